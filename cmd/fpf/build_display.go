@@ -26,6 +26,7 @@ type buildDisplayRow struct {
 	Manager string
 	Package string
 	Desc    string
+	Rank    int
 }
 
 func maybeRunGoBuildDisplay(args []string) (bool, int) {
@@ -190,15 +191,15 @@ func collectRowsForManager(manager string, query string, managerCount int, hasNp
 		return toBuildDisplayRows(manager, rows)
 	}
 	timeout := multiManagerSearchTimeout(manager, query, managerCount)
-	allowBunFallback := allowBunNpmFallback(manager, managerCount, hasNpmManager)
+	allowRegistryFallback := allowNpmRegistryFallback(manager, managerCount, hasNpmManager)
 
 	rows, err := executeSearchEntries(searchInput{
-		Manager:             manager,
-		Query:               effectiveQuery,
-		Limit:               effectiveLimit,
-		NPMSearchLimit:      npmLimit,
-		CommandTimeout:      timeout,
-		AllowBunNPMFallback: allowBunFallback,
+		Manager:                  manager,
+		Query:                    effectiveQuery,
+		Limit:                    effectiveLimit,
+		NPMSearchLimit:           npmLimit,
+		CommandTimeout:           timeout,
+		AllowNPMRegistryFallback: allowRegistryFallback,
 	})
 	if err != nil {
 		if timeout > 0 && errors.Is(err, context.DeadlineExceeded) {
@@ -217,8 +218,23 @@ func collectRowsForManager(manager string, query string, managerCount int, hasNp
 	return toBuildDisplayRows(manager, rows)
 }
 
-func allowBunNpmFallback(manager string, managerCount int, hasNpmManager bool) bool {
-	if manager != "bun" {
+// jsFamilyManagers lists the JavaScript managers in auto-mode preference
+// order. All of them install from the npm registry.
+var jsFamilyManagers = []string{"bun", "npm", "pnpm", "yarn"}
+
+func isJSFamilyManager(manager string) bool {
+	for _, m := range jsFamilyManagers {
+		if m == manager {
+			return true
+		}
+	}
+	return false
+}
+
+// allowNpmRegistryFallback reports whether bun, pnpm, or yarn may run npm's
+// registry search. In multi-manager mode npm already covers it.
+func allowNpmRegistryFallback(manager string, managerCount int, hasNpmManager bool) bool {
+	if !isJSFamilyManager(manager) || manager == "npm" {
 		return true
 	}
 	if managerCount <= 1 {
@@ -250,16 +266,20 @@ func multiManagerSearchTimeout(manager string, query string, managerCount int) t
 	}
 
 	switch manager {
-	case "bun", "npm":
+	case "bun", "npm", "pnpm", "yarn", "cargo", "gem", "mise":
 		if strings.TrimSpace(query) == "" {
 			return 4000 * time.Millisecond
 		}
 		return 10000 * time.Millisecond
-	case "flatpak":
+	case "aur", "flatpak":
 		if strings.TrimSpace(query) == "" {
 			return 15000 * time.Millisecond
 		}
 		return 0
+	case "nix":
+		// nix search evaluates all of nixpkgs on a cold eval cache, with or
+		// without a query.
+		return 15000 * time.Millisecond
 	default:
 		return 0
 	}
@@ -275,7 +295,7 @@ func toBuildDisplayRows(manager string, rows []searchRow) []buildDisplayRow {
 		if desc == "" {
 			desc = "-"
 		}
-		out = append(out, buildDisplayRow{Manager: manager, Package: row.Name, Desc: desc})
+		out = append(out, buildDisplayRow{Manager: manager, Package: row.Name, Desc: desc, Rank: row.Rank})
 	}
 
 	return out
@@ -305,6 +325,37 @@ func cacheRootPath() string {
 	return filepath.Join(os.TempDir(), "fpf-cache")
 }
 
+// cachedBinaryProbe answers a capability check of binary, such as which
+// major version it is, from the cache directory while the binary keeps the
+// same path, size and mtime. Otherwise probe runs and its answer is stored.
+func cachedBinaryProbe(name, binary string, probe func() bool) bool {
+	path, err := exec.LookPath(binary)
+	if err != nil {
+		return false
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return probe()
+	}
+	fingerprint := fmt.Sprintf("%s|%d|%d", path, info.Size(), info.ModTime().UnixNano())
+	metaPath := filepath.Join(cacheRootPath(), "meta", "probe", name+".meta")
+	if raw, err := os.ReadFile(metaPath); err == nil {
+		meta := parseMetaMap(raw)
+		if meta["fingerprint"] == fingerprint {
+			return meta["result"] == "1"
+		}
+	}
+
+	result := probe()
+	value := "0"
+	if result {
+		value = "1"
+	}
+	_ = os.MkdirAll(filepath.Dir(metaPath), 0o755)
+	_ = os.WriteFile(metaPath, []byte("fingerprint="+fingerprint+"\nresult="+value+"\n"), 0o644)
+	return result
+}
+
 func queryCacheEnabledForManager(manager string) bool {
 	if bypass := strings.ToLower(strings.TrimSpace(os.Getenv("FPF_BYPASS_QUERY_CACHE"))); bypass == "1" || bypass == "true" || bypass == "yes" || bypass == "on" {
 		return false
@@ -404,6 +455,9 @@ func loadQueryRowsFromCache(manager, query string, limit, npmLimit int) ([]searc
 	if time.Now().Unix()-createdEpoch > int64(ttl) {
 		return nil, false
 	}
+	if meta["format_version"] != "2" {
+		return nil, false
+	}
 	if meta["fingerprint"] != queryCacheFingerprint(manager, query, limit, npmLimit) {
 		return nil, false
 	}
@@ -418,15 +472,19 @@ func loadQueryRowsFromCache(manager, query string, limit, npmLimit int) ([]searc
 		if strings.TrimSpace(line) == "" {
 			continue
 		}
-		parts := strings.SplitN(line, "\t", 2)
+		parts := strings.SplitN(line, "\t", 3)
 		if len(parts) == 0 || parts[0] == "" {
 			continue
 		}
 		desc := "-"
-		if len(parts) == 2 && strings.TrimSpace(parts[1]) != "" {
+		if len(parts) >= 2 && strings.TrimSpace(parts[1]) != "" {
 			desc = parts[1]
 		}
-		rows = append(rows, searchRow{Name: parts[0], Desc: desc})
+		rank := 0
+		if len(parts) == 3 {
+			rank, _ = strconv.Atoi(parts[2])
+		}
+		rows = append(rows, searchRow{Name: parts[0], Desc: desc, Rank: rank})
 	}
 
 	if len(rows) == 0 {
@@ -466,6 +524,12 @@ func storeQueryRowsToCache(manager, query string, limit, npmLimit int, rows []se
 		b.WriteString(row.Name)
 		b.WriteString("\t")
 		b.WriteString(desc)
+		if row.Rank != 0 {
+			// Manager-ranked rows keep their rank so a cached query orders
+			// the same way as a fresh one.
+			b.WriteString("\t")
+			b.WriteString(strconv.Itoa(row.Rank))
+		}
 		b.WriteString("\n")
 	}
 
@@ -482,7 +546,7 @@ func storeQueryRowsToCache(manager, query string, limit, npmLimit int, rows []se
 
 	meta := strings.Builder{}
 	now := time.Now()
-	meta.WriteString("format_version=1\n")
+	meta.WriteString("format_version=2\n")
 	meta.WriteString("created_at=")
 	meta.WriteString(now.UTC().Format(time.RFC3339))
 	meta.WriteString("\n")
@@ -511,6 +575,16 @@ func queryCacheFingerprint(manager, query string, limit, npmLimit int) string {
 	if cmdPath == "" {
 		cmdPath = "missing"
 	}
+	switch manager {
+	case "apt":
+		// Cached catalog queries go stale with the package databases, not
+		// the binary.
+		cmdPath += "|" + cacheChecksum(aptCatalogFingerprint())
+	case "pacman":
+		cmdPath += "|" + cacheChecksum(pacmanCatalogFingerprint())
+	case "dnf":
+		cmdPath += "|" + cacheChecksum(dnfCatalogFingerprint())
+	}
 	return fmt.Sprintf("3|%s|%s|q=%s|limit=%d|npm=%d|qlim=%s|nqlim=%s", manager, cmdPath, query, limit, npmLimit, os.Getenv("FPF_QUERY_RESULT_LIMIT"), os.Getenv("FPF_NO_QUERY_RESULT_LIMIT"))
 }
 
@@ -534,7 +608,10 @@ func managerCommandForFingerprint(manager string) string {
 	switch manager {
 	case "apt":
 		return "apt-cache"
-	case "brew":
+	case "brew", "brew-tap":
+		if path := brewBinary(); path != "" {
+			return path
+		}
 		return "brew"
 	case "pacman":
 		return "pacman"
@@ -542,7 +619,27 @@ func managerCommandForFingerprint(manager string) string {
 		return "bun"
 	case "flatpak":
 		return "flatpak"
+	case "aur":
+		if helper := aurHelper(); helper != "" {
+			return helper
+		}
+		return "paru"
+	case "pipx":
+		if driver := pipxDriver(); driver != "" {
+			return driver
+		}
+		return "pipx"
+	case "mise":
+		if driver := miseDriver(); driver != "" {
+			return driver
+		}
+		return "mise"
 	default:
+		if m, ok := lookupManager(manager); ok {
+			if binaries := managerBinaries(m); len(binaries) > 0 {
+				return binaries[0]
+			}
+		}
 		return manager
 	}
 }
@@ -562,7 +659,7 @@ func managerSearchConfig(manager string, query string) (string, int, int) {
 	if queryLimit <= 0 {
 		queryLimit = 40
 	}
-	if query != "" && (manager == "npm" || manager == "bun") {
+	if query != "" && isJSFamilyManager(manager) {
 		queryLimit = parseEnvInt("FPF_JS_QUERY_PER_MANAGER_LIMIT", 200)
 		if queryLimit <= 0 {
 			queryLimit = parseEnvInt("FPF_NPM_QUERY_PER_MANAGER_LIMIT", 200)
@@ -579,9 +676,9 @@ func managerSearchConfig(manager string, query string) (string, int, int) {
 			effectiveLimit = lineLimit
 		}
 		switch manager {
-		case "apt", "dnf", "pacman", "zypper", "emerge", "choco", "scoop", "snap":
+		case "apt", "dnf", "pacman", "zypper", "emerge", "apk", "xbps", "choco", "scoop", "snap":
 			effectiveQuery = "a"
-		case "brew", "npm", "bun", "winget":
+		case "brew", "npm", "bun", "pnpm", "yarn", "winget", "nix", "aur", "cargo", "gem":
 			effectiveQuery = "aa"
 		}
 	}
@@ -671,7 +768,7 @@ func applyInstalledMarkers(query string, rows []buildDisplayRow, managers []stri
 	for _, row := range rows {
 		mark := "  "
 		if managerSet, ok := installedMap[row.Manager]; ok {
-			if _, installed := managerSet[row.Package]; installed {
+			if _, installed := managerSet[installedMarkerName(row.Manager, row.Package)]; installed {
 				mark = "* "
 			}
 		}
@@ -682,6 +779,18 @@ func applyInstalledMarkers(query string, rows []buildDisplayRow, managers []stri
 	return out
 }
 
+// installedMarkerName maps a row name onto the name its manager's installed
+// listing uses.
+func installedMarkerName(manager, pkg string) string {
+	switch manager {
+	case "brew":
+		return brewInstalledName(pkg)
+	case "flatpak":
+		return flatpakInstalledName(pkg)
+	}
+	return pkg
+}
+
 func skipNoQueryInstalledMarkers(query string, managers []string) bool {
 	if strings.TrimSpace(query) != "" {
 		return false
@@ -698,19 +807,19 @@ func loadInstalledSet(manager string) map[string]struct{} {
 		return names
 	}
 
-	installed, err := executeInstalledEntries(installedInput{Manager: manager})
+	installed, err := executeInstalledRows(installedInput{Manager: manager})
 	if err != nil {
 		return map[string]struct{}{}
 	}
 
 	names := make(map[string]struct{}, len(installed))
-	for _, name := range installed {
-		if name != "" {
-			names[name] = struct{}{}
+	for _, row := range installed {
+		if row.Name != "" {
+			names[row.Name] = struct{}{}
 		}
 	}
 
-	storeInstalledSetToCache(manager, names)
+	storeInstalledRowsToCache(manager, installed)
 	return names
 }
 
@@ -738,6 +847,15 @@ func installedCachePaths(manager string) (string, string) {
 
 func installedFingerprint(manager string) string {
 	cmd, _ := exec.LookPath(managerCommandForFingerprint(manager))
+	switch manager {
+	case "apt":
+		// dpkg rewrites its status file on every install and removal.
+		return manager + "|" + cmd + "|" + dpkgStatusStamp()
+	case "pacman", "aur":
+		return manager + "|" + cmd + "|" + pacmanLocalStamp()
+	case "flatpak":
+		return manager + "|" + cmd + "|" + flatpakAppDirStamp()
+	}
 	return manager + "|" + cmd
 }
 
@@ -772,9 +890,9 @@ func loadInstalledSetFromCache(manager string) (map[string]struct{}, bool) {
 	}
 	names := map[string]struct{}{}
 	for _, line := range strings.Split(strings.ReplaceAll(string(raw), "\r\n", "\n"), "\n") {
-		line = strings.TrimSpace(line)
-		if line != "" {
-			names[line] = struct{}{}
+		name, _, _ := strings.Cut(line, "\t")
+		if name = strings.TrimSpace(name); name != "" {
+			names[name] = struct{}{}
 		}
 	}
 	if len(names) == 0 {
@@ -783,8 +901,11 @@ func loadInstalledSetFromCache(manager string) (map[string]struct{}, bool) {
 	return names, true
 }
 
-func storeInstalledSetToCache(manager string, names map[string]struct{}) {
-	if !installedCacheEnabled() || len(names) == 0 {
+// storeInstalledRowsToCache writes one "name" or "name<TAB>details" line per
+// installed package, details being whatever the manager reported beyond
+// "installed".
+func storeInstalledRowsToCache(manager string, rows []searchRow) {
+	if !installedCacheEnabled() || len(rows) == 0 {
 		return
 	}
 	cacheFile, metaFile := installedCachePaths(manager)
@@ -792,8 +913,21 @@ func storeInstalledSetToCache(manager string, names map[string]struct{}) {
 		return
 	}
 
-	ordered := make([]string, 0, len(names))
-	for name := range names {
+	details := make(map[string]string, len(rows))
+	for _, row := range rows {
+		if row.Name == "" {
+			continue
+		}
+		if _, seen := details[row.Name]; !seen || details[row.Name] == "" {
+			desc := row.Desc
+			if desc == "installed" {
+				desc = ""
+			}
+			details[row.Name] = desc
+		}
+	}
+	ordered := make([]string, 0, len(details))
+	for name := range details {
 		ordered = append(ordered, name)
 	}
 	sort.Strings(ordered)
@@ -803,7 +937,11 @@ func storeInstalledSetToCache(manager string, names map[string]struct{}) {
 		return
 	}
 	for _, name := range ordered {
-		_, _ = tmp.WriteString(name + "\n")
+		line := name
+		if desc := details[name]; desc != "" {
+			line += "\t" + desc
+		}
+		_, _ = tmp.WriteString(line + "\n")
 	}
 	_ = tmp.Close()
 	if err := os.Rename(tmp.Name(), cacheFile); err != nil {
@@ -813,7 +951,7 @@ func storeInstalledSetToCache(manager string, names map[string]struct{}) {
 
 	meta := strings.Builder{}
 	now := time.Now()
-	meta.WriteString("format_version=1\n")
+	meta.WriteString("format_version=2\n")
 	meta.WriteString("created_at=")
 	meta.WriteString(now.UTC().Format(time.RFC3339))
 	meta.WriteString("\n")
@@ -844,7 +982,7 @@ func rankDisplayRows(query string, rows []buildDisplayRow) []buildDisplayRow {
 
 	rankRows := make([]rankRow, 0, len(rows))
 	for _, row := range rows {
-		rankRows = append(rankRows, rankRow{Manager: row.Manager, Package: row.Package, Desc: row.Desc})
+		rankRows = append(rankRows, rankRow{Manager: row.Manager, Package: row.Package, Desc: row.Desc, Rank: row.Rank})
 	}
 
 	hasExact := false
@@ -883,7 +1021,7 @@ func rankDisplayRows(query string, rows []buildDisplayRow) []buildDisplayRow {
 
 	out := make([]buildDisplayRow, 0, len(scored))
 	for _, item := range scored {
-		out = append(out, buildDisplayRow{Manager: item.Row.Manager, Package: item.Row.Package, Desc: item.Row.Desc})
+		out = append(out, buildDisplayRow{Manager: item.Row.Manager, Package: item.Row.Package, Desc: item.Row.Desc, Rank: item.Row.Rank})
 	}
 	return out
 }
@@ -1,6 +1,8 @@
 package main
 
 import (
+	"reflect"
+	"strings"
 	"testing"
 	"time"
 )
@@ -78,6 +80,77 @@ func TestProcessDisplayRowsAppliesRankingAndLimit(t *testing.T) {
 	}
 }
 
+func TestRankDisplayRowsBlendsManagerRank(t *testing.T) {
+	rows := []buildDisplayRow{
+		{Manager: "apt", Package: "gnome-chess-data", Desc: "data files"},
+		{Manager: "flatpak", Package: "org.gnome.Chess", Desc: "Play chess", Rank: rankFromScore(0)},
+		{Manager: "flatpak", Package: "org.example.Board", Desc: "Board games", Rank: rankFromScore(20)},
+		{Manager: "apt", Package: "board", Desc: "exact"},
+	}
+
+	got := rankDisplayRows("chess", rows)
+	if got[0].Package != "org.gnome.Chess" {
+		t.Fatalf("expected the flatpak exact name match first, got %+v", got)
+	}
+
+	got = rankDisplayRows("board", rows)
+	if got[0].Package != "board" {
+		t.Fatalf("a weak manager rank must not beat an exact package, got %+v", got)
+	}
+}
+
+func TestQueryCacheKeepsManagerRank(t *testing.T) {
+	t.Setenv("FPF_CACHE_DIR", t.TempDir())
+	t.Setenv("FPF_ENABLE_QUERY_CACHE", "1")
+	t.Setenv("FPF_BYPASS_QUERY_CACHE", "")
+	t.Setenv("FPF_SKIP_QUERY_CACHE_WRITE", "")
+	t.Setenv("FPF_QUERY_CACHE_TTL", "300")
+
+	rows := []searchRow{
+		{Name: "org.gnome.Chess", Desc: "Play chess", Rank: rankFromScore(0)},
+		{Name: "gnuchess", Desc: "-"},
+	}
+	storeQueryRowsToCache("flatpak", "chess", 40, 0, rows)
+
+	got, ok := loadQueryRowsFromCache("flatpak", "chess", 40, 0)
+	if !ok {
+		t.Fatal("expected query cache hit")
+	}
+	if !reflect.DeepEqual(got, rows) {
+		t.Fatalf("rows=%+v want %+v", got, rows)
+	}
+}
+
+func TestRankDisplayRowsIgnoresCatalogTags(t *testing.T) {
+	rows := []buildDisplayRow{
+		{Manager: "brew", Package: "aaa", Desc: "[formula homebrew/core] unrelated tool"},
+		{Manager: "apt", Package: "zzzz", Desc: "[main] core utilities"},
+	}
+	got := rankDisplayRows("core", rows)
+	if got[0].Package != "zzzz" {
+		t.Fatalf("expected the row tag not to count as a description match, got %+v", got)
+	}
+}
+
+func TestApplyInstalledMarkersMatchesBareBrewNames(t *testing.T) {
+	bin := t.TempDir()
+	writeMockExecutable(t, bin, "brew", "#!/bin/sh\n[ \"$1\" = list ] && printf 'docker 27.0\\nterraform 1.9\\n'\n")
+	t.Setenv("PATH", bin+":/usr/bin:/bin")
+	t.Setenv("HOMEBREW_PREFIX", "")
+	t.Setenv("FPF_CACHE_DIR", t.TempDir())
+
+	rows := applyInstalledMarkers("docker", []buildDisplayRow{
+		{Manager: "brew", Package: "homebrew/cask/docker", Desc: "[cask homebrew/cask]"},
+		{Manager: "brew", Package: "hashicorp/tap/terraform", Desc: "[formula hashicorp/tap]"},
+		{Manager: "brew", Package: "docker-compose", Desc: "[formula homebrew/core]"},
+	}, []string{"brew"})
+	for i, want := range []string{"* ", "* ", "  "} {
+		if !strings.HasPrefix(rows[i].Desc, want) {
+			t.Fatalf("row %s marker=%q want %q", rows[i].Package, rows[i].Desc[:2], want)
+		}
+	}
+}
+
 func TestRenderBuildDisplayRowsTSVContract(t *testing.T) {
 	rows := []buildDisplayRow{
 		{Manager: "apt", Package: "ripgrep", Desc: "* installed"},
@@ -139,20 +212,20 @@ func TestRankCandidateLimitAndCapRowsForRanking(t *testing.T) {
 }
 
 func TestAllowBunNpmFallback(t *testing.T) {
-	if !allowBunNpmFallback("bun", 1, false) {
+	if !allowNpmRegistryFallback("bun", 1, false) {
 		t.Fatal("expected single-manager bun to allow npm fallback")
 	}
-	if allowBunNpmFallback("bun", 3, true) {
+	if allowNpmRegistryFallback("bun", 3, true) {
 		t.Fatal("expected bun fallback disabled when npm is already in multi-manager set")
 	}
-	if !allowBunNpmFallback("bun", 3, false) {
+	if !allowNpmRegistryFallback("bun", 3, false) {
 		t.Fatal("expected bun fallback enabled when npm is not in multi-manager set")
 	}
 	t.Setenv("FPF_BUN_ALLOW_NPM_FALLBACK_MULTI", "1")
-	if !allowBunNpmFallback("bun", 3, false) {
+	if !allowNpmRegistryFallback("bun", 3, false) {
 		t.Fatal("expected env override to enable bun fallback in multi-manager mode")
 	}
-	if !allowBunNpmFallback("apt", 3, true) {
+	if !allowNpmRegistryFallback("apt", 3, true) {
 		t.Fatal("expected non-bun manager fallback setting to remain enabled")
 	}
 }
@@ -167,6 +240,9 @@ func TestMultiManagerSearchTimeout(t *testing.T) {
 	if got := multiManagerSearchTimeout("flatpak", "", 3); got != 15000*time.Millisecond {
 		t.Fatalf("flatpak no-query timeout=%s want=15000ms", got)
 	}
+	if got := multiManagerSearchTimeout("nix", "ripgrep", 3); got != 15000*time.Millisecond {
+		t.Fatalf("nix query timeout=%s want=15000ms", got)
+	}
 	t.Setenv("FPF_SEARCH_TIMEOUT_BUN_MS", "250")
 	if got := multiManagerSearchTimeout("bun", "ripgrep", 3); got != 250*time.Millisecond {
 		t.Fatalf("bun env override timeout=%s want=250ms", got)
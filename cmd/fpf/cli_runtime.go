@@ -66,10 +66,14 @@ func runCLI(args []string) int {
 		fmt.Fprintln(os.Stderr, "Unable to auto-detect supported package managers. Use --manager.")
 		return 1
 	}
-	managerDisplay := joinManagerLabelsGo(managers)
+	// Labels are only built when shown, since a plugin label costs a
+	// `describe` call that feed searches never need.
+	managerDisplay := func() string {
+		return joinManagerLabelsGo(managers)
+	}
 
 	if input.Action == actionUpdate {
-		if !confirmActionGo(input.AssumeYes, "Run update/upgrade for "+managerDisplay+"?") {
+		if !confirmActionGo(input.AssumeYes, "Run update/upgrade for "+managerDisplay()+"?") {
 			fmt.Fprintln(os.Stderr, "Update canceled")
 			return 0
 		}
@@ -84,7 +88,7 @@ func runCLI(args []string) int {
 	}
 
 	if input.Action == actionRefresh {
-		if !confirmActionGo(input.AssumeYes, "Refresh package catalogs for "+managerDisplay+"?") {
+		if !confirmActionGo(input.AssumeYes, "Refresh package catalogs for "+managerDisplay()+"?") {
 			fmt.Fprintln(os.Stderr, "Refresh canceled")
 			return 0
 		}
@@ -113,9 +117,9 @@ func runCLI(args []string) int {
 			}
 		}
 		if query != "" {
-			fmt.Fprintf(os.Stderr, "No packages found for %s matching '%s'. Try a broader query or --manager.\n", managerDisplay, query)
+			fmt.Fprintf(os.Stderr, "No packages found for %s matching '%s'. Try a broader query or --manager.\n", managerDisplay(), query)
 		} else {
-			fmt.Fprintf(os.Stderr, "No packages found for %s. Try adding a query or using --manager.\n", managerDisplay)
+			fmt.Fprintf(os.Stderr, "No packages found for %s. Try adding a query or using --manager.\n", managerDisplay())
 		}
 		return 1
 	}
@@ -165,11 +169,11 @@ func runCLI(args []string) int {
 	header := "Select package(s)"
 	switch input.Action {
 	case actionSearch:
-		header = "Select package(s) to install with " + managerDisplay + " (TAB to multi-select, * = installed)"
+		header = "Select package(s) to install with " + managerDisplay() + " (TAB to multi-select, * = installed)"
 	case actionList:
-		header = "Select installed package(s) to inspect from " + managerDisplay
+		header = "Select installed package(s) to inspect from " + managerDisplay()
 	case actionRemove:
-		header = "Select installed package(s) to remove from " + managerDisplay
+		header = "Select installed package(s) to remove from " + managerDisplay()
 	}
 
 	helpFile := filepath.Join(tmpDir, "help")
@@ -355,10 +359,16 @@ func parseCLIInput(args []string) (cliInput, error) {
 			input.ManagerOverride = "dnf"
 		case "-pm", "--pacman":
 			input.ManagerOverride = "pacman"
+		case "-au", "--aur":
+			input.ManagerOverride = "aur"
 		case "-zy", "--zypper":
 			input.ManagerOverride = "zypper"
 		case "-em", "--emerge":
 			input.ManagerOverride = "emerge"
+		case "-ak", "--apk":
+			input.ManagerOverride = "apk"
+		case "-xb", "--xbps":
+			input.ManagerOverride = "xbps"
 		case "-br", "--brew":
 			input.ManagerOverride = "brew"
 		case "-wg", "--winget":
@@ -371,10 +381,26 @@ func parseCLIInput(args []string) (cliInput, error) {
 			input.ManagerOverride = "snap"
 		case "-fp", "--flatpak":
 			input.ManagerOverride = "flatpak"
+		case "-nx", "--nix":
+			input.ManagerOverride = "nix"
 		case "-np", "--npm":
 			input.ManagerOverride = "npm"
 		case "-bn", "--bun":
 			input.ManagerOverride = "bun"
+		case "-pn", "--pnpm":
+			input.ManagerOverride = "pnpm"
+		case "-yn", "--yarn":
+			input.ManagerOverride = "yarn"
+		case "-cg", "--cargo":
+			input.ManagerOverride = "cargo"
+		case "-gm", "--gem":
+			input.ManagerOverride = "gem"
+		case "-px", "--pipx":
+			input.ManagerOverride = "pipx"
+		case "-go", "--go":
+			input.ManagerOverride = "go"
+		case "-ms", "--mise":
+			input.ManagerOverride = "mise"
 		case "-ad", "--auto":
 			input.ManagerOverride = ""
 		case "-m", "--manager":
@@ -415,11 +441,22 @@ func resolveManagers(override string, action cliAction, query string) []string {
 		}
 		return nil
 	}
+	if action == actionList || action == actionRemove {
+		// Each JS manager has its own global packages, so installed
+		// listings keep all of them.
+		return detectAutoManagersGo(nil)
+	}
 	includeNpmWithBun := (action == actionSearch || action == actionFeed) && strings.TrimSpace(query) != ""
 	return detectDefaultManagersGo(includeNpmWithBun)
 }
 
 func detectDefaultManagersGo(includeNpmWithBun bool) []string {
+	return detectAutoManagersGo(skippedJSManagers(includeNpmWithBun))
+}
+
+// detectAutoManagersGo lists the ready auto-mode managers, primary first,
+// leaving out the skipped ones.
+func detectAutoManagersGo(skipped map[string]struct{}) []string {
 	primary := detectDefaultManagerGo()
 	out := make([]string, 0)
 	seen := map[string]struct{}{}
@@ -430,17 +467,14 @@ func detectDefaultManagersGo(includeNpmWithBun bool) []string {
 		if _, ok := seen[m]; ok {
 			return
 		}
-		if !isManagerCommandReady(m) {
+		if mgr, ok := lookupManager(m); !ok || managerExplicitOnly(mgr) || !mgr.Ready() {
 			return
 		}
 		seen[m] = struct{}{}
 		out = append(out, m)
 	}
-	add(primary)
-	preferBun := !includeNpmWithBun && isManagerCommandReady("bun")
-	all := []string{"apt", "dnf", "pacman", "zypper", "emerge", "brew", "winget", "choco", "scoop", "snap", "flatpak", "bun", "npm"}
-	for _, m := range all {
-		if preferBun && m == "npm" {
+	for _, m := range append([]string{primary}, registeredManagerNames()...) {
+		if _, skip := skipped[m]; skip {
 			continue
 		}
 		add(m)
@@ -448,26 +482,54 @@ func detectDefaultManagersGo(includeNpmWithBun bool) []string {
 	return out
 }
 
+// skippedJSManagers picks which ready JS managers auto-mode searches leave
+// out. bun, npm, pnpm, and yarn all install from the npm registry, and pnpm
+// and yarn search through npm, so no-query runs keep only the first ready
+// manager in jsFamilyManagers. Query runs keep bun's own search plus one
+// registry searcher.
+func skippedJSManagers(includeRegistrySearch bool) map[string]struct{} {
+	skipped := map[string]struct{}{}
+	keptBun := false
+	keptRegistry := false
+	for _, m := range jsFamilyManagers {
+		if !isManagerCommandReady(m) {
+			continue
+		}
+		switch {
+		case m == "bun":
+			keptBun = true
+		case keptRegistry || (keptBun && !includeRegistrySearch):
+			skipped[m] = struct{}{}
+		default:
+			keptRegistry = true
+		}
+	}
+	return skipped
+}
+
 func detectDefaultManagerGo() string {
 	osName := testableGoOS()
 	if osName == "darwin" && isManagerCommandReady("brew") {
 		return "brew"
 	}
 	if osName == "windows" {
-		for _, m := range []string{"winget", "choco", "scoop", "bun", "npm"} {
+		for _, m := range []string{"winget", "choco", "scoop", "bun", "npm", "pnpm", "yarn"} {
 			if isManagerCommandReady(m) {
 				return m
 			}
 		}
 	}
 	if osName == "linux" {
-		for _, m := range []string{"apt", "dnf", "pacman", "zypper", "emerge", "snap", "flatpak", "bun", "npm"} {
+		if brewPreferredOnLinux() && isManagerCommandReady("brew") {
+			return "brew"
+		}
+		for _, m := range []string{"apt", "dnf", "pacman", "zypper", "emerge", "apk", "xbps", "nix", "snap", "flatpak", "bun", "npm", "pnpm", "yarn"} {
 			if isManagerCommandReady(m) {
 				return m
 			}
 		}
 	}
-	for _, m := range []string{"brew", "winget", "choco", "scoop", "bun", "npm"} {
+	for _, m := range []string{"brew", "winget", "choco", "scoop", "bun", "npm", "pnpm", "yarn"} {
 		if isManagerCommandReady(m) {
 			return m
 		}
@@ -501,15 +563,20 @@ func collectSearchDisplayRowsGo(query string, managers []string) []displayRow {
 func collectInstalledDisplayRowsGo(managers []string) []displayRow {
 	rows := make([]displayRow, 0)
 	for _, manager := range managers {
-		names, err := executeInstalledEntries(installedInput{Manager: manager})
+		installed, err := executeInstalledRows(installedInput{Manager: manager})
 		if err != nil {
 			continue
 		}
-		for _, name := range names {
-			if name == "" {
+		storeInstalledRowsToCache(manager, installed)
+		for _, row := range installed {
+			if row.Name == "" {
 				continue
 			}
-			rows = append(rows, displayRow{Manager: manager, Package: name, Desc: "installed"})
+			desc := row.Desc
+			if desc == "" {
+				desc = "installed"
+			}
+			rows = append(rows, displayRow{Manager: manager, Package: row.Name, Desc: desc})
 		}
 	}
 	sort.Slice(rows, func(i, j int) bool {
@@ -780,8 +847,15 @@ func dynamicReloadManagers(managers []string) []string {
 func defaultDynamicReloadManagers(managers []string) []string {
 
 	slow := map[string]struct{}{
+		"aur":     {},
+		"cargo":   {},
 		"flatpak": {},
+		"gem":     {},
+		"mise":    {},
+		"nix":     {},
 		"npm":     {},
+		"pnpm":    {},
+		"yarn":    {},
 	}
 	fast := make([]string, 0, len(managers))
 	for _, manager := range managers {
@@ -836,13 +910,8 @@ func fzfSupportsResultBindGo() bool {
 }
 
 func managerLabelGo(manager string) string {
-	labels := map[string]string{
-		"apt": "APT", "dnf": "DNF", "pacman": "Pacman", "zypper": "Zypper", "emerge": "Portage (emerge)",
-		"brew": "Homebrew", "winget": "WinGet", "choco": "Chocolatey", "scoop": "Scoop", "snap": "Snap",
-		"flatpak": "Flatpak", "npm": "npm", "bun": "bun",
-	}
-	if label, ok := labels[manager]; ok {
-		return label
+	if m, ok := lookupManager(manager); ok {
+		return m.Label()
 	}
 	return manager
 }
@@ -1057,45 +1126,27 @@ func fzfCommandAvailableGo() bool {
 }
 
 func managerCanInstallFzfGo(manager string) bool {
-	switch manager {
-	case "apt", "dnf", "pacman", "zypper", "emerge", "brew", "winget", "choco", "scoop", "snap":
-		return true
-	default:
+	m, ok := lookupManager(manager)
+	if !ok {
 		return false
 	}
+	_, ok = m.(fzfInstaller)
+	return ok
 }
 
 func installFzfWithManagerGo(manager string) error {
 	if strings.TrimSpace(os.Getenv("FPF_TEST_FZF_MANAGER_INSTALL_FAIL")) == "1" {
 		return fmt.Errorf("forced manager install failure")
 	}
-	switch manager {
-	case "apt":
-		return runRootCommand("apt-get", "install", "-y", "fzf")
-	case "dnf":
-		return runRootCommand("dnf", "install", "-y", "fzf")
-	case "pacman":
-		return runRootCommand("pacman", "-S", "--needed", "fzf")
-	case "zypper":
-		return runRootCommand("zypper", "--non-interactive", "install", "--auto-agree-with-licenses", "fzf")
-	case "emerge":
-		return runRootCommand("emerge", "--ask=n", "app-shells/fzf")
-	case "brew":
-		return runCommand("brew", "install", "fzf")
-	case "winget":
-		if err := runCommand("winget", "install", "--id", "junegunn.fzf", "--exact", "--source", "winget", "--accept-package-agreements", "--accept-source-agreements", "--disable-interactivity"); err != nil {
-			return runCommand("winget", "install", "--id", "fzf", "--exact", "--source", "winget", "--accept-package-agreements", "--accept-source-agreements", "--disable-interactivity")
-		}
-		return nil
-	case "choco":
-		return runCommand("choco", "install", "fzf", "-y")
-	case "scoop":
-		return runCommand("scoop", "install", "fzf")
-	case "snap":
-		return runRootCommand("snap", "install", "fzf")
-	default:
+	m, ok := lookupManager(manager)
+	if !ok {
+		return fmt.Errorf("unsupported manager for fzf install")
+	}
+	installer, ok := m.(fzfInstaller)
+	if !ok {
 		return fmt.Errorf("unsupported manager for fzf install")
 	}
+	return installer.InstallFzf()
 }
 
 func installFzfFromReleaseFallbackGo() bool {
@@ -1134,7 +1185,7 @@ func buildFzfBootstrapCandidatesGo(managers []string) []string {
 	if len(out) > 0 {
 		return out
 	}
-	for _, m := range []string{"apt", "dnf", "pacman", "zypper", "emerge", "brew", "winget", "choco", "scoop", "snap"} {
+	for _, m := range registeredManagerNames() {
 		add(m)
 	}
 	return out
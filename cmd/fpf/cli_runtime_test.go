@@ -26,6 +26,57 @@ func TestResolveManagersQueryAwareNpmPolicy(t *testing.T) {
 	}
 }
 
+func TestResolveManagersDedupesJSFamily(t *testing.T) {
+	mockPath := createMockPath(t, "apt-cache", "apt-get", "dpkg-query", "bun", "npm", "pnpm")
+	t.Setenv("PATH", mockPath)
+	t.Setenv("FPF_TEST_UNAME", "Linux")
+
+	if got := resolveManagers("", actionSearch, ""); sliceContains(got, "npm") || sliceContains(got, "pnpm") {
+		t.Fatalf("expected bun to stand in for npm and pnpm without a query, got %v", got)
+	}
+	withQuery := resolveManagers("", actionSearch, "ripgrep")
+	if !sliceContains(withQuery, "bun") || !sliceContains(withQuery, "npm") || sliceContains(withQuery, "pnpm") {
+		t.Fatalf("expected bun and npm but not pnpm in query manager set, got %v", withQuery)
+	}
+
+	t.Setenv("PATH", createMockPath(t, "apt-cache", "apt-get", "dpkg-query", "pnpm"))
+	if got := resolveManagers("", actionSearch, "ripgrep"); !sliceContains(got, "pnpm") {
+		t.Fatalf("expected pnpm when it is the only JS manager, got %v", got)
+	}
+}
+
+func TestResolveManagersKeepsJSFamilyForInstalled(t *testing.T) {
+	mockPath := createMockPath(t, "apt-cache", "apt-get", "dpkg-query", "bun", "npm", "pnpm")
+	t.Setenv("PATH", mockPath)
+	t.Setenv("FPF_TEST_UNAME", "Linux")
+
+	for _, action := range []cliAction{actionList, actionRemove} {
+		got := resolveManagers("", action, "")
+		if !sliceContains(got, "bun") || !sliceContains(got, "npm") || !sliceContains(got, "pnpm") {
+			t.Fatalf("expected every JS manager for %s, got %v", action, got)
+		}
+	}
+}
+
+func TestResolveManagersLeavesLanguageManagersExplicit(t *testing.T) {
+	mockPath := createMockPath(t, "apt-cache", "apt-get", "dpkg-query", "cargo", "gem", "pipx", "go", "mise")
+	t.Setenv("PATH", mockPath)
+	t.Setenv("FPF_TEST_UNAME", "Linux")
+
+	got := resolveManagers("", actionSearch, "ripgrep")
+	for _, manager := range []string{"cargo", "gem", "pipx", "go", "mise"} {
+		if sliceContains(got, manager) {
+			t.Fatalf("expected %s to stay out of auto mode, got %v", manager, got)
+		}
+		if explicit := resolveManagers(manager, actionSearch, "ripgrep"); len(explicit) != 1 || explicit[0] != manager {
+			t.Fatalf("expected --manager %s to select it, got %v", manager, explicit)
+		}
+	}
+	if !sliceContains(got, "apt") {
+		t.Fatalf("expected apt in auto mode, got %v", got)
+	}
+}
+
 func sliceContains(items []string, target string) bool {
 	for _, item := range items {
 		if item == target {
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"io"
+	"os"
+	"os/exec"
+)
+
+// openDecompressed opens path and decompresses it according to its magic
+// bytes; files in no known format are returned as they are. gzip, bzip2 and
+// lz4 are handled in-process. zstd and xz go through their command line
+// tools, which the package managers that use them depend on, and zchunk
+// through unzck.
+func openDecompressed(path string) (io.ReadCloser, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	magic := make([]byte, 6)
+	n, _ := io.ReadFull(file, magic)
+	magic = magic[:n]
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	switch {
+	case bytes.HasPrefix(magic, []byte{0x1f, 0x8b}):
+		gz, err := gzip.NewReader(file)
+		if err != nil {
+			file.Close()
+			return nil, err
+		}
+		return readCloser{Reader: gz, close: func() error { gz.Close(); return file.Close() }}, nil
+	case bytes.HasPrefix(magic, []byte("BZh")):
+		return readCloser{Reader: bzip2.NewReader(file), close: file.Close}, nil
+	case bytes.HasPrefix(magic, []byte{0x04, 0x22, 0x4d, 0x18}):
+		return readCloser{Reader: newLZ4Reader(file), close: file.Close}, nil
+	case bytes.HasPrefix(magic, []byte{0x28, 0xb5, 0x2f, 0xfd}):
+		file.Close()
+		return commandReader("zstd", "-dcq", "--", path)
+	case bytes.HasPrefix(magic, []byte{0xfd, '7', 'z', 'X', 'Z', 0x00}):
+		file.Close()
+		return commandReader("xz", "-dcq", "--", path)
+	case bytes.HasPrefix(magic, []byte("\x00ZCK1")):
+		file.Close()
+		return commandReader("unzck", "--stdout", path)
+	default:
+		return file, nil
+	}
+}
+
+type readCloser struct {
+	io.Reader
+	close func() error
+}
+
+func (r readCloser) Close() error {
+	return r.close()
+}
+
+// commandReader streams a command's stdout; closing it waits for the
+// command to exit.
+func commandReader(name string, args ...string) (io.ReadCloser, error) {
+	cmd := exec.Command(name, args...)
+	cmd.Env = os.Environ()
+	cmd.Stderr = ioDiscard{}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	return readCloser{Reader: stdout, close: func() error {
+		_, _ = io.Copy(io.Discard, stdout)
+		return cmd.Wait()
+	}}, nil
+}
@@ -17,6 +17,7 @@ type rankRow struct {
 	Manager string
 	Package string
 	Desc    string
+	Rank    int
 }
 
 type rankScore struct {
@@ -204,12 +205,13 @@ func scoreRows(query string, hasExact bool, rows []rankRow) []rankScore {
 	queryTokens := splitAlphaNumTokens(q)
 	queryTokenCount := len(queryTokens)
 	penaltyRe := regexp.MustCompile(`(plugin|template|starter|boilerplate|router|hooks?|mcp|integration)`)
+	preferBrew := testableGoOS() == "linux" && brewPreferredOnLinux()
 
 	scored := make([]rankScore, 0, len(rows))
 	for _, row := range rows {
 		mgr := strings.ToLower(row.Manager)
 		pkg := row.Package
-		desc := row.Desc
+		desc := catalogDescText(row.Desc)
 
 		pkgLower := strings.ToLower(pkg)
 		descLower := strings.ToLower(desc)
@@ -249,6 +251,10 @@ func scoreRows(query string, hasExact bool, rows []rankRow) []rankScore {
 			}
 		}
 
+		if row.Rank > 0 && row.Rank-1 < score {
+			score = row.Rank - 1
+		}
+
 		if q != "" && hasExact && pkgTokenHits == queryTokenCount && queryTokenCount > 0 && pkgTokenCount > queryTokenCount {
 			score += 5
 		}
@@ -259,10 +265,14 @@ func scoreRows(query string, hasExact bool, rows []rankRow) []rankScore {
 
 		managerBias := 0
 		switch mgr {
-		case "npm":
+		case "npm", "pnpm", "yarn":
 			managerBias = 4
 		case "bun":
 			managerBias = 3
+		case "brew":
+			if preferBrew {
+				managerBias = -1
+			}
 		}
 
 		scored = append(scored, rankScore{
@@ -279,6 +289,12 @@ func scoreRows(query string, hasExact bool, rows []rankRow) []rankScore {
 	return scored
 }
 
+// rankFromScore records a manager's own match score, on the scale scoreRows
+// uses, as a row Rank. scoreRows keeps whichever of the two scores is better.
+func rankFromScore(score int) int {
+	return score + 1
+}
+
 func normalizeAlphaNum(value string) string {
 	var b strings.Builder
 	for _, r := range value {
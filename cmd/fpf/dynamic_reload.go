@@ -3,7 +3,6 @@ package main
 import (
 	"fmt"
 	"os"
-	"os/exec"
 	"strconv"
 	"strings"
 	"time"
@@ -183,58 +182,14 @@ func parseEnvFloat(name string, fallback float64) float64 {
 }
 
 func isManagerSupported(manager string) bool {
-	switch manager {
-	case "apt", "dnf", "pacman", "zypper", "emerge", "brew", "winget", "choco", "scoop", "snap", "flatpak", "npm", "bun":
-		return true
-	default:
-		return false
-	}
+	_, ok := lookupManager(manager)
+	return ok
 }
 
 func isManagerCommandReady(manager string) bool {
-	var binaries []string
-	match := true
-
-	switch manager {
-	case "apt":
-		binaries = []string{"apt-cache", "apt-get", "dpkg-query"}
-	case "dnf":
-		binaries = []string{"dnf"}
-	case "pacman":
-		binaries = []string{"pacman"}
-	case "zypper":
-		binaries = []string{"zypper"}
-	case "emerge":
-		binaries = []string{"emerge"}
-	case "brew":
-		binaries = []string{"brew"}
-	case "winget":
-		binaries = []string{"winget"}
-	case "choco":
-		binaries = []string{"choco"}
-	case "scoop":
-		binaries = []string{"scoop"}
-	case "snap":
-		binaries = []string{"snap"}
-	case "flatpak":
-		binaries = []string{"flatpak"}
-	case "npm":
-		binaries = []string{"npm"}
-	case "bun":
-		binaries = []string{"bun"}
-	default:
-		match = false
-	}
-
-	if !match {
+	m, ok := lookupManager(manager)
+	if !ok {
 		return false
 	}
-
-	for _, binary := range binaries {
-		if _, err := exec.LookPath(binary); err != nil {
-			return false
-		}
-	}
-
-	return true
+	return m.Ready()
 }
@@ -0,0 +1,228 @@
+package main
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// lz4 frame constants; see the LZ4 frame format description.
+const (
+	lz4FrameMagic         = 0x184D2204
+	lz4SkippableMagicMask = 0xFFFFFFF0
+	lz4SkippableMagic     = 0x184D2A50
+	lz4WindowSize         = 64 * 1024
+)
+
+var errLZ4Corrupt = errors.New("lz4: corrupt input")
+
+// lz4Reader decompresses LZ4 frames, the format apt uses for
+// Acquire::GzipIndexes lists on Debian and Ubuntu. Only the subset apt
+// writes is needed, but any valid frame without a dictionary decodes.
+type lz4Reader struct {
+	r               *bufio.Reader
+	window          []byte
+	pending         []byte
+	inFrame         bool
+	blockChecksum   bool
+	contentChecksum bool
+}
+
+func newLZ4Reader(r io.Reader) *lz4Reader {
+	return &lz4Reader{r: bufio.NewReader(r)}
+}
+
+func (z *lz4Reader) Read(p []byte) (int, error) {
+	for len(z.pending) == 0 {
+		if err := z.nextBlock(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, z.pending)
+	z.pending = z.pending[n:]
+	return n, nil
+}
+
+// nextBlock decodes the next data block into the window, reading frame
+// headers and end marks on the way. It returns io.EOF after the last frame.
+func (z *lz4Reader) nextBlock() error {
+	for {
+		if !z.inFrame {
+			if err := z.readFrameHeader(); err != nil {
+				return err
+			}
+			continue
+		}
+
+		var size uint32
+		if err := binary.Read(z.r, binary.LittleEndian, &size); err != nil {
+			return lz4Unexpected(err)
+		}
+		if size == 0 {
+			z.inFrame = false
+			if z.contentChecksum {
+				if _, err := z.r.Discard(4); err != nil {
+					return lz4Unexpected(err)
+				}
+			}
+			continue
+		}
+
+		uncompressed := size&0x80000000 != 0
+		size &= 0x7FFFFFFF
+		block := make([]byte, size)
+		if _, err := io.ReadFull(z.r, block); err != nil {
+			return lz4Unexpected(err)
+		}
+		if z.blockChecksum {
+			if _, err := z.r.Discard(4); err != nil {
+				return lz4Unexpected(err)
+			}
+		}
+
+		if len(z.window) > lz4WindowSize {
+			z.window = append(z.window[:0], z.window[len(z.window)-lz4WindowSize:]...)
+		}
+		start := len(z.window)
+		if uncompressed {
+			z.window = append(z.window, block...)
+		} else {
+			window, err := lz4DecodeBlock(z.window, block)
+			if err != nil {
+				return err
+			}
+			z.window = window
+		}
+		z.pending = z.window[start:]
+		if len(z.pending) > 0 {
+			return nil
+		}
+	}
+}
+
+func (z *lz4Reader) readFrameHeader() error {
+	var magic uint32
+	if err := binary.Read(z.r, binary.LittleEndian, &magic); err != nil {
+		if err == io.EOF {
+			return io.EOF
+		}
+		return lz4Unexpected(err)
+	}
+	if magic&lz4SkippableMagicMask == lz4SkippableMagic {
+		var size uint32
+		if err := binary.Read(z.r, binary.LittleEndian, &size); err != nil {
+			return lz4Unexpected(err)
+		}
+		if _, err := z.r.Discard(int(size)); err != nil {
+			return lz4Unexpected(err)
+		}
+		return nil
+	}
+	if magic != lz4FrameMagic {
+		return fmt.Errorf("lz4: bad frame magic %#x", magic)
+	}
+
+	var descriptor [2]byte
+	if _, err := io.ReadFull(z.r, descriptor[:]); err != nil {
+		return lz4Unexpected(err)
+	}
+	flags := descriptor[0]
+	if flags>>6 != 1 {
+		return fmt.Errorf("lz4: unsupported frame version %d", flags>>6)
+	}
+	if flags&0x01 != 0 {
+		return errors.New("lz4: dictionary frames are not supported")
+	}
+	z.blockChecksum = flags&0x10 != 0
+	z.contentChecksum = flags&0x04 != 0
+
+	skip := 1 // header checksum
+	if flags&0x08 != 0 {
+		skip += 8 // content size
+	}
+	if _, err := z.r.Discard(skip); err != nil {
+		return lz4Unexpected(err)
+	}
+	z.inFrame = true
+	z.window = z.window[:0]
+	return nil
+}
+
+// lz4DecodeBlock appends the decompressed block src to dst. Matches may
+// reach back into dst, which holds the previous blocks of a linked frame.
+func lz4DecodeBlock(dst, src []byte) ([]byte, error) {
+	i := 0
+	for i < len(src) {
+		token := src[i]
+		i++
+
+		literals := int(token >> 4)
+		if literals == 15 {
+			n, next, err := lz4ReadLength(src, i)
+			if err != nil {
+				return dst, err
+			}
+			literals += n
+			i = next
+		}
+		if i+literals > len(src) {
+			return dst, errLZ4Corrupt
+		}
+		dst = append(dst, src[i:i+literals]...)
+		i += literals
+		if i == len(src) {
+			break
+		}
+
+		if i+2 > len(src) {
+			return dst, errLZ4Corrupt
+		}
+		offset := int(src[i]) | int(src[i+1])<<8
+		i += 2
+		if offset == 0 || offset > len(dst) {
+			return dst, errLZ4Corrupt
+		}
+
+		match := int(token & 0x0F)
+		if match == 15 {
+			n, next, err := lz4ReadLength(src, i)
+			if err != nil {
+				return dst, err
+			}
+			match += n
+			i = next
+		}
+		match += 4
+
+		from := len(dst) - offset
+		for k := 0; k < match; k++ {
+			dst = append(dst, dst[from+k])
+		}
+	}
+	return dst, nil
+}
+
+// lz4ReadLength reads the 255-continued length extension at src[i:].
+func lz4ReadLength(src []byte, i int) (int, int, error) {
+	n := 0
+	for {
+		if i >= len(src) {
+			return 0, i, errLZ4Corrupt
+		}
+		b := src[i]
+		i++
+		n += int(b)
+		if b != 255 {
+			return n, i, nil
+		}
+	}
+}
+
+func lz4Unexpected(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+)
+
+// lz4FixtureCorpus is the text compressed twice into lz4-frames.lz4: once
+// with linked 64 KiB blocks, block checksums, content size and content
+// checksum (`lz4 -B4 -BD -BX --content-size`), once with independent blocks
+// and no checksum (`lz4 -B4 --no-frame-crc`).
+func lz4FixtureCorpus() []byte {
+	var b bytes.Buffer
+	for i := 0; i < 900; i++ {
+		fmt.Fprintf(&b, "Package: pkg-%d\nVersion: 1.%d-%d\nDescription: synthetic package number %d\n\n", i, i%7, i%13, i)
+	}
+	return b.Bytes()
+}
+
+// lz4StoredFrame wraps data in an LZ4 frame of uncompressed blocks.
+func lz4StoredFrame(data []byte) []byte {
+	var frame bytes.Buffer
+	frame.Write([]byte{0x04, 0x22, 0x4D, 0x18, 0x60, 0x40, 0x00})
+	for len(data) > 0 {
+		n := len(data)
+		if n > lz4WindowSize {
+			n = lz4WindowSize
+		}
+		_ = binary.Write(&frame, binary.LittleEndian, uint32(n)|0x80000000)
+		frame.Write(data[:n])
+		data = data[n:]
+	}
+	frame.Write([]byte{0x00, 0x00, 0x00, 0x00})
+	return frame.Bytes()
+}
+
+func TestLZ4ReaderDecodesFrames(t *testing.T) {
+	var frame bytes.Buffer
+	// Frame one: block checksums and a content size, one compressed block
+	// with a back reference and one stored block.
+	frame.Write([]byte{0x04, 0x22, 0x4D, 0x18, 0x78, 0x40})
+	frame.Write(make([]byte, 8))
+	frame.WriteByte(0x00)
+	frame.Write([]byte{0x09, 0x00, 0x00, 0x00, 0x44, 'a', 'b', 'c', 'd', 0x04, 0x00, 0x10, '!'})
+	frame.Write(make([]byte, 4))
+	frame.Write([]byte{0x03, 0x00, 0x00, 0x80, 'x', 'y', 'z'})
+	frame.Write(make([]byte, 4))
+	frame.Write([]byte{0x00, 0x00, 0x00, 0x00})
+	// A skippable frame between the two data frames.
+	frame.Write([]byte{0x50, 0x2A, 0x4D, 0x18, 0x02, 0x00, 0x00, 0x00, 0xFF, 0xFF})
+	// Frame two: a literal run long enough to need a length extension.
+	frame.Write([]byte{0x04, 0x22, 0x4D, 0x18, 0x40, 0x40, 0x00})
+	frame.Write([]byte{0x16, 0x00, 0x00, 0x00, 0xF0, 0x05})
+	frame.WriteString("0123456789abcdefghij")
+	frame.Write([]byte{0x00, 0x00, 0x00, 0x00})
+
+	got, err := io.ReadAll(newLZ4Reader(&frame))
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	want := "abcdabcdabcd!xyz0123456789abcdefghij"
+	if string(got) != want {
+		t.Fatalf("got %q want %q", got, want)
+	}
+}
+
+func TestLZ4ReaderRejectsBadOffsets(t *testing.T) {
+	raw := []byte{0x04, 0x22, 0x4D, 0x18, 0x60, 0x40, 0x00, 0x05, 0x00, 0x00, 0x00, 0x10, 'a', 0x09, 0x00, 0x00}
+	if _, err := io.ReadAll(newLZ4Reader(bytes.NewReader(raw))); err == nil {
+		t.Fatal("expected an error for a match reaching before the output")
+	}
+}
+
+func TestLZ4ReaderDecodesLinkedBlocksAndChecksums(t *testing.T) {
+	raw := readFixture(t, "lz4-frames.lz4")
+	if flags := raw[4]; flags&0x20 != 0 || flags&0x1C != 0x1C {
+		t.Fatalf("fixture frame flags %#x should be linked blocks with both checksums and a content size", flags)
+	}
+	got, err := io.ReadAll(newLZ4Reader(bytes.NewReader(raw)))
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	corpus := lz4FixtureCorpus()
+	if want := append(append([]byte{}, corpus...), corpus...); !bytes.Equal(got, want) {
+		t.Fatalf("decoded %d bytes, want %d matching the corpus twice", len(got), len(want))
+	}
+
+	stored, err := io.ReadAll(newLZ4Reader(bytes.NewReader(lz4StoredFrame(corpus))))
+	if err != nil || !bytes.Equal(stored, corpus) {
+		t.Fatalf("stored frame: %d bytes, %v", len(stored), err)
+	}
+}
+
+func TestLZ4ReaderRejectsCorruptInput(t *testing.T) {
+	fixture := readFixture(t, "lz4-frames.lz4")
+	header := []byte{0x04, 0x22, 0x4D, 0x18}
+	stored := lz4StoredFrame([]byte("abc"))
+	cases := map[string][]byte{
+		"bad magic":            append([]byte{0x05, 0x22, 0x4D, 0x18}, fixture[4:]...),
+		"unsupported version":  append(append([]byte{}, header...), 0x80, 0x40, 0x00),
+		"dictionary frame":     append(append([]byte{}, header...), 0x61, 0x40, 0x00),
+		"truncated header":     fixture[:5],
+		"truncated block":      fixture[:500],
+		"missing end mark":     stored[:len(stored)-4],
+		"literals past block":  append(append([]byte{}, header...), 0x60, 0x40, 0x00, 0x02, 0x00, 0x00, 0x00, 0x50, 'a', 0x00, 0x00, 0x00, 0x00),
+		"zero match offset":    append(append([]byte{}, header...), 0x60, 0x40, 0x00, 0x04, 0x00, 0x00, 0x00, 0x10, 'a', 0x00, 0x00, 0x00, 0x00, 0x00, 0x00),
+		"unterminated length":  append(append([]byte{}, header...), 0x60, 0x40, 0x00, 0x02, 0x00, 0x00, 0x00, 0xF0, 0xFF, 0x00, 0x00, 0x00, 0x00),
+		"truncated skip frame": {0x50, 0x2A, 0x4D, 0x18, 0x10, 0x00, 0x00, 0x00, 0xFF},
+	}
+	for name, raw := range cases {
+		_, err := io.ReadAll(newLZ4Reader(bytes.NewReader(raw)))
+		if err == nil {
+			t.Fatalf("%s: expected an error", name)
+		}
+		if name == "missing end mark" && !errors.Is(err, io.ErrUnexpectedEOF) {
+			t.Fatalf("%s: got %v want unexpected EOF", name, err)
+		}
+	}
+}
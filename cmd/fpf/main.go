@@ -133,12 +133,30 @@ func normalizeManagerName(value string) string {
 	switch manager {
 	case "homebrew":
 		return "brew"
+	case "tap", "taps", "brew tap", "brew-taps":
+		return "brew-tap"
 	case "chocolatey", "chocolate":
 		return "choco"
 	case "portage (emerge)", "portage-emerge", "portage":
 		return "emerge"
 	case "win-get":
 		return "winget"
+	case "alpine", "alpine (apk)":
+		return "apk"
+	case "void", "void (xbps)", "xbps-install":
+		return "xbps"
+	case "paru", "yay":
+		return "aur"
+	case "uv", "uv tool", "uv-tool":
+		return "pipx"
+	case "rubygems", "ruby", "gems":
+		return "gem"
+	case "golang", "go install":
+		return "go"
+	case "asdf", "rtx":
+		return "mise"
+	case "yarnpkg", "yarn classic":
+		return "yarn"
 	default:
 		return manager
 	}
@@ -301,271 +319,24 @@ func parseManagerActionInput(args []string) (managerActionInput, bool, error) {
 }
 
 func executeManagerAction(input managerActionInput) error {
-	manager := input.Manager
-	action := input.Action
-	pkgs := input.Packages
-
-	switch manager {
-	case "apt":
-		switch action {
+	manager, ok := lookupManager(input.Manager)
+	if ok {
+		pkgs := input.Packages
+		switch input.Action {
 		case "install":
-			return runRootCommand("apt-get", append([]string{"install", "-y"}, pkgs...)...)
+			return manager.Install(pkgs)
 		case "remove":
-			return runRootCommand("apt-get", append([]string{"remove", "-y"}, pkgs...)...)
+			return manager.Remove(pkgs)
 		case "show_info":
-			pkg := firstPackage(pkgs)
-			runCommandQuietErr("apt-cache", "show", pkg)
-			fmt.Println()
-			runCommandQuietErr("dpkg", "-L", pkg)
-			return nil
+			return manager.ShowInfo(firstPackage(pkgs))
 		case "update":
-			if err := runRootCommand("apt-get", "update"); err != nil {
-				return err
-			}
-			return runRootCommand("apt-get", "upgrade", "-y")
+			return manager.Update()
 		case "refresh":
-			return runRootCommand("apt-get", "update")
-		}
-	case "dnf":
-		switch action {
-		case "install":
-			return runRootCommand("dnf", append([]string{"install", "-y"}, pkgs...)...)
-		case "remove":
-			return runRootCommand("dnf", append([]string{"remove", "-y"}, pkgs...)...)
-		case "show_info":
-			pkg := firstPackage(pkgs)
-			runCommandQuietErr("dnf", "info", pkg)
-			fmt.Println()
-			runCommandQuietErr("rpm", "-ql", pkg)
-			return nil
-		case "update":
-			return runRootCommand("dnf", "upgrade", "-y")
-		case "refresh":
-			return runRootCommand("dnf", "makecache")
-		}
-	case "pacman":
-		switch action {
-		case "install":
-			return runRootCommand("pacman", append([]string{"-S", "--needed"}, pkgs...)...)
-		case "remove":
-			return runRootCommand("pacman", append([]string{"-Rsn"}, pkgs...)...)
-		case "show_info":
-			pkg := firstPackage(pkgs)
-			if err := runCommandQuietErr("pacman", "-Qi", pkg); err != nil {
-				runCommandQuietErr("pacman", "-Si", pkg)
-			}
-			fmt.Println()
-			runCommandQuietErr("pacman", "-Ql", pkg)
-			return nil
-		case "update":
-			return runRootCommand("pacman", "-Syu")
-		case "refresh":
-			return runRootCommand("pacman", "-Sy")
-		}
-	case "zypper":
-		switch action {
-		case "install":
-			return runRootCommand("zypper", append([]string{"--non-interactive", "install", "--auto-agree-with-licenses"}, pkgs...)...)
-		case "remove":
-			return runRootCommand("zypper", append([]string{"--non-interactive", "remove"}, pkgs...)...)
-		case "show_info":
-			return runCommandQuietErr("zypper", "--non-interactive", "info", firstPackage(pkgs))
-		case "update":
-			if err := runRootCommand("zypper", "--non-interactive", "refresh"); err != nil {
-				return err
-			}
-			return runRootCommand("zypper", "--non-interactive", "update")
-		case "refresh":
-			return runRootCommand("zypper", "--non-interactive", "refresh")
-		}
-	case "emerge":
-		switch action {
-		case "install":
-			return runRootCommand("emerge", append([]string{"--ask=n", "--verbose"}, pkgs...)...)
-		case "remove":
-			if err := runRootCommand("emerge", append([]string{"--ask=n", "--deselect"}, pkgs...)...); err != nil {
-				return err
-			}
-			return runRootCommand("emerge", append([]string{"--ask=n", "--depclean"}, pkgs...)...)
-		case "show_info":
-			return runCommandQuietErr("emerge", "--search", "--color=n", firstPackage(pkgs))
-		case "update":
-			if err := runRootCommand("emerge", "--sync"); err != nil {
-				return err
-			}
-			return runRootCommand("emerge", "--ask=n", "--update", "--deep", "--newuse", "@world")
-		case "refresh":
-			return runRootCommand("emerge", "--sync")
-		}
-	case "brew":
-		switch action {
-		case "install":
-			return runCommand("brew", append([]string{"install"}, pkgs...)...)
-		case "remove":
-			return runCommand("brew", append([]string{"uninstall"}, pkgs...)...)
-		case "show_info":
-			return runCommandQuietErr("brew", "info", firstPackage(pkgs))
-		case "update":
-			if err := runCommand("brew", "update"); err != nil {
-				return err
-			}
-			return runCommand("brew", "upgrade")
-		case "refresh":
-			return runCommand("brew", "update")
-		}
-	case "winget":
-		switch action {
-		case "install":
-			for _, pkg := range pkgs {
-				if err := runCommand("winget", "install", "--id", pkg, "--exact", "--source", "winget", "--accept-package-agreements", "--accept-source-agreements", "--disable-interactivity"); err != nil {
-					return err
-				}
-			}
-			return nil
-		case "remove":
-			for _, pkg := range pkgs {
-				if err := runCommand("winget", "uninstall", "--id", pkg, "--exact", "--source", "winget", "--disable-interactivity"); err != nil {
-					return err
-				}
-			}
-			return nil
-		case "show_info":
-			return runCommandQuietErr("winget", "show", "--id", firstPackage(pkgs), "--exact", "--source", "winget", "--accept-source-agreements", "--disable-interactivity")
-		case "update":
-			return runCommand("winget", "upgrade", "--all", "--source", "winget", "--accept-package-agreements", "--accept-source-agreements", "--disable-interactivity")
-		case "refresh":
-			return runCommand("winget", "source", "update", "--name", "winget", "--accept-source-agreements", "--disable-interactivity")
-		}
-	case "choco":
-		switch action {
-		case "install":
-			return runCommand("choco", append([]string{"install"}, append(pkgs, "-y")...)...)
-		case "remove":
-			return runCommand("choco", append([]string{"uninstall"}, append(pkgs, "-y")...)...)
-		case "show_info":
-			return runCommandQuietErr("choco", "info", firstPackage(pkgs))
-		case "update":
-			return runCommand("choco", "upgrade", "all", "-y")
-		case "refresh":
-			cmd := exec.Command("choco", "source", "list", "--limit-output")
-			cmd.Env = os.Environ()
-			cmd.Stdin = os.Stdin
-			cmd.Stdout = io.Discard
-			cmd.Stderr = os.Stderr
-			return cmd.Run()
-		}
-	case "scoop":
-		switch action {
-		case "install":
-			return runCommand("scoop", append([]string{"install"}, pkgs...)...)
-		case "remove":
-			return runCommand("scoop", append([]string{"uninstall"}, pkgs...)...)
-		case "show_info":
-			return runCommandQuietErr("scoop", "info", firstPackage(pkgs))
-		case "update":
-			if err := runCommand("scoop", "update"); err != nil {
-				return err
-			}
-			return runCommand("scoop", "update", "*")
-		case "refresh":
-			return runCommand("scoop", "update")
-		}
-	case "snap":
-		switch action {
-		case "install":
-			for _, pkg := range pkgs {
-				if err := runRootCommandQuietErr("snap", "install", pkg); err != nil {
-					if err2 := runRootCommand("snap", "install", "--classic", pkg); err2 != nil {
-						return err2
-					}
-				}
-			}
-			return nil
-		case "remove":
-			return runRootCommand("snap", append([]string{"remove"}, pkgs...)...)
-		case "show_info":
-			return runCommandQuietErr("snap", "info", firstPackage(pkgs))
-		case "update":
-			return runRootCommand("snap", "refresh")
-		case "refresh":
-			return runRootCommand("snap", "refresh", "--list")
-		}
-	case "flatpak":
-		switch action {
-		case "install":
-			for _, pkg := range pkgs {
-				if err := runCommandQuietErr("flatpak", "install", "-y", "--user", "flathub", pkg); err == nil {
-					continue
-				}
-				if err := runCommandQuietErr("flatpak", "install", "-y", "--user", pkg); err == nil {
-					continue
-				}
-				if err := runRootCommandQuietErr("flatpak", "install", "-y", "flathub", pkg); err == nil {
-					continue
-				}
-				if err := runRootCommand("flatpak", "install", "-y", pkg); err != nil {
-					return err
-				}
-			}
-			return nil
-		case "remove":
-			if err := runCommandQuietErr("flatpak", append([]string{"uninstall", "-y", "--user"}, pkgs...)...); err != nil {
-				return runRootCommand("flatpak", append([]string{"uninstall", "-y"}, pkgs...)...)
-			}
-			return nil
-		case "show_info":
-			if err := runCommandQuietErr("flatpak", "info", firstPackage(pkgs)); err != nil {
-				return runCommandQuietErr("flatpak", "remote-info", "flathub", firstPackage(pkgs))
-			}
-			return nil
-		case "update":
-			if err := runCommandQuietErr("flatpak", "update", "-y", "--user"); err != nil {
-				return runRootCommand("flatpak", "update", "-y")
-			}
-			return nil
-		case "refresh":
-			if err := runCommandQuietErr("flatpak", "update", "-y", "--appstream", "--user"); err != nil {
-				return runRootCommand("flatpak", "update", "-y", "--appstream")
-			}
-			return nil
-		}
-	case "npm":
-		switch action {
-		case "install":
-			return runCommand("npm", append([]string{"install", "-g"}, pkgs...)...)
-		case "remove":
-			return runCommand("npm", append([]string{"uninstall", "-g"}, pkgs...)...)
-		case "show_info":
-			return runCommandQuietErr("npm", "view", firstPackage(pkgs))
-		case "update":
-			return runCommand("npm", "update", "-g")
-		case "refresh":
-			return runCommand("npm", "cache", "verify")
-		}
-	case "bun":
-		switch action {
-		case "install":
-			return runCommand("bun", append([]string{"add", "-g"}, pkgs...)...)
-		case "remove":
-			return runCommand("bun", append([]string{"remove", "--global"}, pkgs...)...)
-		case "show_info":
-			if err := runCommandQuietErr("bun", "info", firstPackage(pkgs)); err != nil {
-				return runCommandQuietErr("npm", "view", firstPackage(pkgs))
-			}
-			return nil
-		case "update":
-			return runCommand("bun", "update", "--global")
-		case "refresh":
-			cmd := exec.Command("bun", "pm", "cache")
-			cmd.Env = os.Environ()
-			cmd.Stdin = os.Stdin
-			cmd.Stdout = io.Discard
-			cmd.Stderr = os.Stderr
-			return cmd.Run()
+			return manager.Refresh()
 		}
 	}
 
-	return fmt.Errorf("unsupported manager action: manager=%s action=%s", manager, action)
+	return fmt.Errorf("unsupported manager action: manager=%s action=%s", input.Manager, input.Action)
 }
 
 func firstPackage(pkgs []string) string {
@@ -616,12 +387,17 @@ func runRootCommandQuietErr(name string, args ...string) error {
 }
 
 func needsRoot(managerBinary string) bool {
-	switch managerBinary {
-	case "apt-get", "dnf", "pacman", "zypper", "emerge", "snap":
-		return true
-	default:
-		return false
+	for _, manager := range registeredManagers() {
+		if !manager.NeedsRoot() {
+			continue
+		}
+		for _, binary := range managerBinaries(manager) {
+			if binary == managerBinary {
+				return true
+			}
+		}
 	}
+	return false
 }
 
 func maybeRunPreviewItemAction(args []string) (bool, int) {
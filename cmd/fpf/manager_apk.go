@@ -0,0 +1,144 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"strings"
+)
+
+type apkManager struct {
+	managerSpec
+}
+
+func init() {
+	registerManager(apkManager{managerSpec{
+		name:     "apk",
+		label:    "Alpine (apk)",
+		order:    55,
+		binaries: []string{"apk"},
+		root:     true,
+	}})
+}
+
+var apkInstalledDBPath = "/lib/apk/db/installed"
+
+func (apkManager) Search(input searchInput) ([]searchRow, error) {
+	out, err := input.runOutput("apk", "search", "-v", "-d", input.Query)
+	if err != nil {
+		return nil, err
+	}
+	return parseApkSearch(out), nil
+}
+
+func (apkManager) ListInstalled() ([]string, error) {
+	if raw, err := os.ReadFile(apkInstalledDBPath); err == nil {
+		if names := parseApkInstalledDB(raw); len(names) > 0 {
+			return names, nil
+		}
+	}
+	out, err := runOutputQuietErr("apk", "info", "-vv")
+	if err != nil {
+		return nil, err
+	}
+	rows := parseApkSearch(out)
+	names := make([]string, 0, len(rows))
+	for _, row := range rows {
+		names = append(names, row.Name)
+	}
+	return names, nil
+}
+
+func (apkManager) Install(pkgs []string) error {
+	return runRootCommand("apk", append([]string{"add"}, pkgs...)...)
+}
+
+func (apkManager) Remove(pkgs []string) error {
+	return runRootCommand("apk", append([]string{"del"}, pkgs...)...)
+}
+
+func (apkManager) Update() error {
+	if err := runRootCommand("apk", "update"); err != nil {
+		return err
+	}
+	return runRootCommand("apk", "upgrade")
+}
+
+func (apkManager) Refresh() error {
+	return runRootCommand("apk", "update")
+}
+
+func (apkManager) ShowInfo(pkg string) error {
+	return runCommandQuietErr("apk", "info", "-a", pkg)
+}
+
+func (apkManager) InstallFzf() error {
+	return runRootCommand("apk", "add", "fzf")
+}
+
+// apkPackageName strips the "-<version>-r<release>" suffix apk appends to
+// package names in verbose output.
+func apkPackageName(nameVersion string) string {
+	parts := strings.Split(nameVersion, "-")
+	if len(parts) < 3 {
+		return nameVersion
+	}
+	release := parts[len(parts)-1]
+	version := parts[len(parts)-2]
+	if len(release) < 2 || release[0] != 'r' || !isDigits(release[1:]) {
+		return nameVersion
+	}
+	if version == "" || version[0] < '0' || version[0] > '9' {
+		return nameVersion
+	}
+	return strings.Join(parts[:len(parts)-2], "-")
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func parseApkSearch(out []byte) []searchRow {
+	rows := make([]searchRow, 0)
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "WARNING:") {
+			continue
+		}
+		parts := strings.SplitN(line, " - ", 2)
+		name := apkPackageName(strings.TrimSpace(parts[0]))
+		if name == "" || strings.ContainsAny(name, " \t") {
+			continue
+		}
+		desc := "-"
+		if len(parts) == 2 && strings.TrimSpace(parts[1]) != "" {
+			desc = strings.TrimSpace(parts[1])
+		}
+		rows = append(rows, searchRow{Name: name, Desc: desc})
+	}
+	return rows
+}
+
+func parseApkInstalledDB(raw []byte) []string {
+	names := make([]string, 0)
+	scanner := bufio.NewScanner(bytes.NewReader(raw))
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "P:") {
+			if name := strings.TrimSpace(strings.TrimPrefix(line, "P:")); name != "" {
+				names = append(names, name)
+			}
+		}
+	}
+	return names
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseApkSearch(t *testing.T) {
+	rows := parseApkSearch(readFixture(t, "apk-search.txt"))
+	got := make([]string, 0, len(rows))
+	for _, row := range rows {
+		got = append(got, row.Name)
+	}
+	if strings.Join(got, ",") != "ripgrep,ripgrep-doc,ripgrep-fish-completion,ripgrep-all" {
+		t.Fatalf("parseApkSearch names=%v", got)
+	}
+	if rows[3].Desc != "Search in PDFs, E-Books, Office documents, zip, tar.gz, and more" {
+		t.Fatalf("unexpected description %q", rows[3].Desc)
+	}
+}
+
+func TestApkPackageName(t *testing.T) {
+	tests := map[string]string{
+		"ripgrep-14.1.1-r0":        "ripgrep",
+		"py3-requests-2.32.3-r0":   "py3-requests",
+		"font-noto-cjk-2.004-r10":  "font-noto-cjk",
+		"busybox-binsh-1.36.1-r29": "busybox-binsh",
+		"ripgrep":                  "ripgrep",
+		"some-tool-rc":             "some-tool-rc",
+		"alpine-base-3.20.3-rfoo":  "alpine-base-3.20.3-rfoo",
+	}
+	for in, want := range tests {
+		if got := apkPackageName(in); got != want {
+			t.Fatalf("apkPackageName(%q)=%q want %q", in, got, want)
+		}
+	}
+}
+
+func TestApkListInstalledReadsDatabase(t *testing.T) {
+	prev := apkInstalledDBPath
+	t.Cleanup(func() { apkInstalledDBPath = prev })
+	apkInstalledDBPath = filepath.Join("..", "..", "tests", "fixtures", "apk-installed-db.txt")
+
+	names, err := apkManager{}.ListInstalled()
+	if err != nil {
+		t.Fatalf("ListInstalled: %v", err)
+	}
+	if strings.Join(names, ",") != "musl,ripgrep" {
+		t.Fatalf("ListInstalled=%v want [musl ripgrep]", names)
+	}
+}
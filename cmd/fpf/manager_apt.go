@@ -0,0 +1,873 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+type aptManager struct {
+	managerSpec
+}
+
+func init() {
+	registerManager(aptManager{managerSpec{
+		name:     "apt",
+		label:    "APT",
+		order:    10,
+		binaries: []string{"apt-cache", "apt-get", "dpkg-query"},
+		root:     true,
+	}})
+}
+
+// Search filters the cached package catalog. `apt-cache search` only runs
+// when the catalog cannot be built, so a query that matches nothing stays
+// free of subprocesses.
+func (aptManager) Search(input searchInput) ([]searchRow, error) {
+	if catalogRows, err := loadAptCatalogRows(input.Query); err == nil {
+		return catalogRows, nil
+	}
+	out, err := input.runOutput("apt-cache", "search", "--", input.Query)
+	if err != nil {
+		return nil, err
+	}
+	return parseAptSearch(out), nil
+}
+
+func (aptManager) ListInstalled() ([]string, error) {
+	packages, err := aptInstalledPackages()
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(packages))
+	for _, pkg := range packages {
+		names = append(names, pkg.displayName())
+	}
+	return names, nil
+}
+
+// InstalledDetails describes each installed package as
+// "version (architecture, auto|manual)".
+func (aptManager) InstalledDetails() ([]searchRow, error) {
+	packages, err := aptInstalledPackages()
+	if err != nil {
+		return nil, err
+	}
+	rows := make([]searchRow, 0, len(packages))
+	for _, pkg := range packages {
+		rows = append(rows, searchRow{Name: pkg.displayName(), Desc: pkg.describe()})
+	}
+	return rows, nil
+}
+
+func (aptManager) Install(pkgs []string) error {
+	return runRootCommand("apt-get", append([]string{"install", "-y"}, pkgs...)...)
+}
+
+func (aptManager) Remove(pkgs []string) error {
+	return runRootCommand("apt-get", append([]string{"remove", "-y"}, pkgs...)...)
+}
+
+func (aptManager) Update() error {
+	if err := runRootCommand("apt-get", "update"); err != nil {
+		return err
+	}
+	return runRootCommand("apt-get", "upgrade", "-y")
+}
+
+func (aptManager) Refresh() error {
+	return runRootCommand("apt-get", "update")
+}
+
+func (aptManager) ShowInfo(pkg string) error {
+	runCommandQuietErr("apt-cache", "show", pkg)
+	fmt.Println()
+	runCommandQuietErr("dpkg", "-L", pkg)
+	return nil
+}
+
+func (aptManager) InstallFzf() error {
+	return runRootCommand("apt-get", "install", "-y", "fzf")
+}
+
+func parseAptSearch(out []byte) []searchRow {
+	rows := make([]searchRow, 0)
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		parts := strings.SplitN(line, " - ", 2)
+		name := strings.TrimSpace(parts[0])
+		desc := "-"
+		if len(parts) == 2 && strings.TrimSpace(parts[1]) != "" {
+			desc = strings.TrimSpace(parts[1])
+		}
+		rows = append(rows, searchRow{Name: name, Desc: desc})
+	}
+	return rows
+}
+
+// parseAptInstalled reads `dpkg-query -W` "name<TAB>version" lines, the
+// fallback when the dpkg status database cannot be read directly.
+func parseAptInstalled(out []byte) []dpkgPackage {
+	packages := make([]dpkgPackage, 0)
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		name, version, _ := strings.Cut(line, "\t")
+		if name != "" {
+			packages = append(packages, dpkgPackage{Name: name, Version: strings.TrimSpace(version)})
+		}
+	}
+	return packages
+}
+
+// dpkgPackage is an installed package from the dpkg status database. Reason
+// is "auto" or "manual" from apt's extended_states, or empty when unknown.
+type dpkgPackage struct {
+	Name    string
+	Version string
+	Arch    string
+	Reason  string
+}
+
+// displayName qualifies packages of a foreign architecture the way apt
+// expects them on the command line, e.g. "libc6:i386".
+func (p dpkgPackage) displayName() string {
+	if p.Arch == "" || p.Arch == "all" || p.Arch == dpkgNativeArch() {
+		return p.Name
+	}
+	return p.Name + ":" + p.Arch
+}
+
+func (p dpkgPackage) describe() string {
+	version := p.Version
+	if version == "" {
+		version = "installed"
+	}
+	extra := make([]string, 0, 2)
+	for _, field := range []string{p.Arch, p.Reason} {
+		if field != "" {
+			extra = append(extra, field)
+		}
+	}
+	if len(extra) == 0 {
+		return version
+	}
+	return version + " (" + strings.Join(extra, ", ") + ")"
+}
+
+var dpkgNativeArchCache struct {
+	sync.Mutex
+	byRoot map[string]string
+}
+
+// dpkgNativeArch is dpkg's native architecture for the apt state root: the
+// first line of var/lib/dpkg/arch, which dpkg writes once a foreign
+// architecture is added, else `dpkg --print-architecture` for the host
+// root. The Go architecture fpf was built for is only a last resort, since
+// it cannot tell armel from armhf or see a foreign userland.
+func dpkgNativeArch() string {
+	root := aptStateRoot()
+	dpkgNativeArchCache.Lock()
+	defer dpkgNativeArchCache.Unlock()
+	if arch, ok := dpkgNativeArchCache.byRoot[root]; ok {
+		return arch
+	}
+
+	arch := readDpkgArchFile(filepath.Join(root, "var", "lib", "dpkg", "arch"))
+	if arch == "" && filepath.Clean(root) == "/" {
+		if out, err := runOutputQuietErr("dpkg", "--print-architecture"); err == nil {
+			arch = strings.TrimSpace(string(out))
+		}
+	}
+	if arch == "" {
+		arch = goarchDebianName()
+	}
+	if dpkgNativeArchCache.byRoot == nil {
+		dpkgNativeArchCache.byRoot = make(map[string]string)
+	}
+	dpkgNativeArchCache.byRoot[root] = arch
+	return arch
+}
+
+// readDpkgArchFile returns the native architecture from dpkg's arch file,
+// which lists it first and the foreign architectures after it.
+func readDpkgArchFile(path string) string {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	for _, line := range strings.Split(string(raw), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			return line
+		}
+	}
+	return ""
+}
+
+// goarchDebianName maps the Go architecture fpf was built for to its Debian
+// name.
+func goarchDebianName() string {
+	switch runtime.GOARCH {
+	case "386":
+		return "i386"
+	case "arm":
+		return "armhf"
+	case "ppc64le":
+		return "ppc64el"
+	case "mips64le":
+		return "mips64el"
+	default:
+		return runtime.GOARCH
+	}
+}
+
+func dpkgStatusPath() string {
+	return filepath.Join(aptStateRoot(), "var", "lib", "dpkg", "status")
+}
+
+func aptExtendedStatesPath() string {
+	return filepath.Join(aptStateRoot(), "var", "lib", "apt", "extended_states")
+}
+
+// dpkgStatusStamp identifies the current dpkg status file by mtime and size.
+func dpkgStatusStamp() string {
+	info, err := os.Stat(dpkgStatusPath())
+	if err != nil {
+		return "status=missing"
+	}
+	return fmt.Sprintf("status=%d:%d", info.ModTime().UnixNano(), info.Size())
+}
+
+// aptInstalledPackages reads the dpkg status database, falling back to
+// dpkg-query when it is not readable.
+func aptInstalledPackages() ([]dpkgPackage, error) {
+	if packages, err := readDpkgStatus(); err == nil {
+		return packages, nil
+	}
+	out, err := runOutputQuietErr("dpkg-query", "-W", "-f=${binary:Package}\\t${Version}\\n")
+	if err != nil {
+		return nil, err
+	}
+	return parseAptInstalled(out), nil
+}
+
+func readDpkgStatus() ([]dpkgPackage, error) {
+	file, err := os.Open(dpkgStatusPath())
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	packages, err := parseDpkgStatus(file)
+	if err != nil {
+		return nil, err
+	}
+	auto := map[string]bool{}
+	if states, err := os.Open(aptExtendedStatesPath()); err == nil {
+		auto = parseAptExtendedStates(states)
+		states.Close()
+	}
+	for i := range packages {
+		// apt records Architecture: all packages under the native arch.
+		arch := packages[i].Arch
+		if arch == "all" {
+			arch = dpkgNativeArch()
+		}
+		packages[i].Reason = "manual"
+		if auto[packages[i].Name+":"+arch] {
+			packages[i].Reason = "auto"
+		}
+	}
+	return packages, nil
+}
+
+// parseDpkgStatus reads the packages dpkg considers installed; entries left
+// in config-files or half-installed states are skipped.
+func parseDpkgStatus(reader io.Reader) ([]dpkgPackage, error) {
+	packages := make([]dpkgPackage, 0)
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	var current dpkgPackage
+	installed := false
+	flush := func() {
+		if current.Name != "" && installed {
+			packages = append(packages, current)
+		}
+		current = dpkgPackage{}
+		installed = false
+	}
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.TrimSpace(line) == "" {
+			flush()
+			continue
+		}
+		if line[0] == ' ' || line[0] == '\t' {
+			continue
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		switch key {
+		case "Package":
+			if current.Name != "" {
+				flush()
+			}
+			current.Name = value
+		case "Status":
+			fields := strings.Fields(value)
+			installed = len(fields) == 3 && fields[2] == "installed"
+		case "Version":
+			current.Version = value
+		case "Architecture":
+			current.Arch = value
+		}
+	}
+	flush()
+	return packages, scanner.Err()
+}
+
+// parseAptExtendedStates returns the "name:arch" keys apt marked as
+// automatically installed.
+func parseAptExtendedStates(reader io.Reader) map[string]bool {
+	auto := make(map[string]bool)
+	scanner := bufio.NewScanner(reader)
+	name, arch, isAuto := "", "", false
+	flush := func() {
+		if name != "" && isAuto {
+			auto[name+":"+arch] = true
+		}
+		name, arch, isAuto = "", "", false
+	}
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			flush()
+			continue
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		switch key {
+		case "Package":
+			if name != "" {
+				flush()
+			}
+			name = value
+		case "Architecture":
+			arch = value
+		case "Auto-Installed":
+			isAuto = value == "1"
+		}
+	}
+	flush()
+	return auto
+}
+
+// APT catalog functions
+func loadAptCatalogRows(q string) ([]searchRow, error) {
+	fingerprint := aptCatalogFingerprint()
+	key := cacheChecksum(fingerprint)
+	cachePath := filepath.Join(cacheRootPath(), "search-catalog", "apt", key+".tsv")
+
+	// Try to load from cache
+	if raw, err := os.ReadFile(cachePath); err == nil {
+		rows := parseCachedRows(raw)
+		if len(rows) > 0 {
+			return filterCatalogRows(rows, q), nil
+		}
+	}
+
+	// Build catalog
+	rows, err := buildAptCatalogRows()
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("apt catalog: no packages under %s", aptListsDir())
+	}
+
+	// Cache the catalog
+	_ = os.MkdirAll(filepath.Dir(cachePath), 0o755)
+	_ = os.WriteFile(cachePath, []byte(renderAPT(rows)), 0o644)
+
+	return filterCatalogRows(rows, q), nil
+}
+
+// aptStateRoot is the filesystem root apt and dpkg state is read from.
+// FPF_APT_ROOT points it at a chroot or a test tree.
+func aptStateRoot() string {
+	if root := strings.TrimSpace(os.Getenv("FPF_APT_ROOT")); root != "" {
+		return root
+	}
+	return "/"
+}
+
+func aptListsDir() string {
+	return filepath.Join(aptStateRoot(), "var", "lib", "apt", "lists")
+}
+
+// aptIndexSuffixes are the compressions apt may keep lists in, plain first.
+var aptIndexSuffixes = []string{"", ".lz4", ".gz", ".xz", ".zst"}
+
+// aptListFiles returns the lists whose name ends in kind, plain or
+// compressed. When several variants of one list exist, the plain file wins.
+func aptListFiles(kind string) []string {
+	dir := aptListsDir()
+	seen := make(map[string]bool)
+	files := make([]string, 0)
+	for _, suffix := range aptIndexSuffixes {
+		matches, _ := filepath.Glob(filepath.Join(dir, "*_"+kind+suffix))
+		sort.Strings(matches)
+		for _, path := range matches {
+			base := strings.TrimSuffix(path, suffix)
+			if seen[base] {
+				continue
+			}
+			seen[base] = true
+			files = append(files, path)
+		}
+	}
+	sort.Strings(files)
+	return files
+}
+
+// aptPackageIndexFiles returns the `*_Packages` indexes apt downloaded.
+func aptPackageIndexFiles() []string {
+	return aptListFiles("Packages")
+}
+
+// aptTranslationFiles returns the English description translations. Debian
+// indexes carry only a Description-md5 and keep the text in these files.
+func aptTranslationFiles() []string {
+	return aptListFiles("i18n_Translation-en")
+}
+
+// aptCatalogFingerprint keys the catalog on the size and mtime of every
+// package index, so `apt update` invalidates it. Without indexes it falls
+// back to the `apt-cache dumpavail` source.
+func aptCatalogFingerprint() string {
+	if files := aptPackageIndexFiles(); len(files) > 0 {
+		parts := make([]string, 0, len(files))
+		for _, path := range append(files, aptTranslationFiles()...) {
+			info, err := os.Stat(path)
+			if err != nil {
+				continue
+			}
+			parts = append(parts, fmt.Sprintf("%s=%d:%d", filepath.Base(path), info.ModTime().UnixNano(), info.Size()))
+		}
+		return fmt.Sprintf("3|apt|lists|%s|%s", aptListsDir(), strings.Join(parts, "|"))
+	}
+
+	cmdPath, _ := exec.LookPath("apt-cache")
+	if cmdPath == "" {
+		cmdPath = "missing"
+	}
+	if fixtureRoot := strings.TrimSpace(os.Getenv("FPF_TEST_FIXTURE_DIR")); fixtureRoot != "" {
+		fixturePath := filepath.Join(fixtureRoot, "apt-dumpavail.txt")
+		if info, err := os.Stat(fixturePath); err == nil {
+			return fmt.Sprintf("2|apt|catalog|%s|fixture=%d|%d", cmdPath, info.ModTime().Unix(), info.Size())
+		}
+	}
+	return fmt.Sprintf("2|apt|catalog|%s", cmdPath)
+}
+
+func buildAptCatalogRows() ([]searchRow, error) {
+	if files := aptPackageIndexFiles(); len(files) > 0 {
+		return buildAptCatalogRowsFromLists(files)
+	}
+
+	cmd := exec.Command("apt-cache", "dumpavail")
+	cmd.Env = os.Environ()
+	cmd.Stderr = ioDiscard{}
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	packages, parseErr := parseAptPackagesReader(stdout, "")
+	if err := cmd.Wait(); err != nil {
+		return nil, err
+	}
+	if parseErr != nil {
+		return nil, parseErr
+	}
+	return aptCatalogRowsFromPackages(packages), nil
+}
+
+// buildAptCatalogRowsFromLists reads every package index, keeping the
+// highest version of packages published in several suites. Descriptions
+// missing from the indexes are filled from the Translation-en lists.
+func buildAptCatalogRowsFromLists(files []string) ([]searchRow, error) {
+	suites := aptListSuites(aptListsDir())
+	packages := make([]aptPackage, 0)
+	var firstErr error
+	for _, path := range files {
+		parsed, err := readAptPackageIndex(path, aptSuiteForIndex(suites, filepath.Base(path)))
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+		packages = append(packages, parsed...)
+	}
+	if len(packages) == 0 && firstErr != nil {
+		return nil, firstErr
+	}
+	fillAptTranslations(packages, aptTranslationFiles())
+	return aptCatalogRowsFromPackages(packages), nil
+}
+
+// fillAptTranslations sets the description of packages that only carry a
+// Description-md5, joining on package name and md5 so that a translation
+// of another version's description is never used. Translation files are
+// only read when some package needs one.
+func fillAptTranslations(packages []aptPackage, files []string) {
+	missing := false
+	for _, pkg := range packages {
+		if pkg.Desc == "" && pkg.DescMD5 != "" {
+			missing = true
+			break
+		}
+	}
+	if !missing || len(files) == 0 {
+		return
+	}
+
+	descs := make(map[string]string)
+	for _, path := range files {
+		translations, _ := readAptPackageIndex(path, "")
+		for _, entry := range translations {
+			if entry.Desc != "" && entry.DescMD5 != "" {
+				descs[entry.Name+"\t"+entry.DescMD5] = entry.Desc
+			}
+		}
+	}
+	for i := range packages {
+		if packages[i].Desc == "" && packages[i].DescMD5 != "" {
+			packages[i].Desc = descs[packages[i].Name+"\t"+packages[i].DescMD5]
+		}
+	}
+}
+
+func readAptPackageIndex(path, suite string) ([]aptPackage, error) {
+	reader, err := openDecompressed(path)
+	if err != nil {
+		return nil, err
+	}
+	packages, parseErr := parseAptPackagesReader(reader, suite)
+	if err := reader.Close(); err != nil && parseErr == nil {
+		parseErr = err
+	}
+	return packages, parseErr
+}
+
+// aptListSuites maps the file name prefix of each Release/InRelease file in
+// dir, e.g. "deb.debian.org_debian_dists_bookworm_", to its suite.
+func aptListSuites(dir string) map[string]string {
+	suites := make(map[string]string)
+	for _, name := range []string{"InRelease", "Release"} {
+		matches, _ := filepath.Glob(filepath.Join(dir, "*_"+name))
+		for _, path := range matches {
+			prefix := strings.TrimSuffix(filepath.Base(path), name)
+			if _, ok := suites[prefix]; ok {
+				continue
+			}
+			if suite := readAptReleaseSuite(path); suite != "" {
+				suites[prefix] = suite
+			}
+		}
+	}
+	return suites
+}
+
+// readAptReleaseSuite returns the Suite field of a Release file, or its
+// Codename when it has none. InRelease files are clearsigned, which leaves
+// the fields readable as plain lines.
+func readAptReleaseSuite(path string) string {
+	file, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer file.Close()
+
+	codename := ""
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "Suite:"):
+			return strings.TrimSpace(strings.TrimPrefix(line, "Suite:"))
+		case strings.HasPrefix(line, "Codename:"):
+			codename = strings.TrimSpace(strings.TrimPrefix(line, "Codename:"))
+		case strings.HasPrefix(line, "MD5Sum:"), strings.HasPrefix(line, "SHA256:"):
+			return codename
+		}
+	}
+	return codename
+}
+
+// aptSuiteForIndex picks the suite of the longest Release prefix that an
+// index file name starts with.
+func aptSuiteForIndex(suites map[string]string, name string) string {
+	best := ""
+	suite := ""
+	for prefix, candidate := range suites {
+		if strings.HasPrefix(name, prefix) && len(prefix) > len(best) {
+			best = prefix
+			suite = candidate
+		}
+	}
+	return suite
+}
+
+// aptPackage is one stanza of a Packages index, a Translation list or
+// `apt-cache dumpavail`.
+type aptPackage struct {
+	Name          string
+	Version       string
+	Section       string
+	InstalledSize int64
+	Suite         string
+	Desc          string
+	DescMD5       string
+}
+
+func parseAptDumpAvail(out []byte) []searchRow {
+	packages, _ := parseAptPackagesReader(bytes.NewReader(out), "")
+	return aptCatalogRowsFromPackages(packages)
+}
+
+// parseAptPackagesReader reads deb822 package stanzas, tagging each with
+// suite. Stanzas read before a decompression error are still returned.
+func parseAptPackagesReader(reader io.Reader, suite string) ([]aptPackage, error) {
+	packages := make([]aptPackage, 0)
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	current := aptPackage{Suite: suite}
+	flush := func() {
+		if current.Name != "" {
+			packages = append(packages, current)
+		}
+		current = aptPackage{Suite: suite}
+	}
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.TrimSpace(line) == "" {
+			flush()
+			continue
+		}
+		if line[0] == ' ' || line[0] == '\t' {
+			continue
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		switch key {
+		case "Package":
+			if current.Name != "" {
+				flush()
+			}
+			current.Name = value
+		case "Version":
+			current.Version = value
+		case "Section":
+			current.Section = value
+		case "Installed-Size":
+			current.InstalledSize, _ = strconv.ParseInt(value, 10, 64)
+		case "Description", "Description-en":
+			current.Desc = value
+		case "Description-md5":
+			current.DescMD5 = value
+		}
+	}
+	flush()
+	return packages, scanner.Err()
+}
+
+// aptCatalogRowsFromPackages keeps the highest version of each package, in
+// first-seen order, and renders it as a row.
+func aptCatalogRowsFromPackages(packages []aptPackage) []searchRow {
+	index := make(map[string]int, len(packages))
+	kept := make([]aptPackage, 0, len(packages))
+	for _, pkg := range packages {
+		if i, ok := index[pkg.Name]; ok {
+			if compareDebianVersions(pkg.Version, kept[i].Version) > 0 {
+				kept[i] = pkg
+			}
+			continue
+		}
+		index[pkg.Name] = len(kept)
+		kept = append(kept, pkg)
+	}
+
+	rows := make([]searchRow, 0, len(kept))
+	for _, pkg := range kept {
+		rows = append(rows, searchRow{Name: pkg.Name, Desc: aptRowDesc(pkg)})
+	}
+	return rows
+}
+
+// aptRowDesc renders "[suite/section] description (version, size)", leaving
+// out whatever the index did not provide.
+func aptRowDesc(pkg aptPackage) string {
+	parts := make([]string, 0, 3)
+	origin := make([]string, 0, 2)
+	for _, field := range []string{pkg.Suite, pkg.Section} {
+		if field != "" {
+			origin = append(origin, field)
+		}
+	}
+	if len(origin) > 0 {
+		parts = append(parts, "["+strings.Join(origin, "/")+"]")
+	}
+	if pkg.Desc != "" {
+		parts = append(parts, pkg.Desc)
+	}
+	extra := make([]string, 0, 2)
+	if pkg.Version != "" {
+		extra = append(extra, pkg.Version)
+	}
+	if pkg.InstalledSize > 0 {
+		extra = append(extra, formatAptInstalledSize(pkg.InstalledSize))
+	}
+	if len(extra) > 0 {
+		parts = append(parts, "("+strings.Join(extra, ", ")+")")
+	}
+	if len(parts) == 0 {
+		return "-"
+	}
+	return strings.Join(parts, " ")
+}
+
+// formatAptInstalledSize formats an Installed-Size value, which is in KiB.
+func formatAptInstalledSize(kib int64) string {
+	if kib < 1024 {
+		return fmt.Sprintf("%d kB", kib)
+	}
+	return fmt.Sprintf("%.1f MB", float64(kib)/1024)
+}
+
+// compareDebianVersions orders two Debian version strings the way dpkg
+// does: epoch, then upstream version, then revision.
+func compareDebianVersions(a, b string) int {
+	epochA, restA := splitVersionEpoch(a)
+	epochB, restB := splitVersionEpoch(b)
+	if epochA != epochB {
+		if epochA < epochB {
+			return -1
+		}
+		return 1
+	}
+	upstreamA, revisionA := splitVersionRelease(restA)
+	upstreamB, revisionB := splitVersionRelease(restB)
+	if cmp := compareDebianFragment(upstreamA, upstreamB); cmp != 0 {
+		return cmp
+	}
+	return compareDebianFragment(revisionA, revisionB)
+}
+
+func splitVersionEpoch(version string) (int, string) {
+	if head, rest, ok := strings.Cut(version, ":"); ok {
+		if epoch, err := strconv.Atoi(head); err == nil {
+			return epoch, rest
+		}
+	}
+	return 0, version
+}
+
+func splitVersionRelease(version string) (string, string) {
+	if i := strings.LastIndex(version, "-"); i >= 0 {
+		return version[:i], version[i+1:]
+	}
+	return version, ""
+}
+
+// compareDebianFragment is dpkg's verrevcmp: non-digit runs compare with
+// "~" sorting before everything and letters before other symbols, digit
+// runs compare numerically.
+func compareDebianFragment(a, b string) int {
+	isDigit := func(c byte) bool { return c >= '0' && c <= '9' }
+	order := func(s string, i int) int {
+		if i >= len(s) {
+			return 0
+		}
+		c := s[i]
+		switch {
+		case isDigit(c):
+			return 0
+		case (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z'):
+			return int(c)
+		case c == '~':
+			return -1
+		default:
+			return int(c) + 256
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		for (i < len(a) && !isDigit(a[i])) || (j < len(b) && !isDigit(b[j])) {
+			ac, bc := order(a, i), order(b, j)
+			if ac != bc {
+				if ac < bc {
+					return -1
+				}
+				return 1
+			}
+			i++
+			j++
+		}
+		for i < len(a) && a[i] == '0' {
+			i++
+		}
+		for j < len(b) && b[j] == '0' {
+			j++
+		}
+		firstDiff := 0
+		for i < len(a) && isDigit(a[i]) && j < len(b) && isDigit(b[j]) {
+			if firstDiff == 0 {
+				firstDiff = int(a[i]) - int(b[j])
+			}
+			i++
+			j++
+		}
+		if i < len(a) && isDigit(a[i]) {
+			return 1
+		}
+		if j < len(b) && isDigit(b[j]) {
+			return -1
+		}
+		if firstDiff != 0 {
+			if firstDiff < 0 {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
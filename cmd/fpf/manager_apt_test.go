@@ -0,0 +1,318 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeAptLists builds an apt state root with a plain bookworm index and a
+// gzipped bookworm-updates index, returning the root.
+func writeAptLists(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+	lists := filepath.Join(root, "var", "lib", "apt", "lists")
+	if err := os.MkdirAll(lists, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	var updates bytes.Buffer
+	gz := gzip.NewWriter(&updates)
+	if _, err := gz.Write(readFixture(t, "apt-lists-updates-Packages.txt")); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	files := map[string][]byte{
+		"deb.example.org_debian_dists_bookworm_InRelease":                                   readFixture(t, "apt-lists-InRelease.txt"),
+		"deb.example.org_debian_dists_bookworm_main_binary-amd64_Packages":                  readFixture(t, "apt-lists-Packages.txt"),
+		"deb.example.org_debian_dists_bookworm-updates_Release":                             []byte("Origin: Debian\nCodename: bookworm-updates\n"),
+		"deb.example.org_debian_dists_bookworm-updates_main_binary-amd64_Packages.gz":       updates.Bytes(),
+		"deb.example.org_debian_dists_bookworm-updates_main_i18n_Translation-en.diff_Index": []byte("ignored\n"),
+	}
+	for name, raw := range files {
+		if err := os.WriteFile(filepath.Join(lists, name), raw, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+func TestBuildAptCatalogRowsReadsLists(t *testing.T) {
+	t.Setenv("FPF_APT_ROOT", writeAptLists(t))
+
+	rows, err := buildAptCatalogRows()
+	if err != nil {
+		t.Fatalf("buildAptCatalogRows: %v", err)
+	}
+	want := []searchRow{
+		{Name: "ripgrep", Desc: "[bookworm-updates/utils] Recursively searches directories for a regex pattern (13.0.0-4+deb12u1, 4.5 MB)"},
+		{Name: "fd-find", Desc: "[oldstable/utils] Simple, fast and user-friendly alternative to find (8.6.0-3, 3.0 MB)"},
+		{Name: "libripgrep-dummy", Desc: "[oldstable] Placeholder package without section or size (1.0)"},
+	}
+	if len(rows) != len(want) {
+		t.Fatalf("rows=%v want %v", rows, want)
+	}
+	for i := range want {
+		if rows[i] != want[i] {
+			t.Fatalf("row %d=%+v want %+v", i, rows[i], want[i])
+		}
+	}
+
+	filtered := filterCatalogRows(rows, "bookworm")
+	if len(filtered) != 0 {
+		t.Fatalf("suite tag should not match queries, got %v", filtered)
+	}
+	if filtered := filterCatalogRows(rows, "ripgrep"); len(filtered) != 2 {
+		t.Fatalf("expected name matches for ripgrep, got %v", filtered)
+	}
+}
+
+func TestBuildAptCatalogRowsJoinsDebianTranslations(t *testing.T) {
+	root := t.TempDir()
+	lists := filepath.Join(root, "var", "lib", "apt", "lists")
+	if err := os.MkdirAll(lists, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string][]byte{
+		"deb.debian.org_debian_dists_bookworm_main_binary-amd64_Packages":   readFixture(t, "apt-lists-debian-Packages.txt"),
+		"deb.debian.org_debian_dists_bookworm_main_i18n_Translation-en.lz4": lz4StoredFrame(readFixture(t, "apt-lists-debian-Translation-en.txt")),
+	}
+	for name, raw := range files {
+		if err := os.WriteFile(filepath.Join(lists, name), raw, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Setenv("FPF_APT_ROOT", root)
+
+	rows, err := buildAptCatalogRows()
+	if err != nil {
+		t.Fatalf("buildAptCatalogRows: %v", err)
+	}
+	want := []searchRow{
+		{Name: "bat", Desc: "[utils] cat(1) clone with syntax highlighting and git integration (0.22.1-4, 4.5 MB)"},
+		{Name: "hyperfine", Desc: "[utils] Command-line benchmarking tool (1.15.0-2, 1.6 MB)"},
+		{Name: "tokei", Desc: "[utils] (12.1.2-4, 3.9 MB)"},
+	}
+	if !reflect.DeepEqual(rows, want) {
+		t.Fatalf("rows=%+v want %+v", rows, want)
+	}
+
+	before := aptCatalogFingerprint()
+	translation := filepath.Join(lists, "deb.debian.org_debian_dists_bookworm_main_i18n_Translation-en.lz4")
+	later := time.Now().Add(time.Hour)
+	if err := os.Chtimes(translation, later, later); err != nil {
+		t.Fatal(err)
+	}
+	if aptCatalogFingerprint() == before {
+		t.Fatal("expected the fingerprint to track Translation-en lists")
+	}
+}
+
+func TestAptSearchSkipsAptCacheForUnmatchedQueries(t *testing.T) {
+	t.Setenv("FPF_APT_ROOT", writeAptLists(t))
+	t.Setenv("FPF_CACHE_DIR", t.TempDir())
+	bin := t.TempDir()
+	logFile := filepath.Join(t.TempDir(), "apt-cache.log")
+	writeMockExecutable(t, bin, "apt-cache", "#!/bin/sh\nprintf '%s\\n' \"$*\" >>\""+logFile+"\"\n")
+	t.Setenv("PATH", bin+":/usr/bin:/bin")
+
+	rows, err := (aptManager{}).Search(searchInput{Query: "no-such-package"})
+	if err != nil || len(rows) != 0 {
+		t.Fatalf("expected no rows, got %+v, %v", rows, err)
+	}
+	if raw, _ := os.ReadFile(logFile); len(raw) != 0 {
+		t.Fatalf("expected no apt-cache call once the catalog loads, got %q", raw)
+	}
+
+	t.Setenv("FPF_APT_ROOT", t.TempDir())
+	if _, err := (aptManager{}).Search(searchInput{Query: "ripgrep"}); err != nil {
+		t.Fatalf("search: %v", err)
+	}
+	if raw, _ := os.ReadFile(logFile); !strings.Contains(string(raw), "search -- ripgrep") {
+		t.Fatalf("expected apt-cache search without package lists, got %q", raw)
+	}
+}
+
+func TestAptCatalogFingerprintTracksIndexFiles(t *testing.T) {
+	root := writeAptLists(t)
+	t.Setenv("FPF_APT_ROOT", root)
+
+	before := aptCatalogFingerprint()
+	index := filepath.Join(root, "var", "lib", "apt", "lists", "deb.example.org_debian_dists_bookworm_main_binary-amd64_Packages")
+	later := time.Now().Add(time.Hour)
+	if err := os.Chtimes(index, later, later); err != nil {
+		t.Fatal(err)
+	}
+	if after := aptCatalogFingerprint(); after == before {
+		t.Fatalf("expected fingerprint to change after apt update, still %q", after)
+	}
+}
+
+func TestParseAptDumpAvailKeepsPlainDescriptions(t *testing.T) {
+	rows := parseAptDumpAvail(readFixture(t, "apt-dumpavail.txt"))
+	if len(rows) == 0 || rows[0].Name != "ripgrep" || rows[0].Desc != "Recursively search directories for regex patterns" {
+		t.Fatalf("unexpected rows %v", rows)
+	}
+}
+
+func TestCompareDebianVersions(t *testing.T) {
+	cases := []struct {
+		a, b string
+		want int
+	}{
+		{"1.0", "1.0", 0},
+		{"1.0", "1.1", -1},
+		{"1.10", "1.9", 1},
+		{"1.0~rc1", "1.0", -1},
+		{"1.0", "1.0+b1", -1},
+		{"1:0.9", "2.0", 1},
+		{"13.0.0-4+b2", "13.0.0-4+deb12u1", -1},
+		{"3.0.17-1~deb12u2", "3.0.14-1~deb12u2", 1},
+		{"1.0-1", "1.0-1~bpo1", 1},
+		{"1.0a", "1.0-", 1},
+	}
+	for _, tc := range cases {
+		if got := compareDebianVersions(tc.a, tc.b); got != tc.want {
+			t.Errorf("compareDebianVersions(%q, %q)=%d want %d", tc.a, tc.b, got, tc.want)
+		}
+	}
+}
+
+// writeDpkgState builds an amd64 apt state root, with armel added as a
+// foreign architecture, holding the dpkg status and extended_states
+// fixtures.
+func writeDpkgState(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+	files := map[string][]byte{
+		filepath.Join("var", "lib", "dpkg", "status"):         readFixture(t, "dpkg-status.txt"),
+		filepath.Join("var", "lib", "apt", "extended_states"): readFixture(t, "apt-extended-states.txt"),
+		filepath.Join("var", "lib", "dpkg", "arch"):           []byte("amd64\narmel\n"),
+	}
+	for rel, raw := range files {
+		path := filepath.Join(root, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, raw, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+func TestAptInstalledDetailsReadsDpkgStatus(t *testing.T) {
+	t.Setenv("FPF_APT_ROOT", writeDpkgState(t))
+	t.Setenv("PATH", t.TempDir())
+	native := "amd64"
+
+	rows, err := aptManager{}.InstalledDetails()
+	if err != nil {
+		t.Fatalf("InstalledDetails: %v", err)
+	}
+	want := []searchRow{
+		{Name: "ripgrep", Desc: "13.0.0-4+b2 (" + native + ", manual)"},
+		{Name: "libc6", Desc: "2.36-9+deb12u10 (" + native + ", auto)"},
+		{Name: "libc6:armel", Desc: "2.36-9+deb12u10 (armel, manual)"},
+		{Name: "adduser", Desc: "3.134 (all, auto)"},
+		{Name: "pinned-tool", Desc: "1.0-1 (" + native + ", manual)"},
+	}
+	if !reflect.DeepEqual(rows, want) {
+		t.Fatalf("rows=%v\nwant %v", rows, want)
+	}
+
+	names, err := aptManager{}.ListInstalled()
+	if err != nil {
+		t.Fatalf("ListInstalled: %v", err)
+	}
+	if len(names) != len(want) || names[2] != "libc6:armel" {
+		t.Fatalf("unexpected names %v", names)
+	}
+}
+
+func TestDpkgNativeArchFollowsStateRoot(t *testing.T) {
+	root := t.TempDir()
+	archFile := filepath.Join(root, "var", "lib", "dpkg", "arch")
+	if err := os.MkdirAll(filepath.Dir(archFile), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(archFile, []byte("armel\narmhf\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("FPF_APT_ROOT", root)
+	if got := dpkgNativeArch(); got != "armel" {
+		t.Fatalf("dpkgNativeArch()=%q want armel from the arch file", got)
+	}
+	if got := (dpkgPackage{Name: "libc6", Arch: "armhf"}).displayName(); got != "libc6:armhf" {
+		t.Fatalf("displayName()=%q want libc6:armhf", got)
+	}
+
+	t.Setenv("FPF_APT_ROOT", t.TempDir())
+	t.Setenv("PATH", createMockPath(t, "dpkg"))
+	if got := dpkgNativeArch(); got != goarchDebianName() {
+		t.Fatalf("dpkgNativeArch()=%q want the GOARCH fallback for a foreign root", got)
+	}
+}
+
+func TestAptInstalledFallsBackToDpkgQuery(t *testing.T) {
+	t.Setenv("FPF_APT_ROOT", t.TempDir())
+	dir := t.TempDir()
+	writeMockExecutable(t, dir, "dpkg-query", "#!/bin/sh\nprintf 'ripgrep\\t14.1.0\\nfd-find\\t9.0.0\\n'\n")
+	t.Setenv("PATH", dir)
+
+	rows, err := aptManager{}.InstalledDetails()
+	if err != nil {
+		t.Fatalf("InstalledDetails: %v", err)
+	}
+	want := []searchRow{{Name: "ripgrep", Desc: "14.1.0"}, {Name: "fd-find", Desc: "9.0.0"}}
+	if !reflect.DeepEqual(rows, want) {
+		t.Fatalf("rows=%v want %v", rows, want)
+	}
+}
+
+func TestInstalledCacheKeepsDetailsAndTracksDpkgStatus(t *testing.T) {
+	root := writeDpkgState(t)
+	t.Setenv("FPF_APT_ROOT", root)
+	t.Setenv("FPF_CACHE_DIR", t.TempDir())
+	t.Setenv("FPF_DISABLE_INSTALLED_CACHE", "")
+	t.Setenv("FPF_INSTALLED_CACHE_TTL", "")
+
+	rows, err := aptManager{}.InstalledDetails()
+	if err != nil {
+		t.Fatal(err)
+	}
+	storeInstalledRowsToCache("apt", rows)
+
+	cacheFile, _ := installedCachePaths("apt")
+	raw, err := os.ReadFile(cacheFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(raw), "libc6:armel\t2.36-9+deb12u10 (armel, manual)\n") {
+		t.Fatalf("expected details in installed cache, got:\n%s", raw)
+	}
+	names, ok := loadInstalledSetFromCache("apt")
+	if !ok {
+		t.Fatal("expected installed cache hit")
+	}
+	if _, found := names["libc6:armel"]; !found || len(names) != len(rows) {
+		t.Fatalf("unexpected cached names %v", names)
+	}
+
+	status := filepath.Join(root, "var", "lib", "dpkg", "status")
+	later := time.Now().Add(time.Hour)
+	if err := os.Chtimes(status, later, later); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := loadInstalledSetFromCache("apt"); ok {
+		t.Fatal("expected installed cache miss after dpkg status changed")
+	}
+}
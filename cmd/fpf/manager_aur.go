@@ -0,0 +1,78 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+)
+
+type aurManager struct {
+	managerSpec
+}
+
+func init() {
+	registerManager(aurManager{managerSpec{
+		name:     "aur",
+		label:    "AUR",
+		order:    35,
+		binaries: []string{"paru", "yay"},
+	}})
+}
+
+// aurHelper returns the AUR helper to drive: FPF_AUR_HELPER when it is set
+// and installed, otherwise paru, then yay.
+func aurHelper() string {
+	candidates := []string{"paru", "yay"}
+	if forced := strings.ToLower(strings.TrimSpace(os.Getenv("FPF_AUR_HELPER"))); forced != "" {
+		candidates = []string{forced}
+	}
+	for _, helper := range candidates {
+		if _, err := exec.LookPath(helper); err == nil {
+			return helper
+		}
+	}
+	return ""
+}
+
+func (aurManager) Ready() bool {
+	if _, err := exec.LookPath("pacman"); err != nil {
+		return false
+	}
+	return aurHelper() != ""
+}
+
+func (aurManager) Search(input searchInput) ([]searchRow, error) {
+	out, err := input.runOutput(aurHelper(), "-Ssa", "--", input.Query)
+	if err != nil {
+		return nil, err
+	}
+	return parsePacmanSearch(out), nil
+}
+
+func (aurManager) ListInstalled() ([]string, error) {
+	out, err := runOutputQuietErr("pacman", "-Qm")
+	if err != nil {
+		return nil, err
+	}
+	return parsePacmanInstalled(out), nil
+}
+
+func (aurManager) Install(pkgs []string) error {
+	return runCommand(aurHelper(), append([]string{"-S", "--aur", "--needed"}, pkgs...)...)
+}
+
+func (aurManager) Remove(pkgs []string) error {
+	return runCommand(aurHelper(), append([]string{"-Rsn"}, pkgs...)...)
+}
+
+func (aurManager) Update() error {
+	return runCommand(aurHelper(), "-Sua")
+}
+
+func (aurManager) Refresh() error {
+	return runCommand(aurHelper(), "-Sy")
+}
+
+func (aurManager) ShowInfo(pkg string) error {
+	return runCommandQuietErr(aurHelper(), "-Sia", pkg)
+}
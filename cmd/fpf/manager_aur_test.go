@@ -0,0 +1,48 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestAurHelperSelection(t *testing.T) {
+	dir := t.TempDir()
+	writeMockExecutable(t, dir, "pacman", "#!/usr/bin/env bash\nexit 0\n")
+	writeMockExecutable(t, dir, "yay", "#!/usr/bin/env bash\nexit 0\n")
+	t.Setenv("PATH", dir)
+	t.Setenv("FPF_AUR_HELPER", "")
+
+	if got := aurHelper(); got != "yay" {
+		t.Fatalf("aurHelper()=%q want yay", got)
+	}
+	if !isManagerCommandReady("aur") {
+		t.Fatal("expected aur to be ready with pacman and yay on PATH")
+	}
+
+	writeMockExecutable(t, dir, "paru", "#!/usr/bin/env bash\nexit 0\n")
+	if got := aurHelper(); got != "paru" {
+		t.Fatalf("aurHelper()=%q want paru to be preferred", got)
+	}
+	t.Setenv("FPF_AUR_HELPER", "yay")
+	if got := aurHelper(); got != "yay" {
+		t.Fatalf("aurHelper()=%q want FPF_AUR_HELPER override", got)
+	}
+	if got := managerCommandForFingerprint("aur"); got != "yay" {
+		t.Fatalf("managerCommandForFingerprint(aur)=%q want yay", got)
+	}
+
+	t.Setenv("PATH", t.TempDir())
+	if isManagerCommandReady("aur") {
+		t.Fatal("expected aur to be unavailable without pacman")
+	}
+}
+
+func TestParseAurSearch(t *testing.T) {
+	rows := parsePacmanSearch(readFixture(t, "aur-search.txt"))
+	if len(rows) != 3 {
+		t.Fatalf("expected 3 AUR rows, got %+v", rows)
+	}
+	want := searchRow{Name: "ripgrep-all-bin", Desc: "rga: ripgrep, but also search in PDFs, E-Books, Office documents, zip, tar.gz, etc."}
+	if rows[1] != want {
+		t.Fatalf("row 1=%+v want %+v", rows[1], want)
+	}
+}
@@ -0,0 +1,647 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+type brewManager struct {
+	managerSpec
+}
+
+func init() {
+	registerManager(brewManager{managerSpec{
+		name:     "brew",
+		label:    "Homebrew",
+		order:    60,
+		binaries: []string{"brew"},
+	}})
+}
+
+// linuxbrewDefaultPrefix is where the Homebrew installer puts Homebrew on
+// Linux; ~/.linuxbrew is the older per-user location.
+var linuxbrewDefaultPrefix = "/home/linuxbrew/.linuxbrew"
+
+// brewBinary locates brew: $HOMEBREW_PREFIX/bin/brew, then PATH, then the
+// Linuxbrew prefixes, so Linuxbrew works before `brew shellenv` is sourced.
+func brewBinary() string {
+	candidates := make([]string, 0, 3)
+	if prefix := strings.TrimSpace(os.Getenv("HOMEBREW_PREFIX")); prefix != "" {
+		candidates = append(candidates, filepath.Join(prefix, "bin", "brew"))
+	}
+	if path, err := exec.LookPath("brew"); err == nil {
+		return firstExecutable(append(candidates, path))
+	}
+	if testableGoOS() == "linux" {
+		candidates = append(candidates, filepath.Join(linuxbrewDefaultPrefix, "bin", "brew"))
+		if home, err := os.UserHomeDir(); err == nil {
+			candidates = append(candidates, filepath.Join(home, ".linuxbrew", "bin", "brew"))
+		}
+	}
+	return firstExecutable(candidates)
+}
+
+func firstExecutable(paths []string) string {
+	for _, path := range paths {
+		if info, err := os.Stat(path); err == nil && !info.IsDir() && info.Mode()&0o111 != 0 {
+			return path
+		}
+	}
+	return ""
+}
+
+// brewPreferredOnLinux reports whether FPF_LINUX_PREFER_BREW pins Homebrew
+// as the primary Linux manager.
+func brewPreferredOnLinux() bool {
+	switch strings.ToLower(strings.TrimSpace(os.Getenv("FPF_LINUX_PREFER_BREW"))) {
+	case "1", "true", "yes", "on":
+		return true
+	}
+	return false
+}
+
+func (brewManager) Ready() bool {
+	return brewBinary() != ""
+}
+
+func (brewManager) Search(input searchInput) ([]searchRow, error) {
+	if catalogRows, err := loadBrewCatalogRows(input.Query); err == nil && len(catalogRows) > 0 {
+		return catalogRows, nil
+	}
+	out, err := input.runOutput(brewBinary(), "search", input.Query)
+	if err != nil {
+		return nil, err
+	}
+	return parseBrewSearch(out), nil
+}
+
+func (brewManager) ListInstalled() ([]string, error) {
+	out, err := runOutputQuietErr(brewBinary(), "list", "--versions")
+	if err != nil {
+		return nil, err
+	}
+	return parseBrewInstalled(out), nil
+}
+
+// Install passes casks with --cask; tapped packages keep their full
+// tap/name so brew resolves them from the right tap.
+func (brewManager) Install(pkgs []string) error {
+	return runBrewByKind("install", pkgs)
+}
+
+func (brewManager) Remove(pkgs []string) error {
+	return runBrewByKind("uninstall", pkgs)
+}
+
+func runBrewByKind(verb string, pkgs []string) error {
+	formulae, casks := splitBrewKinds(pkgs)
+	if len(formulae) > 0 {
+		if err := runCommand(brewBinary(), append([]string{verb}, formulae...)...); err != nil {
+			return err
+		}
+	}
+	if len(casks) > 0 {
+		return runCommand(brewBinary(), append([]string{verb, "--cask"}, casks...)...)
+	}
+	return nil
+}
+
+// splitBrewKinds sorts packages into formulae and casks. Only the named
+// packages are looked up, through `brew info --json=v2`, or the cached API
+// files when brew cannot resolve one of them. A name that is both a formula
+// and a core cask stays a formula, as its cask row is homebrew/cask/<name>.
+// Names found nowhere are left to brew as formulae.
+func splitBrewKinds(pkgs []string) ([]string, []string) {
+	lookup := make([]string, 0, len(pkgs))
+	for _, pkg := range pkgs {
+		if !strings.HasPrefix(pkg, brewCoreCaskTap+"/") {
+			lookup = append(lookup, pkg)
+		}
+	}
+	kinds := map[string]string{}
+	if len(lookup) > 0 {
+		out, err := runOutputQuietErr(brewBinary(), append([]string{"info", "--json=v2"}, lookup...)...)
+		if err == nil {
+			kinds, err = parseBrewInfoKinds(out)
+		}
+		if err != nil {
+			kinds = brewAPIKinds(lookup, loadBrewAPIDetails())
+		}
+	}
+	formulae := make([]string, 0, len(pkgs))
+	casks := make([]string, 0)
+	for _, pkg := range pkgs {
+		if kinds[pkg] == brewCask || strings.HasPrefix(pkg, brewCoreCaskTap+"/") {
+			casks = append(casks, pkg)
+			continue
+		}
+		formulae = append(formulae, pkg)
+	}
+	return formulae, casks
+}
+
+// parseBrewInfoKinds maps every name and full name in `brew info --json=v2`
+// output to formula or cask, formulae taking precedence.
+func parseBrewInfoKinds(out []byte) (map[string]string, error) {
+	var doc struct {
+		Formulae []struct {
+			Name     string `json:"name"`
+			FullName string `json:"full_name"`
+		} `json:"formulae"`
+		Casks []struct {
+			Token     string `json:"token"`
+			FullToken string `json:"full_token"`
+		} `json:"casks"`
+	}
+	if err := json.Unmarshal(bytes.TrimSpace(out), &doc); err != nil {
+		return nil, err
+	}
+	kinds := map[string]string{}
+	for _, formula := range doc.Formulae {
+		for _, name := range []string{formula.Name, formula.FullName} {
+			if name != "" {
+				kinds[name] = brewFormula
+			}
+		}
+	}
+	for _, cask := range doc.Casks {
+		for _, name := range []string{cask.Token, cask.FullToken} {
+			if _, ok := kinds[name]; name != "" && !ok {
+				kinds[name] = brewCask
+			}
+		}
+	}
+	return kinds, nil
+}
+
+// brewAPIKinds classifies core names from the cached API files.
+func brewAPIKinds(names []string, details map[string]brewAPIEntry) map[string]string {
+	kinds := map[string]string{}
+	for _, name := range names {
+		if _, ok := details[brewAPIKey(brewFormula, name)]; ok {
+			kinds[name] = brewFormula
+		} else if _, ok := details[brewAPIKey(brewCask, name)]; ok {
+			kinds[name] = brewCask
+		}
+	}
+	return kinds
+}
+
+// brewInstalledName is the name `brew list` prints for a row: tapped and
+// homebrew/cask/<name> packages are listed by their bare name.
+func brewInstalledName(name string) string {
+	return name[strings.LastIndex(name, "/")+1:]
+}
+
+func (brewManager) Update() error {
+	if err := runCommand(brewBinary(), "update"); err != nil {
+		return err
+	}
+	return runCommand(brewBinary(), "upgrade")
+}
+
+func (brewManager) Refresh() error {
+	return runCommand(brewBinary(), "update")
+}
+
+// ShowInfo falls back to the cached API data when `brew info` fails, e.g.
+// for a core package while offline.
+func (brewManager) ShowInfo(pkg string) error {
+	err := runCommandQuietErr(brewBinary(), "info", pkg)
+	if err == nil {
+		return nil
+	}
+	if text := formatBrewAPIInfo(pkg, loadBrewAPIDetails()); text != "" {
+		fmt.Print(text)
+		return nil
+	}
+	return err
+}
+
+func formatBrewAPIInfo(pkg string, details map[string]brewAPIEntry) string {
+	kind := brewFormula
+	entry, ok := details[brewAPIKey(brewFormula, pkg)]
+	if !ok {
+		kind = brewCask
+		entry, ok = details[brewAPIKey(brewCask, strings.TrimPrefix(pkg, brewCoreCaskTap+"/"))]
+	}
+	if !ok {
+		return ""
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s (%s)\n", pkg, kind)
+	for _, field := range [][2]string{
+		{"Description", entry.Desc},
+		{"Version", entry.Version},
+		{"License", entry.License},
+		{"Homepage", entry.Homepage},
+	} {
+		if field[1] != "" {
+			fmt.Fprintf(&b, "%s: %s\n", field[0], field[1])
+		}
+	}
+	return b.String()
+}
+
+func (brewManager) InstallFzf() error {
+	return runCommand(brewBinary(), "install", "fzf")
+}
+
+const (
+	brewFormula     = "formula"
+	brewCask        = "cask"
+	brewCoreTap     = "homebrew/core"
+	brewCoreCaskTap = "homebrew/cask"
+)
+
+// brewRowTag is the "[formula homebrew/core]" prefix carried in the
+// description of every brew row.
+func brewRowTag(kind, tap string) string {
+	return "[" + kind + " " + tap + "]"
+}
+
+func brewRowKind(desc string) string {
+	if strings.HasPrefix(desc, "["+brewCask+" ") {
+		return brewCask
+	}
+	return brewFormula
+}
+
+// brewTapOf returns the tap of a brew name: "user/tap/name" belongs to
+// user/tap, bare names to the core formula or cask tap.
+func brewTapOf(kind, name string) string {
+	if i := strings.LastIndex(name, "/"); i > 0 {
+		return name[:i]
+	}
+	if kind == brewCask {
+		return brewCoreCaskTap
+	}
+	return brewCoreTap
+}
+
+// brewTaggedRows builds tagged rows from formula and cask names, adding the
+// description, version and license from details when it has the package. A
+// core cask that shares its name with a formula is listed as
+// homebrew/cask/<name> so both rows stay distinct.
+func brewTaggedRows(formulae, casks []string, details map[string]brewAPIEntry) []searchRow {
+	rows := make([]searchRow, 0, len(formulae)+len(casks))
+	seen := make(map[string]struct{}, len(formulae))
+	for _, name := range formulae {
+		if _, ok := seen[name]; ok {
+			continue
+		}
+		seen[name] = struct{}{}
+		desc := brewRowDesc(brewFormula, brewTapOf(brewFormula, name), details[brewAPIKey(brewFormula, name)])
+		rows = append(rows, searchRow{Name: name, Desc: desc})
+	}
+	for _, name := range casks {
+		tap := brewTapOf(brewCask, name)
+		entry := details[brewAPIKey(brewCask, name)]
+		if _, ok := seen[name]; ok && !strings.Contains(name, "/") {
+			name = tap + "/" + name
+		}
+		if _, ok := seen[name]; ok {
+			continue
+		}
+		seen[name] = struct{}{}
+		rows = append(rows, searchRow{Name: name, Desc: brewRowDesc(brewCask, tap, entry)})
+	}
+	return rows
+}
+
+// brewRowDesc renders "[formula homebrew/core] desc (version, license)".
+func brewRowDesc(kind, tap string, entry brewAPIEntry) string {
+	parts := []string{brewRowTag(kind, tap)}
+	if desc := strings.Join(strings.Fields(entry.Desc), " "); desc != "" {
+		parts = append(parts, desc)
+	}
+	extra := make([]string, 0, 2)
+	for _, field := range []string{entry.Version, entry.License} {
+		if field = strings.Join(strings.Fields(field), " "); field != "" {
+			extra = append(extra, field)
+		}
+	}
+	if len(extra) > 0 {
+		parts = append(parts, "("+strings.Join(extra, ", ")+")")
+	}
+	return strings.Join(parts, " ")
+}
+
+// brewAPIEntry is the subset of Homebrew's JSON API data shown in rows and
+// previews.
+type brewAPIEntry struct {
+	Desc     string
+	Version  string
+	Homepage string
+	License  string
+}
+
+func brewAPIKey(kind, name string) string {
+	return kind + "\t" + name
+}
+
+// brewCacheDir mirrors `brew --cache`: $HOMEBREW_CACHE, else
+// ~/Library/Caches/Homebrew on macOS and $XDG_CACHE_HOME/Homebrew elsewhere.
+func brewCacheDir() string {
+	if dir := strings.TrimSpace(os.Getenv("HOMEBREW_CACHE")); dir != "" {
+		return dir
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	if testableGoOS() == "darwin" {
+		return filepath.Join(home, "Library", "Caches", "Homebrew")
+	}
+	if xdg := strings.TrimSpace(os.Getenv("XDG_CACHE_HOME")); xdg != "" {
+		return filepath.Join(xdg, "Homebrew")
+	}
+	return filepath.Join(home, ".cache", "Homebrew")
+}
+
+// brewAPIFiles returns the cached formula and cask API files that
+// `brew update` downloads.
+func brewAPIFiles() (string, string) {
+	dir := filepath.Join(brewCacheDir(), "api")
+	return filepath.Join(dir, "formula.jws.json"), filepath.Join(dir, "cask.jws.json")
+}
+
+// loadBrewAPIDetails reads both API files. Missing or unreadable files just
+// leave their packages without details.
+func loadBrewAPIDetails() map[string]brewAPIEntry {
+	details := map[string]brewAPIEntry{}
+	formulaPath, caskPath := brewAPIFiles()
+	for kind, path := range map[string]string{brewFormula: formulaPath, brewCask: caskPath} {
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		entries, err := parseBrewAPI(kind, raw)
+		if err != nil {
+			continue
+		}
+		for name, entry := range entries {
+			details[brewAPIKey(kind, name)] = entry
+		}
+	}
+	return details
+}
+
+// parseBrewAPI decodes formula.jws.json or cask.jws.json. The JWS envelope
+// carries the package array as a JSON string in "payload"; a bare array, as
+// in the older formula.json, is accepted too.
+func parseBrewAPI(kind string, raw []byte) (map[string]brewAPIEntry, error) {
+	raw = bytes.TrimSpace(raw)
+	if bytes.HasPrefix(raw, []byte("{")) {
+		var envelope struct {
+			Payload string `json:"payload"`
+		}
+		if err := json.Unmarshal(raw, &envelope); err != nil {
+			return nil, err
+		}
+		raw = []byte(envelope.Payload)
+	}
+
+	var items []struct {
+		Name     json.RawMessage `json:"name"`
+		Token    string          `json:"token"`
+		Desc     string          `json:"desc"`
+		Homepage string          `json:"homepage"`
+		License  string          `json:"license"`
+		Version  string          `json:"version"`
+		Versions json.RawMessage `json:"versions"`
+	}
+	if err := json.Unmarshal(raw, &items); err != nil {
+		return nil, err
+	}
+
+	entries := make(map[string]brewAPIEntry, len(items))
+	for _, item := range items {
+		// Casks carry a list of display names under "name"; their id is "token".
+		name := item.Token
+		if kind == brewFormula {
+			name = ""
+			_ = json.Unmarshal(item.Name, &name)
+		}
+		if name == "" {
+			continue
+		}
+		version := item.Version
+		if len(item.Versions) > 0 {
+			var versions struct {
+				Stable string `json:"stable"`
+			}
+			if json.Unmarshal(item.Versions, &versions) == nil && versions.Stable != "" {
+				version = versions.Stable
+			}
+		}
+		if version == "latest" {
+			version = ""
+		}
+		entries[name] = brewAPIEntry{
+			Desc:     item.Desc,
+			Version:  version,
+			Homepage: item.Homepage,
+			License:  item.License,
+		}
+	}
+	return entries, nil
+}
+
+// parseBrewSearch reads `brew search`, whose results are grouped under
+// "==> Formulae" and "==> Casks" headers.
+func parseBrewSearch(out []byte) []searchRow {
+	formulae := make([]string, 0)
+	casks := make([]string, 0)
+	kind := brewFormula
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "==>") {
+			if strings.Contains(strings.ToLower(line), "cask") {
+				kind = brewCask
+			} else {
+				kind = brewFormula
+			}
+			continue
+		}
+		for _, name := range strings.Fields(line) {
+			if kind == brewCask {
+				casks = append(casks, name)
+			} else {
+				formulae = append(formulae, name)
+			}
+		}
+	}
+	return brewTaggedRows(formulae, casks, nil)
+}
+
+func parseBrewInstalled(out []byte) []string {
+	names := make([]string, 0)
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		parts := strings.Fields(line)
+		if len(parts) > 0 {
+			names = append(names, parts[0])
+		}
+	}
+	return names
+}
+
+func loadBrewCatalogRows(q string) ([]searchRow, error) {
+	cachePath := filepath.Join(cacheRootPath(), "catalog", "brew.tsv")
+	metaPath := filepath.Join(cacheRootPath(), "meta", "catalog", "brew.tsv.meta")
+	fingerprint := brewCatalogFingerprint()
+
+	if rawMeta, err := os.ReadFile(metaPath); err == nil {
+		meta := parseMetaMap(rawMeta)
+		if meta["fingerprint"] == fingerprint {
+			if raw, err := os.ReadFile(cachePath); err == nil {
+				rows := parseCachedRows(raw)
+				if len(rows) > 0 {
+					return filterCatalogRows(rows, q), nil
+				}
+			}
+		}
+	}
+
+	rows, err := buildBrewCatalogRows()
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+
+	_ = os.MkdirAll(filepath.Dir(cachePath), 0o755)
+	_ = os.MkdirAll(filepath.Dir(metaPath), 0o755)
+	_ = os.WriteFile(cachePath, []byte(renderAPT(rows)), 0o644)
+
+	now := time.Now()
+	meta := strings.Builder{}
+	meta.WriteString("format_version=1\n")
+	meta.WriteString("created_at=")
+	meta.WriteString(now.UTC().Format(time.RFC3339))
+	meta.WriteString("\n")
+	meta.WriteString("created_epoch=")
+	meta.WriteString(fmt.Sprintf("%d", now.Unix()))
+	meta.WriteString("\n")
+	meta.WriteString("fingerprint=")
+	meta.WriteString(fingerprint)
+	meta.WriteString("\n")
+	meta.WriteString("item_count=")
+	meta.WriteString(fmt.Sprintf("%d", len(rows)))
+	meta.WriteString("\n")
+	_ = os.WriteFile(metaPath, []byte(meta.String()), 0o644)
+
+	return filterCatalogRows(rows, q), nil
+}
+
+func brewCatalogFingerprint() string {
+	cmdPath := brewBinary()
+	if cmdPath == "" {
+		cmdPath = "missing"
+	}
+	formulaPath, caskPath := brewAPIFiles()
+	return fmt.Sprintf("4|brew|%s|formula=%s|cask=%s|taps=%s", cmdPath, brewAPIFileStamp(formulaPath), brewAPIFileStamp(caskPath), brewTapsStamp())
+}
+
+// brewRepository mirrors `brew --repository` without starting brew:
+// $HOMEBREW_REPOSITORY, else the checkout the brew binary resolves into.
+func brewRepository() string {
+	if dir := strings.TrimSpace(os.Getenv("HOMEBREW_REPOSITORY")); dir != "" {
+		return dir
+	}
+	binary := brewBinary()
+	if binary == "" {
+		return ""
+	}
+	if resolved, err := filepath.EvalSymlinks(binary); err == nil {
+		binary = resolved
+	}
+	return filepath.Dir(filepath.Dir(binary))
+}
+
+// brewTapsStamp lists the installed taps with their formula and cask
+// directory mtimes, so `brew tap`, `brew untap` and tap updates rebuild the
+// catalog.
+func brewTapsStamp() string {
+	repo := brewRepository()
+	if repo == "" {
+		return "missing"
+	}
+	tapsDir := filepath.Join(repo, "Library", "Taps")
+	users, err := os.ReadDir(tapsDir)
+	if err != nil {
+		return "missing"
+	}
+	parts := make([]string, 0)
+	for _, user := range users {
+		if !user.IsDir() {
+			continue
+		}
+		repos, err := os.ReadDir(filepath.Join(tapsDir, user.Name()))
+		if err != nil {
+			continue
+		}
+		for _, tap := range repos {
+			if !tap.IsDir() {
+				continue
+			}
+			dir := filepath.Join(tapsDir, user.Name(), tap.Name())
+			stamp := user.Name() + "/" + tap.Name()
+			for _, sub := range []string{"", "Formula", "Casks"} {
+				if info, err := os.Stat(filepath.Join(dir, sub)); err == nil {
+					stamp += fmt.Sprintf(":%d", info.ModTime().UnixNano())
+				}
+			}
+			parts = append(parts, stamp)
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+// brewAPIFileStamp identifies one version of an API file, so the catalog is
+// rebuilt whenever `brew update` downloads a new one.
+func brewAPIFileStamp(path string) string {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "missing"
+	}
+	return fmt.Sprintf("%d:%d", info.ModTime().UnixNano(), info.Size())
+}
+
+func buildBrewCatalogRows() ([]searchRow, error) {
+	formulae := make([]string, 0)
+	err := runLineStreamQuietErr(brewBinary(), []string{"formulae"}, func(line string) {
+		if name := strings.TrimSpace(line); name != "" {
+			formulae = append(formulae, name)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	casks := make([]string, 0)
+	err = runLineStreamQuietErr(brewBinary(), []string{"casks"}, func(line string) {
+		if name := strings.TrimSpace(line); name != "" {
+			casks = append(casks, name)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return brewTaggedRows(formulae, casks, loadBrewAPIDetails()), nil
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// brewTapManager browses Homebrew taps: `fpf -m brew-tap` lists tapped
+// repositories, installing a row taps it and removing it untaps it. It is
+// only used when selected explicitly.
+type brewTapManager struct {
+	managerSpec
+}
+
+func init() {
+	registerManager(brewTapManager{managerSpec{
+		name:         "brew-tap",
+		label:        "Homebrew taps",
+		order:        61,
+		binaries:     []string{"brew"},
+		explicitOnly: true,
+	}})
+}
+
+func (brewTapManager) Ready() bool {
+	return brewBinary() != ""
+}
+
+// Search lists tapped repositories matching the query. A query shaped like
+// user/repo that is not tapped yet is offered as a row so it can be added.
+func (m brewTapManager) Search(input searchInput) ([]searchRow, error) {
+	var rows []searchRow
+	if out, err := input.runOutput(brewBinary(), "tap-info", "--json", "--installed"); err == nil {
+		rows = parseBrewTapInfo(out)
+	} else {
+		taps, listErr := m.ListInstalled()
+		if listErr != nil {
+			return nil, listErr
+		}
+		for _, tap := range taps {
+			rows = append(rows, searchRow{Name: tap, Desc: "tapped"})
+		}
+	}
+
+	query := strings.ToLower(strings.TrimSpace(input.Query))
+	filtered := make([]searchRow, 0, len(rows))
+	tapped := false
+	for _, row := range rows {
+		name := strings.ToLower(row.Name)
+		if name == query {
+			tapped = true
+		}
+		if query == "" || strings.Contains(name, query) || strings.Contains(strings.ToLower(row.Desc), query) {
+			filtered = append(filtered, row)
+		}
+	}
+	if !tapped && isBrewTapName(query) {
+		filtered = append(filtered, searchRow{Name: query, Desc: "not tapped"})
+	}
+	return filtered, nil
+}
+
+func (brewTapManager) ListInstalled() ([]string, error) {
+	out, err := runOutputQuietErr(brewBinary(), "tap")
+	if err != nil {
+		return nil, err
+	}
+	taps := make([]string, 0)
+	for _, line := range splitLines(out) {
+		if tap := strings.TrimSpace(line); tap != "" {
+			taps = append(taps, tap)
+		}
+	}
+	return taps, nil
+}
+
+// Install taps each repository; `brew tap` takes one tap per call.
+func (brewTapManager) Install(pkgs []string) error {
+	for _, tap := range pkgs {
+		if err := runCommand(brewBinary(), "tap", tap); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (brewTapManager) Remove(pkgs []string) error {
+	return runCommand(brewBinary(), append([]string{"untap"}, pkgs...)...)
+}
+
+// Update and Refresh both run `brew update`, which pulls every tap.
+func (brewTapManager) Update() error {
+	return runCommand(brewBinary(), "update")
+}
+
+func (brewTapManager) Refresh() error {
+	return runCommand(brewBinary(), "update")
+}
+
+func (brewTapManager) ShowInfo(pkg string) error {
+	return runCommandQuietErr(brewBinary(), "tap-info", pkg)
+}
+
+// isBrewTapName reports whether name has the user/repo shape of a tap.
+func isBrewTapName(name string) bool {
+	user, repo, ok := strings.Cut(name, "/")
+	return ok && user != "" && repo != "" && !strings.ContainsAny(repo, "/ \t")
+}
+
+// parseBrewTapInfo reads `brew tap-info --json --installed` into rows that
+// summarise each tap's formulae, casks and remote.
+func parseBrewTapInfo(out []byte) []searchRow {
+	var taps []struct {
+		Name         string   `json:"name"`
+		Remote       string   `json:"remote"`
+		FormulaNames []string `json:"formula_names"`
+		CaskTokens   []string `json:"cask_tokens"`
+	}
+	if err := json.Unmarshal(bytes.TrimSpace(out), &taps); err != nil {
+		return nil
+	}
+	rows := make([]searchRow, 0, len(taps))
+	for _, tap := range taps {
+		if tap.Name == "" {
+			continue
+		}
+		desc := fmt.Sprintf("%d formulae, %d casks", len(tap.FormulaNames), len(tap.CaskTokens))
+		if tap.Remote != "" {
+			desc += " (" + tap.Remote + ")"
+		}
+		rows = append(rows, searchRow{Name: tap.Name, Desc: desc})
+	}
+	return rows
+}
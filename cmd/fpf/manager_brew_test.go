@@ -0,0 +1,272 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBrewBinaryFindsLinuxbrewOffPath(t *testing.T) {
+	prefix := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(prefix, "bin"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	writeMockExecutable(t, filepath.Join(prefix, "bin"), "brew", "#!/bin/sh\nexit 0\n")
+
+	oldPrefix := linuxbrewDefaultPrefix
+	linuxbrewDefaultPrefix = prefix
+	t.Cleanup(func() { linuxbrewDefaultPrefix = oldPrefix })
+
+	t.Setenv("PATH", t.TempDir())
+	t.Setenv("HOMEBREW_PREFIX", "")
+	t.Setenv("FPF_TEST_UNAME", "Linux")
+	want := filepath.Join(prefix, "bin", "brew")
+	if got := brewBinary(); got != want {
+		t.Fatalf("brewBinary()=%q want %q", got, want)
+	}
+
+	t.Setenv("FPF_TEST_UNAME", "Darwin")
+	if got := brewBinary(); got != "" {
+		t.Fatalf("expected no Linuxbrew fallback on darwin, got %q", got)
+	}
+}
+
+func TestBrewBinaryHonoursHomebrewPrefix(t *testing.T) {
+	prefix := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(prefix, "bin"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	writeMockExecutable(t, filepath.Join(prefix, "bin"), "brew", "#!/bin/sh\nexit 0\n")
+
+	t.Setenv("PATH", createMockPath(t, "brew"))
+	t.Setenv("HOMEBREW_PREFIX", prefix)
+	want := filepath.Join(prefix, "bin", "brew")
+	if got := brewBinary(); got != want {
+		t.Fatalf("brewBinary()=%q want %q", got, want)
+	}
+}
+
+func TestDetectDefaultManagerPinsBrewOnLinux(t *testing.T) {
+	t.Setenv("PATH", createMockPath(t, "apt-cache", "apt-get", "dpkg-query", "brew"))
+	t.Setenv("HOMEBREW_PREFIX", "")
+	t.Setenv("FPF_TEST_UNAME", "Linux")
+
+	if got := detectDefaultManagerGo(); got != "apt" {
+		t.Fatalf("detectDefaultManagerGo()=%q want apt", got)
+	}
+	t.Setenv("FPF_LINUX_PREFER_BREW", "1")
+	if got := detectDefaultManagerGo(); got != "brew" {
+		t.Fatalf("detectDefaultManagerGo() with pin=%q want brew", got)
+	}
+	if got := detectDefaultManagersGo(true); len(got) == 0 || got[0] != "brew" {
+		t.Fatalf("expected brew first in manager set, got %v", got)
+	}
+}
+
+func TestParseBrewSearchTagsFormulaeAndCasks(t *testing.T) {
+	rows := parseBrewSearch(readFixture(t, "brew-search-sections.txt"))
+	want := []searchRow{
+		{Name: "docker", Desc: "[formula homebrew/core]"},
+		{Name: "docker-compose", Desc: "[formula homebrew/core]"},
+		{Name: "hashicorp/tap/terraform", Desc: "[formula hashicorp/tap]"},
+		{Name: "homebrew/cask/docker", Desc: "[cask homebrew/cask]"},
+		{Name: "hashicorp/tap/hashicorp-vagrant", Desc: "[cask hashicorp/tap]"},
+	}
+	if !reflect.DeepEqual(rows, want) {
+		t.Fatalf("parseBrewSearch=%+v want %+v", rows, want)
+	}
+	if brewRowKind(rows[3].Desc) != brewCask || brewRowKind(rows[0].Desc) != brewFormula {
+		t.Fatalf("brewRowKind did not read back row tags: %+v", rows)
+	}
+	if got := brewInstalledName("homebrew/cask/docker"); got != "docker" {
+		t.Fatalf("brewInstalledName=%q", got)
+	}
+}
+
+func TestParseBrewInfoKinds(t *testing.T) {
+	kinds, err := parseBrewInfoKinds(readFixture(t, "brew-info-v2.json"))
+	if err != nil {
+		t.Fatalf("parseBrewInfoKinds: %v", err)
+	}
+	want := map[string]string{
+		"docker":                  brewFormula,
+		"terraform":               brewFormula,
+		"hashicorp/tap/terraform": brewFormula,
+		"ripgrep-beta":            brewCask,
+	}
+	if !reflect.DeepEqual(kinds, want) {
+		t.Fatalf("parseBrewInfoKinds=%v want %v", kinds, want)
+	}
+}
+
+func TestSplitBrewKindsLooksUpOnlyNamedPackages(t *testing.T) {
+	bin := t.TempDir()
+	logFile := filepath.Join(t.TempDir(), "brew.log")
+	script := "#!/bin/sh\nprintf '%s\\n' \"$*\" >>\"" + logFile + "\"\n[ \"$1\" = info ] && cat \"" + filepath.Join("..", "..", "tests", "fixtures", "brew-info-v2.json") + "\"\n"
+	writeMockExecutable(t, bin, "brew", script)
+	t.Setenv("PATH", bin+":/usr/bin:/bin")
+	t.Setenv("HOMEBREW_PREFIX", "")
+	t.Setenv("FPF_CACHE_DIR", t.TempDir())
+
+	formulae, casks := splitBrewKinds([]string{"docker", "ripgrep-beta", "homebrew/cask/docker"})
+	if !reflect.DeepEqual(formulae, []string{"docker"}) || !reflect.DeepEqual(casks, []string{"ripgrep-beta", "homebrew/cask/docker"}) {
+		t.Fatalf("splitBrewKinds formulae=%v casks=%v", formulae, casks)
+	}
+	raw, _ := os.ReadFile(logFile)
+	if got := string(raw); got != "info --json=v2 docker ripgrep-beta\n" {
+		t.Fatalf("expected a single brew info lookup and no catalog build, got %q", got)
+	}
+}
+
+func TestParseBrewTapInfo(t *testing.T) {
+	rows := parseBrewTapInfo(readFixture(t, "brew-tap-info.json"))
+	want := []searchRow{
+		{Name: "homebrew/cask-fonts", Desc: "0 formulae, 2 casks (https://github.com/Homebrew/homebrew-cask-fonts)"},
+		{Name: "hashicorp/tap", Desc: "3 formulae, 1 casks (https://github.com/hashicorp/homebrew-tap)"},
+	}
+	if !reflect.DeepEqual(rows, want) {
+		t.Fatalf("parseBrewTapInfo=%+v want %+v", rows, want)
+	}
+	for name, want := range map[string]bool{"hashicorp/tap": true, "ripgrep": false, "a/b/c": false, "/tap": false} {
+		if got := isBrewTapName(name); got != want {
+			t.Fatalf("isBrewTapName(%q)=%v want %v", name, got, want)
+		}
+	}
+}
+
+func TestBrewTapManagerIsExplicitOnly(t *testing.T) {
+	t.Setenv("PATH", createMockPath(t, "brew"))
+	t.Setenv("HOMEBREW_PREFIX", "")
+	t.Setenv("FPF_TEST_UNAME", "Darwin")
+
+	if got := detectDefaultManagersGo(true); sliceContains(got, "brew-tap") || !sliceContains(got, "brew") {
+		t.Fatalf("expected brew without brew-tap in auto mode, got %v", got)
+	}
+	if got := resolveManagers("brew-tap", actionSearch, ""); len(got) != 1 || got[0] != "brew-tap" {
+		t.Fatalf("expected explicit brew-tap selection, got %v", got)
+	}
+}
+
+func TestParseBrewAPI(t *testing.T) {
+	formulae, err := parseBrewAPI(brewFormula, readFixture(t, "brew-api-formula.jws.json"))
+	if err != nil {
+		t.Fatalf("parseBrewAPI formula: %v", err)
+	}
+	want := brewAPIEntry{
+		Desc:     "Search tool like grep and The Silver Searcher",
+		Version:  "14.1.0",
+		Homepage: "https://github.com/BurntSushi/ripgrep",
+		License:  "Unlicense",
+	}
+	if formulae["ripgrep"] != want {
+		t.Fatalf("ripgrep entry=%+v want %+v", formulae["ripgrep"], want)
+	}
+
+	casks, err := parseBrewAPI(brewCask, readFixture(t, "brew-api-cask.jws.json"))
+	if err != nil {
+		t.Fatalf("parseBrewAPI cask: %v", err)
+	}
+	if got := casks["firefox"]; got.Desc != "Web browser" || got.Version != "127.0.2" {
+		t.Fatalf("firefox entry=%+v", got)
+	}
+	if got := casks["google-chrome"]; got.Version != "" || got.Desc != "" {
+		t.Fatalf("expected empty desc and no \"latest\" version for google-chrome, got %+v", got)
+	}
+
+	bare, err := parseBrewAPI(brewFormula, []byte(`[{"name":"jq","desc":"JSON processor","versions":{"stable":"1.7.1"}}]`))
+	if err != nil || bare["jq"].Version != "1.7.1" {
+		t.Fatalf("expected bare formula array to parse, got %+v (%v)", bare, err)
+	}
+}
+
+func TestBrewTaggedRowsUsesAPIDetails(t *testing.T) {
+	cacheDir := t.TempDir()
+	apiDir := filepath.Join(cacheDir, "api")
+	if err := os.MkdirAll(apiDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for src, dst := range map[string]string{"brew-api-formula.jws.json": "formula.jws.json", "brew-api-cask.jws.json": "cask.jws.json"} {
+		if err := os.WriteFile(filepath.Join(apiDir, dst), readFixture(t, src), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Setenv("HOMEBREW_CACHE", cacheDir)
+
+	details := loadBrewAPIDetails()
+	rows := brewTaggedRows([]string{"ripgrep", "docker", "hashicorp/tap/terraform"}, []string{"docker", "google-chrome"}, details)
+	want := []searchRow{
+		{Name: "ripgrep", Desc: "[formula homebrew/core] Search tool like grep and The Silver Searcher (14.1.0, Unlicense)"},
+		{Name: "docker", Desc: "[formula homebrew/core] Pack, ship and run any application as a lightweight container (27.0.3, Apache-2.0)"},
+		{Name: "hashicorp/tap/terraform", Desc: "[formula hashicorp/tap]"},
+		{Name: "homebrew/cask/docker", Desc: "[cask homebrew/cask] App to build and share containerised applications and microservices (4.31.0,153195)"},
+		{Name: "google-chrome", Desc: "[cask homebrew/cask]"},
+	}
+	if !reflect.DeepEqual(rows, want) {
+		t.Fatalf("brewTaggedRows=%+v want %+v", rows, want)
+	}
+
+	info := formatBrewAPIInfo("homebrew/cask/docker", details)
+	if !strings.Contains(info, "homebrew/cask/docker (cask)") || !strings.Contains(info, "Homepage: https://www.docker.com/products/docker-desktop") {
+		t.Fatalf("formatBrewAPIInfo=%q", info)
+	}
+}
+
+func TestBrewCatalogFingerprintTracksAPIFiles(t *testing.T) {
+	cacheDir := t.TempDir()
+	t.Setenv("HOMEBREW_CACHE", cacheDir)
+	t.Setenv("PATH", createMockPath(t, "brew"))
+	t.Setenv("HOMEBREW_PREFIX", "")
+
+	missing := brewCatalogFingerprint()
+	formulaPath, _ := brewAPIFiles()
+	if err := os.MkdirAll(filepath.Dir(formulaPath), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(formulaPath, []byte(`{"payload":"[]"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	written := brewCatalogFingerprint()
+	if written == missing {
+		t.Fatalf("expected fingerprint to change once formula.jws.json exists: %s", written)
+	}
+	later := time.Now().Add(time.Hour)
+	if err := os.Chtimes(formulaPath, later, later); err != nil {
+		t.Fatal(err)
+	}
+	if touched := brewCatalogFingerprint(); touched == written {
+		t.Fatalf("expected fingerprint to change with mtime: %s", touched)
+	}
+}
+
+func TestBrewCatalogFingerprintTracksTaps(t *testing.T) {
+	repo := t.TempDir()
+	t.Setenv("HOMEBREW_CACHE", t.TempDir())
+	t.Setenv("HOMEBREW_REPOSITORY", repo)
+	t.Setenv("PATH", createMockPath(t, "brew"))
+
+	untapped := brewCatalogFingerprint()
+	tap := filepath.Join(repo, "Library", "Taps", "example", "homebrew-tools")
+	if err := os.MkdirAll(filepath.Join(tap, "Formula"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	tapped := brewCatalogFingerprint()
+	if tapped == untapped || !strings.Contains(tapped, "example/homebrew-tools") {
+		t.Fatalf("expected brew tap to change the fingerprint: %s", tapped)
+	}
+	later := time.Now().Add(time.Hour)
+	if err := os.Chtimes(filepath.Join(tap, "Formula"), later, later); err != nil {
+		t.Fatal(err)
+	}
+	if updated := brewCatalogFingerprint(); updated == tapped {
+		t.Fatalf("expected a tap update to change the fingerprint: %s", updated)
+	}
+	if err := os.RemoveAll(tap); err != nil {
+		t.Fatal(err)
+	}
+	if untapped := brewCatalogFingerprint(); strings.Contains(untapped, "example/homebrew-tools") {
+		t.Fatalf("expected brew untap to drop the tap from the fingerprint: %s", untapped)
+	}
+}
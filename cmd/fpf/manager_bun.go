@@ -0,0 +1,197 @@
+package main
+
+import (
+	"context"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+type bunManager struct {
+	managerSpec
+}
+
+func init() {
+	registerManager(bunManager{managerSpec{
+		name:     "bun",
+		label:    "bun",
+		order:    120,
+		binaries: []string{"bun"},
+	}})
+}
+
+func (bunManager) Search(input searchInput) ([]searchRow, error) {
+	if !bunSearchAvailable() {
+		if !input.AllowNPMRegistryFallback {
+			return nil, nil
+		}
+		return npmRegistrySearch(input)
+	}
+	out, err := input.runOutput("bun", "search", input.Query)
+	if err != nil {
+		if _, lookupErr := exec.LookPath("npm"); lookupErr != nil || !input.AllowNPMRegistryFallback {
+			return nil, err
+		}
+		npmRows, npmErr := npmRegistrySearch(input)
+		if npmErr != nil {
+			return nil, err
+		}
+		return npmRows, nil
+	}
+	rows := parseBunSearch(out)
+	if len(rows) > 0 {
+		return rows, nil
+	}
+	if _, lookupErr := exec.LookPath("npm"); lookupErr != nil || !input.AllowNPMRegistryFallback {
+		return rows, nil
+	}
+	npmRows, npmErr := npmRegistrySearch(input)
+	if npmErr != nil {
+		return rows, nil
+	}
+	return npmRows, nil
+}
+
+func (bunManager) ListInstalled() ([]string, error) {
+	out, err := runOutputQuietErr("bun", "pm", "ls", "--global")
+	if err != nil {
+		if _, lookupErr := exec.LookPath("npm"); lookupErr == nil {
+			npmOut, npmErr := runOutputQuietErr("npm", "ls", "-g", "--depth=0", "--parseable")
+			if npmErr == nil {
+				return parseNpmInstalled(npmOut), nil
+			}
+		}
+		return nil, err
+	}
+	return parseBunInstalled(out), nil
+}
+
+func (bunManager) Install(pkgs []string) error {
+	return runCommand("bun", append([]string{"add", "-g"}, pkgs...)...)
+}
+
+func (bunManager) Remove(pkgs []string) error {
+	return runCommand("bun", append([]string{"remove", "--global"}, pkgs...)...)
+}
+
+func (bunManager) Update() error {
+	return runCommand("bun", "update", "--global")
+}
+
+func (bunManager) Refresh() error {
+	cmd := exec.Command("bun", "pm", "cache")
+	cmd.Env = os.Environ()
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = io.Discard
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+func (bunManager) ShowInfo(pkg string) error {
+	if err := runCommandQuietErr("bun", "info", pkg); err != nil {
+		return runCommandQuietErr("npm", "view", pkg)
+	}
+	return nil
+}
+
+var (
+	bunSearchCheckOnce sync.Once
+	bunSearchReady     bool
+)
+
+func bunSearchAvailable() bool {
+	bunSearchCheckOnce.Do(func() {
+		if _, err := exec.LookPath("bun"); err != nil {
+			bunSearchReady = false
+			return
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+
+		cmd := exec.CommandContext(ctx, "bun", "search", "--help")
+		cmd.Env = os.Environ()
+		out, err := cmd.CombinedOutput()
+		if err == nil {
+			bunSearchReady = true
+			return
+		}
+		text := strings.ToLower(string(out))
+		if strings.Contains(text, "script not found \"search\"") || strings.Contains(text, "unknown command") {
+			bunSearchReady = false
+			return
+		}
+		// Keep behavior permissive for non-standard bun outputs.
+		bunSearchReady = true
+	})
+	return bunSearchReady
+}
+
+func parseBunSearch(out []byte) []searchRow {
+	rows := make([]searchRow, 0)
+	lines := splitLines(out)
+	for i, line := range lines {
+		if i == 0 {
+			continue
+		}
+		trim := strings.TrimSpace(line)
+		if trim == "" {
+			continue
+		}
+		parts := strings.Fields(trim)
+		if len(parts) == 0 {
+			continue
+		}
+		name := parts[0]
+		desc := "-"
+		if len(parts) > 1 {
+			desc = strings.Join(parts[1:], " ")
+		}
+		rows = append(rows, searchRow{Name: name, Desc: desc})
+	}
+	return rows
+}
+
+func parseBunInstalled(out []byte) []string {
+	names := make([]string, 0)
+	lines := splitLines(out)
+	for i, rawLine := range lines {
+		if i == 0 {
+			continue
+		}
+
+		line := strings.ReplaceAll(rawLine, "\r", "")
+		line = strings.TrimSpace(line)
+		line = strings.TrimLeft(line, "+-|` ")
+		line = strings.TrimSpace(line)
+
+		if line == "" {
+			continue
+		}
+		if strings.Contains(line, "node_modules") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		pkg := fields[0]
+
+		atCount := strings.Count(pkg, "@")
+		if (strings.HasPrefix(pkg, "@") && atCount >= 2) || (!strings.HasPrefix(pkg, "@") && atCount >= 1) {
+			idx := strings.LastIndex(pkg, "@")
+			if idx > 0 {
+				pkg = pkg[:idx]
+			}
+		}
+
+		if pkg != "" {
+			names = append(names, pkg)
+		}
+	}
+	return names
+}
@@ -0,0 +1,239 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type cargoManager struct {
+	managerSpec
+}
+
+func init() {
+	registerManager(cargoManager{managerSpec{
+		name:         "cargo",
+		label:        "Cargo",
+		order:        140,
+		binaries:     []string{"cargo"},
+		explicitOnly: true,
+	}})
+}
+
+// crates.io caps `cargo search --limit` at 100.
+const cargoSearchMaxLimit = 100
+
+type cargoInstalledCrate struct {
+	Name     string
+	Version  string
+	Registry bool
+}
+
+func cargoHomePath() string {
+	if home := strings.TrimSpace(os.Getenv("CARGO_HOME")); home != "" {
+		return home
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".cargo")
+}
+
+func cargoBinstallAvailable() bool {
+	_, err := exec.LookPath("cargo-binstall")
+	return err == nil
+}
+
+func (cargoManager) Search(input searchInput) ([]searchRow, error) {
+	limit := input.Limit
+	if limit <= 0 {
+		limit = 40
+	}
+	if limit > cargoSearchMaxLimit {
+		limit = cargoSearchMaxLimit
+	}
+	out, err := input.runOutput("cargo", "search", "--limit", strconv.Itoa(limit), "--color", "never", input.Query)
+	if err != nil {
+		return nil, err
+	}
+	return parseCargoSearch(out), nil
+}
+
+func (cargoManager) ListInstalled() ([]string, error) {
+	crates, err := loadCargoInstalledCrates()
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(crates))
+	for _, crate := range crates {
+		names = append(names, crate.Name)
+	}
+	return names, nil
+}
+
+func (cargoManager) Install(pkgs []string) error {
+	if cargoBinstallAvailable() {
+		return runCommand("cargo", append([]string{"binstall", "--no-confirm"}, pkgs...)...)
+	}
+	return runCommand("cargo", append([]string{"install"}, pkgs...)...)
+}
+
+func (cargoManager) Remove(pkgs []string) error {
+	return runCommand("cargo", append([]string{"uninstall"}, pkgs...)...)
+}
+
+// Update reinstalls every crates.io crate whose latest published version
+// differs from the installed one. Git and path installs are left alone.
+func (cargoManager) Update() error {
+	crates, err := loadCargoInstalledCrates()
+	if err != nil {
+		return err
+	}
+	outdated := make([]string, 0)
+	for _, crate := range crates {
+		if !crate.Registry {
+			continue
+		}
+		latest, err := cargoLatestVersion(crate.Name)
+		if err != nil || latest == "" || latest == crate.Version {
+			continue
+		}
+		fmt.Printf("%s %s -> %s\n", crate.Name, crate.Version, latest)
+		outdated = append(outdated, crate.Name)
+	}
+	if len(outdated) == 0 {
+		fmt.Println("All cargo crates are up to date.")
+		return nil
+	}
+	if cargoBinstallAvailable() {
+		return runCommand("cargo", append([]string{"binstall", "--no-confirm", "--force"}, outdated...)...)
+	}
+	return runCommand("cargo", append([]string{"install", "--force"}, outdated...)...)
+}
+
+// Refresh is a no-op: cargo search queries crates.io directly and keeps no
+// local index that fpf needs to update.
+func (cargoManager) Refresh() error {
+	return nil
+}
+
+func (cargoManager) ShowInfo(pkg string) error {
+	if err := runCommandQuietErr("cargo", "info", pkg); err == nil {
+		return nil
+	}
+	return runCommandQuietErr("cargo", "search", "--limit", "1", "--color", "never", pkg)
+}
+
+func cargoLatestVersion(name string) (string, error) {
+	out, err := runOutputQuietErr("cargo", "search", "--limit", "1", "--color", "never", name)
+	if err != nil {
+		return "", err
+	}
+	for _, entry := range parseCargoSearchEntries(out) {
+		if entry.Name == name {
+			return entry.Version, nil
+		}
+	}
+	return "", nil
+}
+
+type cargoSearchEntry struct {
+	Name    string
+	Version string
+	Desc    string
+}
+
+// parseCargoSearchEntries reads `name = "version"    # description` lines
+// and skips the trailing "... and N crates more" note.
+func parseCargoSearchEntries(out []byte) []cargoSearchEntry {
+	entries := make([]cargoSearchEntry, 0)
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "...") || strings.HasPrefix(line, "note:") {
+			continue
+		}
+		head, desc, _ := strings.Cut(line, "#")
+		name, version, ok := strings.Cut(head, "=")
+		if !ok {
+			continue
+		}
+		name = strings.TrimSpace(name)
+		if name == "" || strings.ContainsAny(name, " \t") {
+			continue
+		}
+		entries = append(entries, cargoSearchEntry{
+			Name:    name,
+			Version: strings.Trim(strings.TrimSpace(version), `"`),
+			Desc:    strings.TrimSpace(desc),
+		})
+	}
+	return entries
+}
+
+func parseCargoSearch(out []byte) []searchRow {
+	entries := parseCargoSearchEntries(out)
+	rows := make([]searchRow, 0, len(entries))
+	for _, entry := range entries {
+		desc := entry.Desc
+		if desc == "" {
+			desc = "-"
+		}
+		rows = append(rows, searchRow{Name: entry.Name, Desc: desc})
+	}
+	return rows
+}
+
+func loadCargoInstalledCrates() ([]cargoInstalledCrate, error) {
+	home := cargoHomePath()
+	if home == "" {
+		return nil, fmt.Errorf("cargo home not found")
+	}
+	raw, err := os.ReadFile(filepath.Join(home, ".crates2.json"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return parseCargoCrates2(raw)
+}
+
+// parseCargoCrates2 reads cargo's install tracker, whose keys look like
+// "ripgrep 14.1.1 (registry+https://github.com/rust-lang/crates.io-index)".
+func parseCargoCrates2(raw []byte) ([]cargoInstalledCrate, error) {
+	var doc struct {
+		Installs map[string]json.RawMessage `json:"installs"`
+	}
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return nil, err
+	}
+	crates := make([]cargoInstalledCrate, 0, len(doc.Installs))
+	for key := range doc.Installs {
+		parts := strings.Fields(key)
+		if len(parts) < 2 {
+			continue
+		}
+		source := ""
+		if len(parts) > 2 {
+			source = strings.Trim(strings.Join(parts[2:], " "), "()")
+		}
+		crates = append(crates, cargoInstalledCrate{
+			Name:     parts[0],
+			Version:  parts[1],
+			Registry: strings.HasPrefix(source, "registry+") || strings.HasPrefix(source, "sparse+"),
+		})
+	}
+	sort.Slice(crates, func(i, j int) bool {
+		return crates[i].Name < crates[j].Name
+	})
+	return crates, nil
+}
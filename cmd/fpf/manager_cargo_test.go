@@ -0,0 +1,89 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseCargoSearch(t *testing.T) {
+	rows := parseCargoSearch(readFixture(t, "cargo-search.txt"))
+	if len(rows) != 3 {
+		t.Fatalf("expected 3 cargo rows, got %+v", rows)
+	}
+	want := searchRow{Name: "ripgrep_all", Desc: "rga: ripgrep, but also search in PDFs, E-Books, Office documents, zip, tar.gz, etc."}
+	if rows[1] != want {
+		t.Fatalf("row 1=%+v want %+v", rows[1], want)
+	}
+
+	entries := parseCargoSearchEntries(readFixture(t, "cargo-search.txt"))
+	if entries[0].Name != "ripgrep" || entries[0].Version != "14.1.1" {
+		t.Fatalf("unexpected first entry %+v", entries[0])
+	}
+}
+
+func TestParseCargoCrates2(t *testing.T) {
+	crates, err := parseCargoCrates2(readFixture(t, "cargo-crates2.json"))
+	if err != nil {
+		t.Fatalf("parseCargoCrates2: %v", err)
+	}
+	got := make([]string, 0, len(crates))
+	for _, crate := range crates {
+		got = append(got, crate.Name+"@"+crate.Version)
+	}
+	if strings.Join(got, ",") != "cargo-binstall@1.10.15,mytool@0.1.0,ripgrep@14.1.0" {
+		t.Fatalf("unexpected crates %v", got)
+	}
+	if !crates[0].Registry || crates[1].Registry || !crates[2].Registry {
+		t.Fatalf("unexpected registry flags %+v", crates)
+	}
+}
+
+func TestCargoUpdateReinstallsOutdatedCrates(t *testing.T) {
+	home := t.TempDir()
+	if err := os.WriteFile(filepath.Join(home, ".crates2.json"), readFixture(t, "cargo-crates2.json"), 0o644); err != nil {
+		t.Fatalf("write crates2.json: %v", err)
+	}
+	t.Setenv("CARGO_HOME", home)
+
+	bin := t.TempDir()
+	logFile := filepath.Join(t.TempDir(), "cargo.log")
+	writeMockExecutable(t, bin, "cargo", `#!/usr/bin/env bash
+printf '%s\n' "$*" >>"`+logFile+`"
+if [[ "${1:-}" == "search" ]]; then
+    case "${!#}" in
+        ripgrep) printf 'ripgrep = "14.1.1"    # line-oriented search\n' ;;
+        cargo-binstall) printf 'cargo-binstall = "1.10.15"    # binary installs\n' ;;
+    esac
+fi
+`)
+	t.Setenv("PATH", bin+":/usr/bin:/bin")
+
+	if err := (cargoManager{}).Update(); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	raw, err := os.ReadFile(logFile)
+	if err != nil {
+		t.Fatalf("read cargo log: %v", err)
+	}
+	log := string(raw)
+	if !strings.Contains(log, "install --force ripgrep\n") {
+		t.Fatalf("expected outdated ripgrep to be reinstalled, log:\n%s", log)
+	}
+	if strings.Contains(log, "mytool") || strings.Contains(log, "--force cargo-binstall") {
+		t.Fatalf("expected only outdated registry crates to be touched, log:\n%s", log)
+	}
+
+	writeMockExecutable(t, bin, "cargo-binstall", "#!/usr/bin/env bash\nexit 0\n")
+	if err := os.WriteFile(logFile, nil, 0o644); err != nil {
+		t.Fatalf("reset cargo log: %v", err)
+	}
+	if err := (cargoManager{}).Install([]string{"ripgrep"}); err != nil {
+		t.Fatalf("Install: %v", err)
+	}
+	raw, _ = os.ReadFile(logFile)
+	if !strings.Contains(string(raw), "binstall --no-confirm ripgrep") {
+		t.Fatalf("expected cargo-binstall to be preferred, log:\n%s", raw)
+	}
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+type chocoManager struct {
+	managerSpec
+}
+
+func init() {
+	registerManager(chocoManager{managerSpec{
+		name:     "choco",
+		label:    "Chocolatey",
+		order:    80,
+		binaries: []string{"choco"},
+	}})
+}
+
+func (chocoManager) Search(input searchInput) ([]searchRow, error) {
+	out, err := input.runOutput("choco", "search", input.Query, "--limit-output")
+	if err != nil {
+		return nil, err
+	}
+	return parseChocoSearch(out), nil
+}
+
+func (chocoManager) ListInstalled() ([]string, error) {
+	out, err := runOutputQuietErr("choco", "list", "--local-only", "--limit-output")
+	if err != nil {
+		return nil, err
+	}
+	return parseChocoInstalled(out), nil
+}
+
+func (chocoManager) Install(pkgs []string) error {
+	return runCommand("choco", append([]string{"install"}, append(pkgs, "-y")...)...)
+}
+
+func (chocoManager) Remove(pkgs []string) error {
+	return runCommand("choco", append([]string{"uninstall"}, append(pkgs, "-y")...)...)
+}
+
+func (chocoManager) Update() error {
+	return runCommand("choco", "upgrade", "all", "-y")
+}
+
+func (chocoManager) Refresh() error {
+	cmd := exec.Command("choco", "source", "list", "--limit-output")
+	cmd.Env = os.Environ()
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = io.Discard
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+func (chocoManager) ShowInfo(pkg string) error {
+	return runCommandQuietErr("choco", "info", pkg)
+}
+
+func (chocoManager) InstallFzf() error {
+	return runCommand("choco", "install", "fzf", "-y")
+}
+
+func parseChocoSearch(out []byte) []searchRow {
+	rows := make([]searchRow, 0)
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		parts := strings.SplitN(line, "|", 2)
+		name := strings.TrimSpace(parts[0])
+		if name == "" {
+			continue
+		}
+		ver := "-"
+		if len(parts) == 2 && strings.TrimSpace(parts[1]) != "" {
+			ver = strings.TrimSpace(parts[1])
+		}
+		rows = append(rows, searchRow{Name: name, Desc: "version " + ver})
+	}
+	return rows
+}
+
+func parseChocoInstalled(out []byte) []string {
+	names := make([]string, 0)
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		parts := strings.SplitN(line, "|", 2)
+		if len(parts) > 0 && parts[0] != "" {
+			names = append(names, strings.TrimSpace(parts[0]))
+		}
+	}
+	return names
+}
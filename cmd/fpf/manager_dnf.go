@@ -0,0 +1,495 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+type dnfManager struct {
+	managerSpec
+}
+
+func init() {
+	registerManager(dnfManager{managerSpec{
+		name:     "dnf",
+		label:    "DNF",
+		order:    20,
+		binaries: []string{"dnf"},
+		root:     true,
+	}})
+}
+
+// Search filters the cached repository metadata catalog. `dnf list`, which
+// takes seconds, only runs when no primary metadata could be read.
+func (dnfManager) Search(input searchInput) ([]searchRow, error) {
+	if catalogRows, err := loadDnfCatalogRows(input.Query); err == nil {
+		return catalogRows, nil
+	}
+	pattern := "*"
+	if input.Query != "" {
+		pattern = "*" + input.Query + "*"
+	}
+	out, err := input.runOutput("dnf", "-q", "list", "available", pattern)
+	if err != nil {
+		return nil, err
+	}
+	return parseDNFSearch(out), nil
+}
+
+func (dnfManager) ListInstalled() ([]string, error) {
+	out, err := runOutputQuietErr("dnf", "-q", "list", "installed")
+	if err != nil {
+		return nil, err
+	}
+	return parseDnfInstalled(out), nil
+}
+
+func (dnfManager) Install(pkgs []string) error {
+	return runRootCommand("dnf", append([]string{"install", "-y"}, pkgs...)...)
+}
+
+func (dnfManager) Remove(pkgs []string) error {
+	return runRootCommand("dnf", append([]string{"remove", "-y"}, pkgs...)...)
+}
+
+func (dnfManager) Update() error {
+	return runRootCommand("dnf", "upgrade", "-y")
+}
+
+func (dnfManager) Refresh() error {
+	return runRootCommand("dnf", "makecache")
+}
+
+func (dnfManager) ShowInfo(pkg string) error {
+	runCommandQuietErr("dnf", "info", pkg)
+	fmt.Println()
+	runCommandQuietErr("rpm", "-ql", pkg)
+	return nil
+}
+
+func (dnfManager) InstallFzf() error {
+	return runRootCommand("dnf", "install", "-y", "fzf")
+}
+
+func parseDNFSearch(out []byte) []searchRow {
+	rows := make([]searchRow, 0)
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "Available") || strings.HasPrefix(line, "Last") || strings.HasPrefix(line, "Installed") {
+			continue
+		}
+		parts := strings.Fields(line)
+		if len(parts) < 2 {
+			continue
+		}
+		name := parts[0]
+		if idx := strings.LastIndex(name, "."); idx > 0 {
+			name = name[:idx]
+		}
+		rows = append(rows, searchRow{Name: name, Desc: parts[1]})
+	}
+	return rows
+}
+
+func parseDnfInstalled(out []byte) []string {
+	names := make([]string, 0)
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "Installed") || strings.HasPrefix(line, "Last") || strings.HasPrefix(line, "Available") {
+			continue
+		}
+		parts := strings.Fields(line)
+		if len(parts) > 0 {
+			name := parts[0]
+			if idx := strings.LastIndex(name, "."); idx > 0 {
+				name = name[:idx]
+			}
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// dnfRoot is the filesystem root the dnf metadata cache is read from.
+// FPF_DNF_ROOT points it at a chroot or a test tree.
+func dnfRoot() string {
+	if root := strings.TrimSpace(os.Getenv("FPF_DNF_ROOT")); root != "" {
+		return root
+	}
+	return "/"
+}
+
+// dnfCacheRepoSuffix matches the hash dnf appends to repo cache directories,
+// e.g. "updates-2d95c80a1fa0a67d".
+var dnfCacheRepoSuffix = regexp.MustCompile(`-[0-9a-f]{16}$`)
+
+// dnfPrimaryFiles maps each cached repo to its newest primary metadata
+// file, looking in both the dnf 4 and dnf 5 cache directories. Fedora
+// caches zchunk (`.zck`) metadata by default; it is only used when unzck is
+// installed to expand it, otherwise a `.gz`, `.xz` or `.zst` sibling is
+// read and repos with nothing else are skipped. The libsolv
+// `.solv` caches are not read: their format is internal to libsolv.
+func dnfPrimaryFiles() map[string]string {
+	_, unzckErr := exec.LookPath("unzck")
+	files := make(map[string]string)
+	newest := make(map[string]int64)
+	for _, cacheDir := range []string{"dnf", "libdnf5"} {
+		pattern := filepath.Join(dnfRoot(), "var", "cache", cacheDir, "*", "repodata", "*primary.xml*")
+		matches, _ := filepath.Glob(pattern)
+		for _, path := range matches {
+			if strings.HasSuffix(path, ".zck") && unzckErr != nil {
+				continue
+			}
+			info, err := os.Stat(path)
+			if err != nil {
+				continue
+			}
+			repo := dnfCacheRepoSuffix.ReplaceAllString(filepath.Base(filepath.Dir(filepath.Dir(path))), "")
+			if mtime := info.ModTime().UnixNano(); files[repo] == "" || mtime > newest[repo] {
+				files[repo] = path
+				newest[repo] = mtime
+			}
+		}
+	}
+	return files
+}
+
+// dnfCatalogFingerprint keys the catalog on the path, size and mtime of
+// every primary metadata file, so `dnf makecache` invalidates it.
+func dnfCatalogFingerprint() string {
+	files := dnfPrimaryFiles()
+	repos := make([]string, 0, len(files))
+	for repo := range files {
+		repos = append(repos, repo)
+	}
+	sort.Strings(repos)
+	parts := []string{"1", "dnf", "primary", dnfRoot()}
+	for _, repo := range repos {
+		stamp := "missing"
+		if info, err := os.Stat(files[repo]); err == nil {
+			stamp = fmt.Sprintf("%d:%d", info.ModTime().UnixNano(), info.Size())
+		}
+		parts = append(parts, repo+"="+filepath.Base(files[repo])+":"+stamp)
+	}
+	return strings.Join(parts, "|")
+}
+
+func loadDnfCatalogRows(q string) ([]searchRow, error) {
+	files := dnfPrimaryFiles()
+	if len(files) == 0 {
+		return nil, fmt.Errorf("dnf catalog: no readable primary metadata under %s", filepath.Join(dnfRoot(), "var", "cache"))
+	}
+	key := cacheChecksum(dnfCatalogFingerprint())
+	cachePath := filepath.Join(cacheRootPath(), "search-catalog", "dnf", key+".tsv")
+
+	if raw, err := os.ReadFile(cachePath); err == nil {
+		rows := parseCachedRows(raw)
+		if len(rows) > 0 {
+			return filterCatalogRows(rows, q), nil
+		}
+	}
+
+	rows, err := buildDnfCatalogRows(files)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("dnf catalog: no packages in the primary metadata")
+	}
+
+	_ = os.MkdirAll(filepath.Dir(cachePath), 0o755)
+	_ = os.WriteFile(cachePath, []byte(renderAPT(rows)), 0o644)
+
+	return filterCatalogRows(rows, q), nil
+}
+
+// dnfPackage is one <package> entry of a primary.xml file.
+type dnfPackage struct {
+	Name    string `xml:"name"`
+	Arch    string `xml:"arch"`
+	Summary string `xml:"summary"`
+	Version struct {
+		Epoch   string `xml:"epoch,attr"`
+		Ver     string `xml:"ver,attr"`
+		Release string `xml:"rel,attr"`
+	} `xml:"version"`
+	Size struct {
+		Installed int64 `xml:"installed,attr"`
+	} `xml:"size"`
+	Repo string `xml:"-"`
+}
+
+// evr renders the package version as dnf prints it, with the epoch only
+// when it is not zero.
+func (p dnfPackage) evr() string {
+	evr := p.Version.Ver
+	if p.Version.Release != "" {
+		evr += "-" + p.Version.Release
+	}
+	if p.Version.Epoch != "" && p.Version.Epoch != "0" {
+		evr = p.Version.Epoch + ":" + evr
+	}
+	return evr
+}
+
+// buildDnfCatalogRows reads every repo's primary metadata, keeping the
+// newest version of each package. Packages built for the machine's own
+// architecture or noarch are preferred over multilib builds.
+func buildDnfCatalogRows(files map[string]string) ([]searchRow, error) {
+	repos := make([]string, 0, len(files))
+	for repo := range files {
+		repos = append(repos, repo)
+	}
+	sort.Strings(repos)
+
+	index := make(map[string]int)
+	kept := make([]dnfPackage, 0)
+	var firstErr error
+	for _, repo := range repos {
+		packages, err := readDnfPrimary(files[repo])
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+		for _, pkg := range packages {
+			pkg.Repo = repo
+			i, ok := index[pkg.Name]
+			if !ok {
+				index[pkg.Name] = len(kept)
+				kept = append(kept, pkg)
+				continue
+			}
+			if dnfPreferPackage(pkg, kept[i]) {
+				kept[i] = pkg
+			}
+		}
+	}
+	if len(kept) == 0 && firstErr != nil {
+		return nil, firstErr
+	}
+
+	rows := make([]searchRow, 0, len(kept))
+	for _, pkg := range kept {
+		rows = append(rows, searchRow{Name: pkg.Name, Desc: dnfRowDesc(pkg)})
+	}
+	return rows, nil
+}
+
+func dnfPreferPackage(candidate, current dnfPackage) bool {
+	candidateNative, currentNative := dnfNativeArch(candidate.Arch), dnfNativeArch(current.Arch)
+	if candidateNative != currentNative {
+		return candidateNative
+	}
+	return compareRPMVersions(candidate.evr(), current.evr()) > 0
+}
+
+// dnfNativeArch reports whether arch runs natively on this machine.
+func dnfNativeArch(arch string) bool {
+	switch arch {
+	case "noarch":
+		return true
+	case "x86_64":
+		return runtime.GOARCH == "amd64"
+	case "aarch64":
+		return runtime.GOARCH == "arm64"
+	case "i686":
+		return runtime.GOARCH == "386"
+	default:
+		return arch == runtime.GOARCH
+	}
+}
+
+// dnfRowDesc renders "[repo] summary (version, arch, size)".
+func dnfRowDesc(pkg dnfPackage) string {
+	parts := make([]string, 0, 3)
+	if pkg.Repo != "" {
+		parts = append(parts, "["+pkg.Repo+"]")
+	}
+	if summary := strings.Join(strings.Fields(pkg.Summary), " "); summary != "" {
+		parts = append(parts, summary)
+	}
+	extra := make([]string, 0, 3)
+	for _, field := range []string{pkg.evr(), pkg.Arch} {
+		if field != "" {
+			extra = append(extra, field)
+		}
+	}
+	if pkg.Size.Installed > 0 {
+		extra = append(extra, formatAptInstalledSize(pkg.Size.Installed/1024))
+	}
+	if len(extra) > 0 {
+		parts = append(parts, "("+strings.Join(extra, ", ")+")")
+	}
+	if len(parts) == 0 {
+		return "-"
+	}
+	return strings.Join(parts, " ")
+}
+
+func readDnfPrimary(path string) ([]dnfPackage, error) {
+	reader, err := openDecompressed(path)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	return parseDnfPrimary(reader)
+}
+
+// parseDnfPrimary streams the <package> entries of a primary.xml file,
+// skipping source packages.
+func parseDnfPrimary(reader io.Reader) ([]dnfPackage, error) {
+	packages := make([]dnfPackage, 0)
+	decoder := xml.NewDecoder(reader)
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return packages, nil
+		}
+		if err != nil {
+			return packages, err
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Local != "package" {
+			continue
+		}
+		var pkg dnfPackage
+		if err := decoder.DecodeElement(&pkg, &start); err != nil {
+			return packages, err
+		}
+		if pkg.Name == "" || pkg.Arch == "src" || pkg.Arch == "nosrc" {
+			continue
+		}
+		packages = append(packages, pkg)
+	}
+}
+
+// compareRPMVersions orders two [epoch:]version[-release] strings the way
+// rpm does.
+func compareRPMVersions(a, b string) int {
+	epochA, restA := splitVersionEpoch(a)
+	epochB, restB := splitVersionEpoch(b)
+	if epochA != epochB {
+		if epochA < epochB {
+			return -1
+		}
+		return 1
+	}
+	versionA, releaseA := splitVersionRelease(restA)
+	versionB, releaseB := splitVersionRelease(restB)
+	if cmp := rpmVerCmp(versionA, versionB); cmp != 0 {
+		return cmp
+	}
+	return rpmVerCmp(releaseA, releaseB)
+}
+
+// rpmVerCmp is rpm's rpmvercmp: alphanumeric segments compare numerically
+// or lexically, numbers beat letters, "~" sorts before everything and "^"
+// sorts after the base version.
+func rpmVerCmp(a, b string) int {
+	if a == b {
+		return 0
+	}
+	isDigit := func(c byte) bool { return c >= '0' && c <= '9' }
+	isAlpha := func(c byte) bool { return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') }
+	isSeparator := func(c byte) bool { return !isDigit(c) && !isAlpha(c) && c != '~' && c != '^' }
+
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		for i < len(a) && isSeparator(a[i]) {
+			i++
+		}
+		for j < len(b) && isSeparator(b[j]) {
+			j++
+		}
+
+		aTilde, bTilde := i < len(a) && a[i] == '~', j < len(b) && b[j] == '~'
+		if aTilde || bTilde {
+			if !aTilde {
+				return 1
+			}
+			if !bTilde {
+				return -1
+			}
+			i++
+			j++
+			continue
+		}
+
+		aCaret, bCaret := i < len(a) && a[i] == '^', j < len(b) && b[j] == '^'
+		if aCaret || bCaret {
+			if i >= len(a) {
+				return -1
+			}
+			if j >= len(b) {
+				return 1
+			}
+			if !aCaret {
+				return 1
+			}
+			if !bCaret {
+				return -1
+			}
+			i++
+			j++
+			continue
+		}
+
+		if i >= len(a) || j >= len(b) {
+			break
+		}
+
+		startA, startB := i, j
+		numeric := isDigit(a[i])
+		class := isAlpha
+		if numeric {
+			class = isDigit
+		}
+		for i < len(a) && class(a[i]) {
+			i++
+		}
+		for j < len(b) && class(b[j]) {
+			j++
+		}
+		if j == startB {
+			if numeric {
+				return 1
+			}
+			return -1
+		}
+
+		segA, segB := a[startA:i], b[startB:j]
+		if numeric {
+			segA = strings.TrimLeft(segA, "0")
+			segB = strings.TrimLeft(segB, "0")
+			if len(segA) != len(segB) {
+				if len(segA) < len(segB) {
+					return -1
+				}
+				return 1
+			}
+		}
+		if cmp := strings.Compare(segA, segB); cmp != 0 {
+			return cmp
+		}
+	}
+
+	switch {
+	case i >= len(a) && j >= len(b):
+		return 0
+	case i < len(a):
+		return 1
+	default:
+		return -1
+	}
+}
@@ -0,0 +1,193 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeDnfCache builds a dnf cache with the primary fixture under the
+// "fedora" repo and a newer ripgrep build, gzipped, under "updates".
+func writeDnfCache(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+	updates := `<?xml version="1.0" encoding="UTF-8"?>
+<metadata xmlns="http://linux.duke.edu/metadata/common" packages="1">
+<package type="rpm">
+  <name>ripgrep</name>
+  <arch>x86_64</arch>
+  <version epoch="0" ver="14.1.1" rel="1.fc40"/>
+  <summary>Line-oriented search tool</summary>
+  <size package="1540321" installed="4718592" archive="4722000"/>
+</package>
+</metadata>
+`
+	files := map[string][]byte{
+		"var/cache/dnf/fedora-2d95c80a1fa0a67d/repodata/0a1b2c-primary.xml":         readFixture(t, "dnf-primary.xml"),
+		"var/cache/libdnf5/updates-8f3e2b6a4c1d9e70/repodata/9f8e7d-primary.xml.gz": gzipBytes(t, []byte(updates)),
+	}
+	for rel, raw := range files {
+		path := filepath.Join(root, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, raw, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+func TestParseDnfPrimary(t *testing.T) {
+	packages, err := parseDnfPrimary(bytes.NewReader(readFixture(t, "dnf-primary.xml")))
+	if err != nil {
+		t.Fatalf("parseDnfPrimary: %v", err)
+	}
+	if len(packages) != 4 {
+		t.Fatalf("expected source packages to be skipped, got %+v", packages)
+	}
+	rg := packages[0]
+	if rg.Name != "ripgrep" || rg.Arch != "x86_64" || rg.evr() != "14.1.0-1.fc40" || rg.Summary != "Line-oriented search tool" || rg.Size.Installed != 4718592 {
+		t.Fatalf("unexpected ripgrep entry %+v", rg)
+	}
+	if doc := packages[3]; doc.evr() != "1:2.0-1.fc40" {
+		t.Fatalf("expected epoch in version, got %q", doc.evr())
+	}
+}
+
+func TestLoadDnfCatalogRowsMergesRepos(t *testing.T) {
+	if runtime.GOARCH != "amd64" {
+		t.Skip("fixture prefers x86_64 builds")
+	}
+	t.Setenv("FPF_DNF_ROOT", writeDnfCache(t))
+	t.Setenv("FPF_CACHE_DIR", t.TempDir())
+
+	rows, err := loadDnfCatalogRows("")
+	if err != nil {
+		t.Fatalf("loadDnfCatalogRows: %v", err)
+	}
+	want := []searchRow{
+		{Name: "ripgrep", Desc: "[updates] Line-oriented search tool (14.1.1-1.fc40, x86_64, 4.5 MB)"},
+		{Name: "pcre2", Desc: "[fedora] Perl-compatible regular expression library (10.42-2.fc40, x86_64, 664 kB)"},
+		{Name: "ripgrep-doc", Desc: "[fedora] Documentation for ripgrep (1:2.0-1.fc40, noarch)"},
+	}
+	if !reflect.DeepEqual(rows, want) {
+		t.Fatalf("rows=%v\nwant %v", rows, want)
+	}
+	if rows, _ := loadDnfCatalogRows("fedora"); len(rows) != 0 {
+		t.Fatalf("repo tag should not match queries, got %v", rows)
+	}
+}
+
+func TestDnfSearchSkipsDnfForUnmatchedQueries(t *testing.T) {
+	t.Setenv("FPF_DNF_ROOT", writeDnfCache(t))
+	t.Setenv("FPF_CACHE_DIR", t.TempDir())
+	bin := t.TempDir()
+	logFile := filepath.Join(t.TempDir(), "dnf.log")
+	writeMockExecutable(t, bin, "dnf", "#!/bin/sh\nprintf '%s\\n' \"$*\" >>\""+logFile+"\"\n")
+	t.Setenv("PATH", bin+":/usr/bin:/bin")
+
+	rows, err := (dnfManager{}).Search(searchInput{Query: "no-such-package"})
+	if err != nil || len(rows) != 0 {
+		t.Fatalf("expected no rows, got %+v, %v", rows, err)
+	}
+	if raw, _ := os.ReadFile(logFile); len(raw) != 0 {
+		t.Fatalf("expected no dnf call once the catalog loads, got %q", raw)
+	}
+
+	t.Setenv("FPF_DNF_ROOT", t.TempDir())
+	if _, err := (dnfManager{}).Search(searchInput{Query: "ripgrep"}); err != nil {
+		t.Fatalf("search: %v", err)
+	}
+	if raw, _ := os.ReadFile(logFile); !strings.Contains(string(raw), "list available *ripgrep*") {
+		t.Fatalf("expected dnf list without primary metadata, got %q", raw)
+	}
+}
+
+func TestDnfCatalogFingerprintTracksPrimaryFiles(t *testing.T) {
+	root := writeDnfCache(t)
+	t.Setenv("FPF_DNF_ROOT", root)
+
+	before := dnfCatalogFingerprint()
+	if !strings.Contains(before, "|fedora=") || !strings.Contains(before, "|updates=") {
+		t.Fatalf("expected repo ids without cache hashes, got %q", before)
+	}
+	later := time.Now().Add(time.Hour)
+	path := filepath.Join(root, "var", "cache", "dnf", "fedora-2d95c80a1fa0a67d", "repodata", "0a1b2c-primary.xml")
+	if err := os.Chtimes(path, later, later); err != nil {
+		t.Fatal(err)
+	}
+	if after := dnfCatalogFingerprint(); after == before {
+		t.Fatalf("expected fingerprint to change after dnf makecache, still %q", after)
+	}
+}
+
+func TestDnfPrimaryFilesHandlesZchunk(t *testing.T) {
+	root := writeDnfCache(t)
+	t.Setenv("FPF_DNF_ROOT", root)
+	later := time.Now().Add(time.Hour)
+	for _, rel := range []string{
+		"var/cache/dnf/fedora-2d95c80a1fa0a67d/repodata/ff00-primary.xml.zck",
+		"var/cache/dnf/rawhide-0123456789abcdef/repodata/aa11-primary.xml.zck",
+	} {
+		path := filepath.Join(root, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("\x00ZCK1 not expandable here"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, later, later); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	t.Setenv("PATH", t.TempDir())
+	files := dnfPrimaryFiles()
+	if !strings.HasSuffix(files["fedora"], "0a1b2c-primary.xml") {
+		t.Fatalf("expected the plain sibling without unzck, got %q", files["fedora"])
+	}
+	if path, ok := files["rawhide"]; ok {
+		t.Fatalf("expected a zchunk-only repo to be skipped without unzck, got %q", path)
+	}
+
+	bin := t.TempDir()
+	writeMockExecutable(t, bin, "unzck", "#!/bin/sh\ncat \""+filepath.Join("..", "..", "tests", "fixtures", "dnf-primary.xml")+"\"\n")
+	t.Setenv("PATH", bin+":/usr/bin:/bin")
+	files = dnfPrimaryFiles()
+	if !strings.HasSuffix(files["fedora"], ".zck") || !strings.HasSuffix(files["rawhide"], ".zck") {
+		t.Fatalf("expected zchunk metadata with unzck installed, got %v", files)
+	}
+	packages, err := readDnfPrimary(files["rawhide"])
+	if err != nil || len(packages) != 4 {
+		t.Fatalf("expected unzck to expand the primary metadata, got %d packages, %v", len(packages), err)
+	}
+}
+
+func TestCompareRPMVersions(t *testing.T) {
+	cases := []struct {
+		a, b string
+		want int
+	}{
+		{"1.0-1", "1.0-1", 0},
+		{"1.0-1", "1.0-2", -1},
+		{"1.10-1", "1.9-1", 1},
+		{"1.0~rc1-1", "1.0-1", -1},
+		{"1.0^git1-1", "1.0-1", 1},
+		{"1.0^git1-1", "1.0.1-1", -1},
+		{"1:1.0-1", "2.0-1", 1},
+		{"2.0a-1", "2.0-1", 1},
+		{"2.0-1.fc40", "2.0-1.fc39", 1},
+		{"1.0.010-1", "1.0.9-1", 1},
+	}
+	for _, tc := range cases {
+		if got := compareRPMVersions(tc.a, tc.b); got != tc.want {
+			t.Errorf("compareRPMVersions(%q, %q)=%d want %d", tc.a, tc.b, got, tc.want)
+		}
+	}
+}
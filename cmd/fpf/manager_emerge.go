@@ -0,0 +1,109 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+)
+
+type emergeManager struct {
+	managerSpec
+}
+
+func init() {
+	registerManager(emergeManager{managerSpec{
+		name:     "emerge",
+		label:    "Portage (emerge)",
+		order:    50,
+		binaries: []string{"emerge"},
+		root:     true,
+	}})
+}
+
+func (emergeManager) Search(input searchInput) ([]searchRow, error) {
+	out, err := input.runOutput("emerge", "--searchdesc", "--color=n", input.Query)
+	if err != nil {
+		return nil, err
+	}
+	return parseEmergeSearch(out), nil
+}
+
+func (emergeManager) ListInstalled() ([]string, error) {
+	out, err := runOutputQuietErr("qlist", "-ICv")
+	if err != nil {
+		return nil, err
+	}
+	return parseEmergeInstalled(out), nil
+}
+
+func (emergeManager) Install(pkgs []string) error {
+	return runRootCommand("emerge", append([]string{"--ask=n", "--verbose"}, pkgs...)...)
+}
+
+func (emergeManager) Remove(pkgs []string) error {
+	if err := runRootCommand("emerge", append([]string{"--ask=n", "--deselect"}, pkgs...)...); err != nil {
+		return err
+	}
+	return runRootCommand("emerge", append([]string{"--ask=n", "--depclean"}, pkgs...)...)
+}
+
+func (emergeManager) Update() error {
+	if err := runRootCommand("emerge", "--sync"); err != nil {
+		return err
+	}
+	return runRootCommand("emerge", "--ask=n", "--update", "--deep", "--newuse", "@world")
+}
+
+func (emergeManager) Refresh() error {
+	return runRootCommand("emerge", "--sync")
+}
+
+func (emergeManager) ShowInfo(pkg string) error {
+	return runCommandQuietErr("emerge", "--search", "--color=n", pkg)
+}
+
+func (emergeManager) InstallFzf() error {
+	return runRootCommand("emerge", "--ask=n", "app-shells/fzf")
+}
+
+func parseEmergeSearch(out []byte) []searchRow {
+	rows := make([]searchRow, 0)
+	var atom string
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "*  ") {
+			parts := strings.Fields(line)
+			if len(parts) >= 2 {
+				atom = parts[1]
+			}
+			continue
+		}
+		trim := strings.TrimSpace(line)
+		if strings.HasPrefix(trim, "Description:") && atom != "" {
+			desc := strings.TrimSpace(strings.TrimPrefix(trim, "Description:"))
+			if desc == "" {
+				desc = "-"
+			}
+			rows = append(rows, searchRow{Name: atom, Desc: desc})
+			atom = ""
+		}
+	}
+	return rows
+}
+
+func parseEmergeInstalled(out []byte) []string {
+	names := make([]string, 0)
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		parts := strings.Fields(line)
+		if len(parts) > 0 {
+			names = append(names, parts[0])
+		}
+	}
+	return names
+}
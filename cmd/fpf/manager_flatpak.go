@@ -0,0 +1,424 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Timmy6942025/fpf-cli/internal/flatpak"
+)
+
+type flatpakManager struct {
+	managerSpec
+}
+
+func init() {
+	registerManager(flatpakManager{managerSpec{
+		name:     "flatpak",
+		label:    "Flatpak",
+		order:    110,
+		binaries: []string{"flatpak"},
+	}})
+	flatpak.PreparsedCacheDir = func() string {
+		return filepath.Join(cacheRootPath(), "flatpak-appstream")
+	}
+}
+
+// flatpakMatchScores places the appstream match tiers on scoreRows' scale:
+// exact and prefix matches rank like an exact or prefix package name, and a
+// keyword, summary or description match like a description hit.
+var flatpakMatchScores = map[int]int{
+	flatpak.MatchExact:       0,
+	flatpak.MatchPrefix:      1,
+	flatpak.MatchToken:       2,
+	flatpak.MatchKeyword:     4,
+	flatpak.MatchSummary:     5,
+	flatpak.MatchDescription: 6,
+}
+
+func flatpakCacheRows(cache *flatpak.Cache, query string) []searchRow {
+	results := cache.Filter(query)
+	rows := make([]searchRow, len(results))
+	for i, r := range results {
+		rows[i] = searchRow{Name: r.Name, Desc: r.Desc}
+		if score, ok := flatpakMatchScores[r.Score]; ok {
+			rows[i].Rank = rankFromScore(score)
+		}
+	}
+	return rows
+}
+
+func (flatpakManager) Search(input searchInput) ([]searchRow, error) {
+	query := input.Query
+	if flatpak.ShouldUseDirectCache() {
+		cache, err := flatpak.LoadBest()
+		if err == nil && len(cache.Apps) > 0 {
+			return flatpakCacheRows(cache, query), nil
+		}
+		if err == flatpak.ErrNoCache {
+			_ = flatpak.UpdateAppStream()
+			cache, refreshErr := flatpak.LoadBest()
+			if refreshErr == nil && len(cache.Apps) > 0 {
+				return flatpakCacheRows(cache, query), nil
+			}
+		}
+	}
+	if query == "" {
+		out, err := input.runOutput("flatpak", "remote-ls", "--app", "--columns=application,description", "flathub")
+		if err != nil {
+			if errors.Is(err, context.DeadlineExceeded) {
+				return nil, err
+			}
+			out, err = input.runOutput("flatpak", "remote-ls", "--app", "--columns=application,description")
+			if err != nil {
+				return nil, err
+			}
+		}
+		return parseFlatpakSearch(out), nil
+	}
+	out, err := input.runOutput("flatpak", "search", "--columns=application,description", query)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return nil, err
+		}
+		out, err = input.runOutput("flatpak", "search", query)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return parseFlatpakSearch(out), nil
+}
+
+// ListInstalled reads the user and system installation directories, falling
+// back to `flatpak list` when neither has an app directory.
+func (flatpakManager) ListInstalled() ([]string, error) {
+	if apps, ok := flatpak.ListInstalled(); ok {
+		names := make([]string, 0, len(apps))
+		seen := make(map[string]bool, len(apps))
+		for _, app := range apps {
+			if !seen[app.ID] {
+				seen[app.ID] = true
+				names = append(names, app.ID)
+			}
+		}
+		return names, nil
+	}
+	out, err := runOutputQuietErr("flatpak", "list", "--app", "--columns=application,version")
+	if err != nil {
+		return nil, err
+	}
+	return parseFlatpakInstalled(out), nil
+}
+
+// InstalledDetails describes each installed app as
+// "version (scope, origin, branch)". An app installed for both the user and
+// the system lists both scopes.
+func (m flatpakManager) InstalledDetails() ([]searchRow, error) {
+	apps, ok := flatpak.ListInstalled()
+	if !ok {
+		names, err := m.ListInstalled()
+		if err != nil {
+			return nil, err
+		}
+		rows := make([]searchRow, 0, len(names))
+		for _, name := range names {
+			rows = append(rows, searchRow{Name: name, Desc: "installed"})
+		}
+		return rows, nil
+	}
+
+	order := make([]string, 0, len(apps))
+	byID := make(map[string][]flatpak.InstalledApp, len(apps))
+	for _, app := range apps {
+		if _, ok := byID[app.ID]; !ok {
+			order = append(order, app.ID)
+		}
+		byID[app.ID] = append(byID[app.ID], app)
+	}
+
+	rows := make([]searchRow, 0, len(order))
+	for _, id := range order {
+		deploys := byID[id]
+		scopes := make([]string, 0, len(deploys))
+		for _, deploy := range deploys {
+			scopes = append(scopes, deploy.Scope)
+		}
+		first := deploys[0]
+		version := first.Version
+		if version == "" {
+			version = "installed"
+		}
+		rows = append(rows, searchRow{
+			Name: id,
+			Desc: version + " (" + joinNonEmpty(strings.Join(scopes, "+"), first.Origin, first.Branch) + ")",
+		})
+	}
+	return rows, nil
+}
+
+// flatpakAppDirStamp changes whenever an app is installed into or removed
+// from either installation, since both touch the app directory.
+func flatpakAppDirStamp() string {
+	parts := make([]string, 0, 2)
+	for _, inst := range flatpak.Installations() {
+		stamp := "missing"
+		if info, err := os.Stat(filepath.Join(inst.Dir, "app")); err == nil {
+			stamp = strconv.FormatInt(info.ModTime().UnixNano(), 10)
+		}
+		parts = append(parts, inst.Scope+"="+stamp)
+	}
+	return strings.Join(parts, ",")
+}
+
+// flatpakScopes maps each package to the installations it is deployed in.
+// ok is false when the installation directories could not be read.
+func flatpakScopes() (map[string][]string, bool) {
+	apps, ok := flatpak.ListInstalled()
+	if !ok {
+		return nil, false
+	}
+	scopes := make(map[string][]string, len(apps))
+	for _, app := range apps {
+		scopes[app.ID] = append(scopes[app.ID], app.Scope)
+	}
+	return scopes, true
+}
+
+// flatpakInstalledName is the app id of a row, which installations list
+// whichever remote the row offers it from.
+func flatpakInstalledName(ref string) string {
+	_, id := flatpak.SplitRef(ref)
+	return id
+}
+
+// flatpakRemoteFor returns the remote whose appstream in cache lists pkg,
+// the remote a "remote:id" row names, or flathub. cache may be nil when the
+// appstream data could not be loaded.
+func flatpakRemoteFor(cache *flatpak.Cache, pkg string) string {
+	if cache != nil {
+		if remote, ok := cache.OriginOf(pkg); ok {
+			return remote
+		}
+	}
+	if remote, _ := flatpak.SplitRef(pkg); remote != "" {
+		return remote
+	}
+	return "flathub"
+}
+
+func (flatpakManager) Install(pkgs []string) error {
+	cache, _ := flatpak.LoadLocal()
+	for _, ref := range pkgs {
+		remote := flatpakRemoteFor(cache, ref)
+		_, pkg := flatpak.SplitRef(ref)
+		if err := runCommandQuietErr("flatpak", "install", "-y", "--user", remote, pkg); err == nil {
+			continue
+		}
+		if err := runCommandQuietErr("flatpak", "install", "-y", "--user", pkg); err == nil {
+			continue
+		}
+		if err := runRootCommandQuietErr("flatpak", "install", "-y", remote, pkg); err == nil {
+			continue
+		}
+		if err := runRootCommand("flatpak", "install", "-y", pkg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Remove uninstalls each app from every installation it is deployed in,
+// using sudo only for the system one. Apps not found on disk are tried in the
+// user installation first, then the system one.
+func (flatpakManager) Remove(pkgs []string) error {
+	var user, system, unknown []string
+	scopes, _ := flatpakScopes()
+	for _, ref := range pkgs {
+		_, pkg := flatpak.SplitRef(ref)
+		found := scopes[pkg]
+		if len(found) == 0 {
+			unknown = append(unknown, pkg)
+		}
+		for _, scope := range found {
+			if scope == flatpak.ScopeUser {
+				user = append(user, pkg)
+			} else {
+				system = append(system, pkg)
+			}
+		}
+	}
+
+	var errs []error
+	if len(user) > 0 {
+		errs = append(errs, runCommand("flatpak", append([]string{"uninstall", "-y", "--user"}, user...)...))
+	}
+	if len(system) > 0 {
+		errs = append(errs, runRootCommand("flatpak", append([]string{"uninstall", "-y", "--system"}, system...)...))
+	}
+	if len(unknown) > 0 {
+		if err := runCommandQuietErr("flatpak", append([]string{"uninstall", "-y", "--user"}, unknown...)...); err != nil {
+			errs = append(errs, runRootCommand("flatpak", append([]string{"uninstall", "-y"}, unknown...)...))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Update updates each installation that has something deployed, using sudo
+// only for the system one. Without readable installations it tries the user
+// installation, then the system one.
+func (flatpakManager) Update() error {
+	var errs []error
+	updated := false
+	for _, inst := range flatpak.Installations() {
+		if !inst.InUse() {
+			continue
+		}
+		updated = true
+		if inst.Scope == flatpak.ScopeUser {
+			errs = append(errs, runCommand("flatpak", "update", "-y", "--user"))
+		} else {
+			errs = append(errs, runRootCommand("flatpak", "update", "-y", "--system"))
+		}
+	}
+	if updated {
+		return errors.Join(errs...)
+	}
+
+	if err := runCommandQuietErr("flatpak", "update", "-y", "--user"); err != nil {
+		return runRootCommand("flatpak", "update", "-y")
+	}
+	return nil
+}
+
+func (flatpakManager) Refresh() error {
+	if err := runCommandQuietErr("flatpak", "update", "-y", "--appstream", "--user"); err != nil {
+		return runRootCommand("flatpak", "update", "-y", "--appstream")
+	}
+	return nil
+}
+
+// ShowInfo shows `flatpak info` for installed apps. Other apps are described
+// from the local appstream data when it lists them, before asking the remote.
+func (flatpakManager) ShowInfo(ref string) error {
+	_, pkg := flatpak.SplitRef(ref)
+	if err := runCommandQuietErr("flatpak", "info", pkg); err == nil {
+		return nil
+	}
+	cache, _ := flatpak.LoadLocal()
+	if cache != nil {
+		if app, ok := cache.Lookup(ref); ok {
+			fmt.Print(formatFlatpakAppInfo(app))
+			return nil
+		}
+	}
+	return runCommandQuietErr("flatpak", "remote-info", flatpakRemoteFor(cache, ref), pkg)
+}
+
+// flatpakInfoReleases caps the release history shown by formatFlatpakAppInfo.
+const flatpakInfoReleases = 5
+
+func formatFlatpakAppInfo(app flatpak.App) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s (%s)\n", app.ID, app.Origin)
+
+	version := app.Version
+	if len(app.Releases) > 0 && app.Releases[0].Date != "" {
+		version += " (" + app.Releases[0].Date + ")"
+	}
+	releases := make([]string, 0, flatpakInfoReleases)
+	for _, release := range app.Releases {
+		if len(releases) == flatpakInfoReleases {
+			break
+		}
+		entry := release.Version
+		if release.Date != "" {
+			entry += " (" + release.Date + ")"
+		}
+		releases = append(releases, entry)
+	}
+	rating := make([]string, 0, len(app.ContentRating))
+	for id, level := range app.ContentRating {
+		rating = append(rating, id+" ("+level+")")
+	}
+	sort.Strings(rating)
+	icon := ""
+	for _, candidate := range app.Icons {
+		if icon == "" || candidate.Type == "remote" {
+			icon = candidate.Value
+		}
+	}
+
+	for _, field := range [][2]string{
+		{"Name", app.Name},
+		{"Summary", app.Summary},
+		{"Description", app.Description},
+		{"Version", version},
+		{"Developer", app.Developer},
+		{"License", app.License},
+		{"Categories", strings.Join(app.Categories, ", ")},
+		{"Keywords", strings.Join(app.Keywords, ", ")},
+		{"Homepage", app.Homepage},
+		{"Bug tracker", app.BugTracker},
+		{"Content rating", strings.Join(rating, ", ")},
+		{"Releases", strings.Join(releases, ", ")},
+		{"Icon", icon},
+	} {
+		if field[1] != "" {
+			fmt.Fprintf(&b, "%s: %s\n", field[0], field[1])
+		}
+	}
+	return b.String()
+}
+
+func parseFlatpakSearch(out []byte) []searchRow {
+	rows := make([]searchRow, 0)
+	lines := splitLines(out)
+	for _, line := range lines {
+		trim := strings.TrimSpace(line)
+		if trim == "" || isFlatpakHeaderLine(trim) {
+			continue
+		}
+		parts := strings.Fields(trim)
+		if len(parts) == 0 {
+			continue
+		}
+		name := parts[0]
+		desc := "-"
+		if len(parts) > 1 {
+			desc = strings.TrimSpace(trim[len(name):])
+			if desc == "" {
+				desc = "-"
+			}
+		}
+		rows = append(rows, searchRow{Name: name, Desc: desc})
+	}
+	return rows
+}
+
+func isFlatpakHeaderLine(line string) bool {
+	fields := strings.Fields(strings.ToLower(strings.TrimSpace(line)))
+	if len(fields) < 2 {
+		return false
+	}
+	return fields[0] == "application" && fields[1] == "description"
+}
+
+func parseFlatpakInstalled(out []byte) []string {
+	names := make([]string, 0)
+	lines := splitLines(out)
+	for i, line := range lines {
+		if i == 0 || strings.TrimSpace(line) == "" {
+			continue
+		}
+		parts := strings.Fields(line)
+		if len(parts) > 0 {
+			names = append(names, parts[0])
+		}
+	}
+	return names
+}
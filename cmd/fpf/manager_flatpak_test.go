@@ -0,0 +1,158 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Timmy6942025/fpf-cli/internal/flatpak"
+)
+
+func TestFormatFlatpakAppInfo(t *testing.T) {
+	app := flatpak.App{
+		ID:            "org.gnome.Chess",
+		Name:          "Chess",
+		Summary:       "Play chess",
+		Version:       "46.0",
+		Origin:        "flathub",
+		Categories:    []string{"Game", "BoardGame"},
+		License:       "GPL-3.0+",
+		Homepage:      "https://wiki.gnome.org/Apps/Chess",
+		ContentRating: map[string]string{"social-chat": "mild", "language-humor": "mild"},
+		Releases: []flatpak.Release{
+			{Version: "46.0", Date: "2024-03-21"},
+			{Version: "45.0"},
+		},
+		Icons: []flatpak.Icon{
+			{Type: "cached", Value: "/icons/64x64/org.gnome.Chess.png"},
+			{Type: "remote", Value: "https://example.org/chess.png"},
+		},
+	}
+
+	want := "org.gnome.Chess (flathub)\n" +
+		"Name: Chess\n" +
+		"Summary: Play chess\n" +
+		"Version: 46.0 (2024-03-21)\n" +
+		"License: GPL-3.0+\n" +
+		"Categories: Game, BoardGame\n" +
+		"Homepage: https://wiki.gnome.org/Apps/Chess\n" +
+		"Content rating: language-humor (mild), social-chat (mild)\n" +
+		"Releases: 46.0 (2024-03-21), 45.0\n" +
+		"Icon: https://example.org/chess.png\n"
+	if got := formatFlatpakAppInfo(app); got != want {
+		t.Fatalf("formatFlatpakAppInfo =\n%s\nwant\n%s", got, want)
+	}
+}
+
+// flatpakTestInstallations points both installations at temp dirs, deploys
+// the given apps ("scope:id:origin") and installs a flatpak mock that logs
+// its arguments.
+func flatpakTestInstallations(t *testing.T, apps ...string) string {
+	t.Helper()
+	root := t.TempDir()
+	dirs := map[string]string{"user": filepath.Join(root, "user"), "system": filepath.Join(root, "system")}
+	t.Setenv("FLATPAK_USER_DIR", dirs["user"])
+	t.Setenv("FLATPAK_SYSTEM_DIR", dirs["system"])
+	for _, spec := range apps {
+		parts := strings.SplitN(spec, ":", 3)
+		active := filepath.Join(dirs[parts[0]], "app", parts[1], "x86_64", "stable", "active")
+		if err := os.MkdirAll(active, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(active, "deploy"), []byte(parts[2]+"\x00"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	bin := filepath.Join(root, "bin")
+	if err := os.MkdirAll(bin, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	logFile := filepath.Join(root, "flatpak.log")
+	writeMockExecutable(t, bin, "flatpak", "#!/bin/sh\necho \"$*\" >>"+logFile+"\n")
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+	return logFile
+}
+
+func readFlatpakLog(t *testing.T, logFile string) []string {
+	t.Helper()
+	raw, err := os.ReadFile(logFile)
+	if err != nil {
+		return nil
+	}
+	return splitLines(raw)
+}
+
+func TestFlatpakRemoveTargetsInstallation(t *testing.T) {
+	logFile := flatpakTestInstallations(t,
+		"user:org.example.Editor:flathub",
+		"system:org.gnome.Chess:fedora",
+		"user:org.example.Both:flathub",
+		"system:org.example.Both:flathub",
+	)
+
+	if err := (flatpakManager{}).Remove([]string{"org.example.Editor", "org.gnome.Chess", "org.example.Both"}); err != nil {
+		t.Fatalf("Remove: %v", err)
+	}
+	want := []string{
+		"uninstall -y --user org.example.Editor org.example.Both",
+		"uninstall -y --system org.gnome.Chess org.example.Both",
+	}
+	if got := readFlatpakLog(t, logFile); !reflect.DeepEqual(got, want) {
+		t.Fatalf("flatpak calls = %q, want %q", got, want)
+	}
+}
+
+func TestFlatpakRowsFromOtherRemotesKeepTheirRemote(t *testing.T) {
+	logFile := flatpakTestInstallations(t, "system:org.gnome.Chess:fedora")
+
+	if err := (flatpakManager{}).Install([]string{"fedora:org.gnome.Chess"}); err != nil {
+		t.Fatalf("Install: %v", err)
+	}
+	if err := (flatpakManager{}).Remove([]string{"fedora:org.gnome.Chess"}); err != nil {
+		t.Fatalf("Remove: %v", err)
+	}
+	want := []string{
+		"install -y --user fedora org.gnome.Chess",
+		"uninstall -y --system org.gnome.Chess",
+	}
+	if got := readFlatpakLog(t, logFile); !reflect.DeepEqual(got, want) {
+		t.Fatalf("flatpak calls = %q, want %q", got, want)
+	}
+	if got := installedMarkerName("flatpak", "fedora:org.gnome.Chess"); got != "org.gnome.Chess" {
+		t.Fatalf("installedMarkerName = %q", got)
+	}
+}
+
+func TestFlatpakUpdateOnlyInstallationsInUse(t *testing.T) {
+	logFile := flatpakTestInstallations(t, "system:org.gnome.Chess:fedora")
+
+	if err := (flatpakManager{}).Update(); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if got := readFlatpakLog(t, logFile); !reflect.DeepEqual(got, []string{"update -y --system"}) {
+		t.Fatalf("flatpak calls = %q", got)
+	}
+}
+
+func TestFlatpakInstalledDetailsListsScopes(t *testing.T) {
+	flatpakTestInstallations(t,
+		"user:org.example.Both:flathub",
+		"system:org.example.Both:flathub",
+		"system:org.gnome.Chess:fedora",
+	)
+
+	rows, err := (flatpakManager{}).InstalledDetails()
+	if err != nil {
+		t.Fatalf("InstalledDetails: %v", err)
+	}
+	want := []searchRow{
+		{Name: "org.example.Both", Desc: "installed (user+system, flathub, stable)"},
+		{Name: "org.gnome.Chess", Desc: "installed (system, fedora, stable)"},
+	}
+	if !reflect.DeepEqual(rows, want) {
+		t.Fatalf("rows = %+v", rows)
+	}
+}
@@ -0,0 +1,213 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+type gemManager struct {
+	managerSpec
+}
+
+func init() {
+	registerManager(gemManager{managerSpec{
+		name:         "gem",
+		label:        "RubyGems",
+		order:        145,
+		binaries:     []string{"gem"},
+		explicitOnly: true,
+	}})
+}
+
+// gemSpec is one `name (versions)` entry from `gem search` or `gem list`.
+type gemSpec struct {
+	Name     string
+	Versions []string
+}
+
+// gemHome resolves the directory gems install into: $GEM_HOME, else
+// `gem env gemdir`.
+func gemHome() string {
+	if home := strings.TrimSpace(os.Getenv("GEM_HOME")); home != "" {
+		return home
+	}
+	out, err := runOutputQuietErr("gem", "env", "gemdir")
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// gemUserInstall reports whether installs need --user-install because the
+// gem home is not writable, as with a distro Ruby under /usr/lib.
+func gemUserInstall() bool {
+	home := gemHome()
+	if home == "" {
+		return false
+	}
+	return !dirWritable(home)
+}
+
+func gemScopeArgs(args ...string) []string {
+	if gemUserInstall() {
+		args = append(args, "--user-install")
+	}
+	return args
+}
+
+// gemSearchURL is the rubygems.org search API. Unlike `gem search` it
+// returns each gem's summary.
+const gemSearchURL = "https://rubygems.org/api/v1/search.json?query="
+
+// Search asks the rubygems.org API through curl and falls back to
+// `gem search --remote`, which only lists names and versions.
+func (gemManager) Search(input searchInput) ([]searchRow, error) {
+	query := strings.TrimSpace(input.Query)
+	if _, err := exec.LookPath("curl"); err == nil && query != "" {
+		out, err := input.runOutput("curl", "-fsSL", "--max-time", "5", gemSearchURL+url.QueryEscape(query))
+		if errors.Is(err, context.DeadlineExceeded) {
+			return nil, err
+		}
+		if err == nil {
+			if rows, parseErr := parseRubygemsSearch(out); parseErr == nil {
+				return rows, nil
+			}
+		}
+	}
+	out, err := input.runOutput("gem", "search", "--remote", input.Query)
+	if err != nil {
+		return nil, err
+	}
+	return parseGemSearch(out), nil
+}
+
+func (m gemManager) ListInstalled() ([]string, error) {
+	rows, err := m.InstalledDetails()
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(rows))
+	for _, row := range rows {
+		names = append(names, row.Name)
+	}
+	return names, nil
+}
+
+// InstalledDetails lists each installed gem with its versions, newest first.
+func (gemManager) InstalledDetails() ([]searchRow, error) {
+	out, err := runOutputQuietErr("gem", "list", "--local")
+	if err != nil {
+		return nil, err
+	}
+	specs := parseGemList(out)
+	rows := make([]searchRow, 0, len(specs))
+	for _, spec := range specs {
+		desc := strings.Join(spec.Versions, ", ")
+		if desc == "" {
+			desc = "installed"
+		}
+		rows = append(rows, searchRow{Name: spec.Name, Desc: desc})
+	}
+	return rows, nil
+}
+
+func (gemManager) Install(pkgs []string) error {
+	return runCommand("gem", append(gemScopeArgs("install"), pkgs...)...)
+}
+
+// Remove uninstalls every version along with the gem's executables so gem
+// does not stop to prompt, from the same gem dir Install writes to.
+func (gemManager) Remove(pkgs []string) error {
+	return runCommand("gem", append(gemScopeArgs("uninstall", "--all", "--executables"), pkgs...)...)
+}
+
+func (gemManager) Update() error {
+	return runCommand("gem", gemScopeArgs("update")...)
+}
+
+// Refresh is a no-op: gem search queries rubygems.org directly.
+func (gemManager) Refresh() error {
+	return nil
+}
+
+func (gemManager) ShowInfo(pkg string) error {
+	return runCommandQuietErr("gem", "search", "--remote", "--exact", "--details", pkg)
+}
+
+// parseGemList reads `gem list` and `gem search` output: "name (1.2.0, 1.1.0)"
+// lines, where versions may carry a "default: " marker or platform suffixes.
+func parseGemList(out []byte) []gemSpec {
+	specs := make([]gemSpec, 0)
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "***") {
+			continue
+		}
+		name, rest, ok := strings.Cut(line, " (")
+		if !ok || name == "" || strings.ContainsAny(name, " \t") {
+			continue
+		}
+		versions := make([]string, 0)
+		for _, field := range strings.Split(strings.TrimSuffix(rest, ")"), ",") {
+			field = strings.TrimPrefix(strings.TrimSpace(field), "default: ")
+			if parts := strings.Fields(field); len(parts) > 0 {
+				versions = append(versions, parts[0])
+			}
+		}
+		specs = append(specs, gemSpec{Name: name, Versions: versions})
+	}
+	return specs
+}
+
+func parseGemSearch(out []byte) []searchRow {
+	specs := parseGemList(out)
+	rows := make([]searchRow, 0, len(specs))
+	for _, spec := range specs {
+		desc := "-"
+		if len(spec.Versions) > 0 {
+			desc = spec.Versions[0]
+		}
+		rows = append(rows, searchRow{Name: spec.Name, Desc: desc})
+	}
+	return rows
+}
+
+// parseRubygemsSearch reads the rubygems.org search API response into
+// "summary (version)" rows.
+func parseRubygemsSearch(out []byte) ([]searchRow, error) {
+	var gems []struct {
+		Name    string `json:"name"`
+		Version string `json:"version"`
+		Info    string `json:"info"`
+	}
+	if err := json.Unmarshal(bytes.TrimSpace(out), &gems); err != nil {
+		return nil, fmt.Errorf("rubygems.org search: %w", err)
+	}
+	rows := make([]searchRow, 0, len(gems))
+	for _, gem := range gems {
+		if gem.Name == "" {
+			continue
+		}
+		desc := strings.Join(strings.Fields(gem.Info), " ")
+		switch {
+		case desc == "":
+			desc = gem.Version
+		case gem.Version != "":
+			desc += " (" + gem.Version + ")"
+		}
+		if desc == "" {
+			desc = "-"
+		}
+		rows = append(rows, searchRow{Name: gem.Name, Desc: desc})
+	}
+	return rows, nil
+}
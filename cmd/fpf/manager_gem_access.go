@@ -0,0 +1,14 @@
+//go:build !windows
+
+package main
+
+import "syscall"
+
+// accessWriteOK is W_OK from <unistd.h>.
+const accessWriteOK = 0x2
+
+// dirWritable asks the kernel whether the current user may create files in
+// dir, without writing anything there.
+func dirWritable(dir string) bool {
+	return syscall.Access(dir, accessWriteOK) == nil
+}
@@ -0,0 +1,10 @@
+package main
+
+import "os"
+
+// dirWritable reports whether dir exists without the read-only attribute;
+// Windows has no access(2), and gem homes there are per-user anyway.
+func dirWritable(dir string) bool {
+	info, err := os.Stat(dir)
+	return err == nil && info.IsDir() && info.Mode().Perm()&0o200 != 0
+}
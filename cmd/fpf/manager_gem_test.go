@@ -0,0 +1,109 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseGemSearch(t *testing.T) {
+	rows := parseGemSearch(readFixture(t, "gem-search.txt"))
+	want := []searchRow{
+		{Name: "rails", Desc: "7.1.3.4"},
+		{Name: "rails-html-sanitizer", Desc: "1.6.0"},
+		{Name: "nokogiri", Desc: "1.16.6"},
+		{Name: "railties", Desc: "7.1.3.4"},
+	}
+	if !reflect.DeepEqual(rows, want) {
+		t.Fatalf("parseGemSearch=%+v want %+v", rows, want)
+	}
+}
+
+func TestParseRubygemsSearch(t *testing.T) {
+	rows, err := parseRubygemsSearch(readFixture(t, "rubygems-search.json"))
+	if err != nil {
+		t.Fatalf("parseRubygemsSearch: %v", err)
+	}
+	want := []searchRow{
+		{Name: "rails", Desc: "Ruby on Rails is a full-stack web framework optimized for programmer happiness and sustainable productivity. It encourages beautiful code by favoring convention over configuration. (7.1.3.4)"},
+		{Name: "rails-html-sanitizer", Desc: "HTML sanitization for Rails applications (1.6.0)"},
+		{Name: "railties-stub", Desc: "0.0.1"},
+	}
+	if !reflect.DeepEqual(rows, want) {
+		t.Fatalf("parseRubygemsSearch=%+v want %+v", rows, want)
+	}
+	if _, err := parseRubygemsSearch([]byte("<html>502</html>")); err == nil {
+		t.Fatal("expected an error for a non-JSON response")
+	}
+}
+
+func TestGemSearchFallsBackToGemSearch(t *testing.T) {
+	bin := t.TempDir()
+	writeMockExecutable(t, bin, "gem", "#!/bin/sh\nprintf '*** REMOTE GEMS ***\\n\\nrails (7.1.3.4)\\n'\n")
+	writeMockExecutable(t, bin, "curl", "#!/bin/sh\ncat \""+filepath.Join("..", "..", "tests", "fixtures", "rubygems-search.json")+"\"\n")
+	t.Setenv("PATH", bin+":/usr/bin:/bin")
+
+	rows, err := executeSearchEntries(searchInput{Manager: "gem", Query: "rails"})
+	if err != nil || len(rows) != 3 || rows[1].Desc != "HTML sanitization for Rails applications (1.6.0)" {
+		t.Fatalf("expected rubygems.org rows, got %+v, %v", rows, err)
+	}
+
+	writeMockExecutable(t, bin, "curl", "#!/bin/sh\nexit 6\n")
+	rows, err = executeSearchEntries(searchInput{Manager: "gem", Query: "rails"})
+	if err != nil || !reflect.DeepEqual(rows, []searchRow{{Name: "rails", Desc: "7.1.3.4"}}) {
+		t.Fatalf("expected gem search fallback rows, got %+v, %v", rows, err)
+	}
+}
+
+func TestGemInstalledDetailsKeepVersions(t *testing.T) {
+	bin := t.TempDir()
+	writeMockExecutable(t, bin, "gem", "#!/bin/sh\ncat \""+filepath.Join("..", "..", "tests", "fixtures", "gem-list.txt")+"\"\n")
+	t.Setenv("PATH", bin+":/usr/bin:/bin")
+
+	rows, err := executeInstalledRows(installedInput{Manager: "gem"})
+	if err != nil || len(rows) != 4 || rows[0] != (searchRow{Name: "bundler", Desc: "2.5.11, 2.4.22"}) {
+		t.Fatalf("expected gem versions in installed rows, got %+v, %v", rows, err)
+	}
+}
+
+func TestParseGemList(t *testing.T) {
+	specs := parseGemList(readFixture(t, "gem-list.txt"))
+	want := []gemSpec{
+		{Name: "bundler", Versions: []string{"2.5.11", "2.4.22"}},
+		{Name: "nokogiri", Versions: []string{"1.16.6", "1.16.5"}},
+		{Name: "rake", Versions: []string{"13.2.1"}},
+		{Name: "rubocop", Versions: []string{"1.64.1"}},
+	}
+	if !reflect.DeepEqual(specs, want) {
+		t.Fatalf("parseGemList=%+v want %+v", specs, want)
+	}
+}
+
+func TestGemScopeArgsUsesUserInstallForReadOnlyHome(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("GEM_HOME", home)
+	if got := gemScopeArgs("install"); !reflect.DeepEqual(got, []string{"install"}) {
+		t.Fatalf("gemScopeArgs writable home=%v", got)
+	}
+
+	if entries, _ := os.ReadDir(home); len(entries) != 0 {
+		t.Fatalf("expected the writability check to leave GEM_HOME untouched, found %d entries", len(entries))
+	}
+
+	t.Setenv("GEM_HOME", home+"/missing")
+	if got := gemScopeArgs("install"); !reflect.DeepEqual(got, []string{"install", "--user-install"}) {
+		t.Fatalf("gemScopeArgs read-only home=%v", got)
+	}
+
+	dir := t.TempDir()
+	log := filepath.Join(dir, "log")
+	writeMockExecutable(t, dir, "gem", "#!/usr/bin/env bash\nprintf '%s\\n' \"$*\" >> \""+log+"\"\n")
+	t.Setenv("PATH", dir+":/usr/bin:/bin")
+	if err := (gemManager{}).Remove([]string{"rake"}); err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+	if raw, _ := os.ReadFile(log); string(raw) != "uninstall --all --executables --user-install rake\n" {
+		t.Fatalf("gem calls=%q", raw)
+	}
+}
@@ -0,0 +1,301 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+type goManager struct {
+	managerSpec
+}
+
+func init() {
+	registerManager(goManager{managerSpec{
+		name:         "go",
+		label:        "Go",
+		order:        160,
+		binaries:     []string{"go"},
+		explicitOnly: true,
+	}})
+}
+
+// goBinary is one executable built by `go install`, as reported by
+// `go version -m`.
+type goBinary struct {
+	File    string
+	Path    string
+	Module  string
+	Version string
+}
+
+func (b goBinary) name() string {
+	return filepath.Base(b.File)
+}
+
+// goBinDir resolves where `go install` puts binaries: $GOBIN, else the first
+// $GOPATH entry's bin directory.
+func goBinDir() (string, error) {
+	out, err := runOutputQuietErr("go", "env", "GOBIN", "GOPATH")
+	if err != nil {
+		return "", err
+	}
+	lines := splitLines(out)
+	if len(lines) > 0 && strings.TrimSpace(lines[0]) != "" {
+		return strings.TrimSpace(lines[0]), nil
+	}
+	if len(lines) > 1 {
+		if gopath := filepath.SplitList(strings.TrimSpace(lines[1])); len(gopath) > 0 && gopath[0] != "" {
+			return filepath.Join(gopath[0], "bin"), nil
+		}
+	}
+	return "", fmt.Errorf("unable to resolve GOBIN or GOPATH")
+}
+
+func loadGoBinaries() ([]goBinary, error) {
+	dir, err := goBinDir()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		return nil, nil
+	}
+	out, err := runOutputQuietErr("go", "version", "-m", dir)
+	if err != nil && len(out) == 0 {
+		return nil, err
+	}
+	return parseGoVersionM(out), nil
+}
+
+func (goManager) Search(input searchInput) ([]searchRow, error) {
+	query := strings.TrimSpace(input.Query)
+	binaries, err := loadGoBinaries()
+	if err != nil && query == "" {
+		return nil, err
+	}
+
+	rows := make([]searchRow, 0)
+	seen := map[string]struct{}{}
+	needle := strings.ToLower(query)
+	for _, bin := range binaries {
+		if needle != "" && !strings.Contains(strings.ToLower(bin.Path), needle) && !strings.Contains(strings.ToLower(bin.name()), needle) {
+			continue
+		}
+		if _, ok := seen[bin.Path]; ok {
+			continue
+		}
+		seen[bin.Path] = struct{}{}
+		rows = append(rows, searchRow{Name: bin.Path, Desc: fmt.Sprintf("%s %s", bin.name(), bin.Version)})
+	}
+
+	// Anything that looks like an import path is resolved against the module
+	// proxy so `fpf -go example.com/tool/cmd/x` can install tools not yet on disk.
+	if strings.Contains(query, "/") && !strings.ContainsAny(query, " \t") {
+		pkg, _, _ := strings.Cut(query, "@")
+		if _, ok := seen[pkg]; !ok {
+			rows = append(rows, lookupGoModuleRow(input, pkg))
+		}
+	}
+	return rows, nil
+}
+
+// goModuleLookupTimeout bounds the one module proxy query a search makes.
+const goModuleLookupTimeout = 5 * time.Second
+
+// lookupGoModuleRow describes pkg with its latest version from a single
+// `go list -m` query. When pkg is a package inside a module rather than the
+// module itself the query fails, and the row is offered without a version;
+// `go install pkg@latest` resolves the module either way.
+func lookupGoModuleRow(input searchInput, pkg string) searchRow {
+	timeout := goModuleLookupTimeout
+	if input.CommandTimeout > 0 && input.CommandTimeout < timeout {
+		timeout = input.CommandTimeout
+	}
+	if out, err := runOutputQuietErrWithTimeout(timeout, "go", "list", "-m", "-json", pkg+"@latest"); err == nil {
+		if row, ok := parseGoListModule(pkg, out); ok {
+			return row
+		}
+	}
+	return searchRow{Name: pkg, Desc: "go install " + pkg + "@latest"}
+}
+
+func (goManager) ListInstalled() ([]string, error) {
+	binaries, err := loadGoBinaries()
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(binaries))
+	for _, bin := range binaries {
+		names = append(names, bin.Path)
+	}
+	return names, nil
+}
+
+func goInstallTarget(pkg string) string {
+	if strings.Contains(pkg, "@") {
+		return pkg
+	}
+	return pkg + "@latest"
+}
+
+func (goManager) Install(pkgs []string) error {
+	for _, pkg := range pkgs {
+		if err := runCommand("go", "install", goInstallTarget(pkg)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Remove deletes the installed binary for each package path or binary name.
+func (goManager) Remove(pkgs []string) error {
+	binaries, err := loadGoBinaries()
+	if err != nil {
+		return err
+	}
+	for _, pkg := range pkgs {
+		pkg, _, _ = strings.Cut(pkg, "@")
+		removed := false
+		for _, bin := range binaries {
+			if bin.Path != pkg && bin.name() != pkg {
+				continue
+			}
+			if err := os.Remove(bin.File); err != nil {
+				return err
+			}
+			fmt.Printf("Removed %s\n", bin.File)
+			removed = true
+		}
+		if !removed {
+			return fmt.Errorf("no installed Go binary for %s", pkg)
+		}
+	}
+	return nil
+}
+
+// Update reinstalls every binary's package at @latest. Binaries built from a
+// local checkout report "(devel)" and are skipped.
+func (goManager) Update() error {
+	binaries, err := loadGoBinaries()
+	if err != nil {
+		return err
+	}
+	for _, bin := range binaries {
+		if bin.Path == "" || bin.Version == "(devel)" {
+			continue
+		}
+		if err := runCommand("go", "install", bin.Path+"@latest"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Refresh is a no-op: versions are resolved through the module proxy on
+// demand.
+func (goManager) Refresh() error {
+	return nil
+}
+
+func (goManager) ShowInfo(pkg string) error {
+	pkg, _, _ = strings.Cut(pkg, "@")
+	if binaries, err := loadGoBinaries(); err == nil {
+		for _, bin := range binaries {
+			if bin.Path == pkg || bin.name() == pkg {
+				return runCommandQuietErr("go", "version", "-m", bin.File)
+			}
+		}
+	}
+	return runCommandQuietErr("go", "list", "-m", "-json", pkg+"@latest")
+}
+
+// InstallFzf only runs when `go install` puts binaries on PATH; otherwise
+// the fzf it builds would not be found and bootstrap moves on.
+func (goManager) InstallFzf() error {
+	dir, err := goBinDir()
+	if err != nil {
+		return err
+	}
+	if !dirOnPath(dir) {
+		return fmt.Errorf("%s is not on PATH", dir)
+	}
+	return runCommand("go", "install", "github.com/junegunn/fzf@latest")
+}
+
+func dirOnPath(dir string) bool {
+	dir = filepath.Clean(dir)
+	for _, entry := range filepath.SplitList(os.Getenv("PATH")) {
+		if entry != "" && filepath.Clean(entry) == dir {
+			return true
+		}
+	}
+	return false
+}
+
+// parseGoVersionM reads `go version -m <dir>` output: a "<file>: go1.x"
+// header per binary followed by tab-indented path/mod/dep/build lines.
+func parseGoVersionM(out []byte) []goBinary {
+	binaries := make([]goBinary, 0)
+	var current *goBinary
+	flush := func() {
+		if current != nil && current.Path != "" {
+			binaries = append(binaries, *current)
+		}
+		current = nil
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		if !strings.HasPrefix(line, "\t") {
+			flush()
+			file, _, ok := strings.Cut(line, ": ")
+			if !ok {
+				continue
+			}
+			current = &goBinary{File: file}
+			continue
+		}
+		if current == nil {
+			continue
+		}
+		fields := strings.Split(strings.TrimPrefix(line, "\t"), "\t")
+		switch fields[0] {
+		case "path":
+			if len(fields) > 1 {
+				current.Path = fields[1]
+			}
+		case "mod":
+			if len(fields) > 2 {
+				current.Module = fields[1]
+				current.Version = fields[2]
+			}
+		}
+	}
+	flush()
+
+	sort.Slice(binaries, func(i, j int) bool {
+		return binaries[i].File < binaries[j].File
+	})
+	return binaries
+}
+
+func parseGoListModule(pkg string, out []byte) (searchRow, bool) {
+	var mod struct {
+		Path    string `json:"Path"`
+		Version string `json:"Version"`
+	}
+	if err := json.Unmarshal(bytes.TrimSpace(out), &mod); err != nil || mod.Version == "" {
+		return searchRow{}, false
+	}
+	return searchRow{Name: pkg, Desc: fmt.Sprintf("%s %s (latest)", mod.Path, mod.Version)}, true
+}
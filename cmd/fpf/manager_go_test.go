@@ -0,0 +1,121 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseGoVersionM(t *testing.T) {
+	binaries := parseGoVersionM(readFixture(t, "go-version-m.txt"))
+	if len(binaries) != 3 {
+		t.Fatalf("expected 3 binaries, got %+v", binaries)
+	}
+	want := goBinary{File: "/home/dev/go/bin/staticcheck", Path: "honnef.co/go/tools/cmd/staticcheck", Module: "honnef.co/go/tools", Version: "v0.5.1"}
+	if binaries[2] != want {
+		t.Fatalf("binary 2=%+v want %+v", binaries[2], want)
+	}
+	if binaries[1].name() != "mytool" || binaries[1].Version != "(devel)" {
+		t.Fatalf("unexpected devel binary %+v", binaries[1])
+	}
+}
+
+func TestGoManagerActions(t *testing.T) {
+	gobin := t.TempDir()
+	for _, name := range []string{"gopls", "mytool"} {
+		if err := os.WriteFile(filepath.Join(gobin, name), []byte("bin"), 0o755); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+
+	bin := t.TempDir()
+	logFile := filepath.Join(t.TempDir(), "go.log")
+	writeMockExecutable(t, bin, "go", `#!/bin/sh
+printf '%s\n' "$*" >>"`+logFile+`"
+case "$1" in
+    env) printf '%s\n\n' "`+gobin+`" ;;
+    version)
+        printf '%s: go1.23.3\n\tpath\tgolang.org/x/tools/gopls\n\tmod\tgolang.org/x/tools/gopls\tv0.16.2\th1:x\n' "`+filepath.Join(gobin, "gopls")+`"
+        printf '%s: go1.23.3\n\tpath\texample.com/mytool\n\tmod\texample.com/mytool\t(devel)\t\n' "`+filepath.Join(gobin, "mytool")+`"
+        ;;
+esac
+`)
+	t.Setenv("PATH", bin)
+
+	installed, err := executeInstalledEntries(installedInput{Manager: "go"})
+	if err != nil || strings.Join(installed, ",") != "golang.org/x/tools/gopls,example.com/mytool" {
+		t.Fatalf("installed=(%v,%v)", installed, err)
+	}
+
+	rows, err := executeSearchEntries(searchInput{Manager: "go", Query: "gopls"})
+	if err != nil || len(rows) != 1 || rows[0].Name != "golang.org/x/tools/gopls" || rows[0].Desc != "gopls v0.16.2" {
+		t.Fatalf("search rows=(%+v,%v)", rows, err)
+	}
+
+	if err := (goManager{}).Update(); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if err := (goManager{}).Install([]string{"github.com/junegunn/fzf", "honnef.co/go/tools/cmd/staticcheck@v0.5.1"}); err != nil {
+		t.Fatalf("Install: %v", err)
+	}
+	raw, _ := os.ReadFile(logFile)
+	log := string(raw)
+	for _, want := range []string{
+		"install golang.org/x/tools/gopls@latest\n",
+		"install github.com/junegunn/fzf@latest\n",
+		"install honnef.co/go/tools/cmd/staticcheck@v0.5.1\n",
+	} {
+		if !strings.Contains(log, want) {
+			t.Fatalf("expected %q in go log:\n%s", want, log)
+		}
+	}
+	if strings.Contains(log, "example.com/mytool@latest") {
+		t.Fatalf("expected (devel) binaries to be skipped on update, log:\n%s", log)
+	}
+
+	if err := (goManager{}).Remove([]string{"golang.org/x/tools/gopls"}); err != nil {
+		t.Fatalf("Remove: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(gobin, "gopls")); !os.IsNotExist(err) {
+		t.Fatalf("expected gopls binary to be removed, stat err=%v", err)
+	}
+	if err := (goManager{}).Remove([]string{"not-installed"}); err == nil {
+		t.Fatal("expected removing an unknown binary to fail")
+	}
+}
+
+func TestGoModuleLookupMakesOneQuery(t *testing.T) {
+	gobin := t.TempDir()
+	bin := t.TempDir()
+	logFile := filepath.Join(t.TempDir(), "go.log")
+	writeMockExecutable(t, bin, "go", `#!/bin/sh
+printf '%s\n' "$*" >>"`+logFile+`"
+case "$1" in
+    env) printf '%s\n\n' "`+gobin+`" ;;
+    list) exit 1 ;;
+esac
+`)
+	t.Setenv("PATH", bin)
+
+	rows, err := executeSearchEntries(searchInput{Manager: "go", Query: "example.com/tool/cmd/tool"})
+	if err != nil || len(rows) != 1 || rows[0].Name != "example.com/tool/cmd/tool" || rows[0].Desc != "go install example.com/tool/cmd/tool@latest" {
+		t.Fatalf("search rows=(%+v,%v)", rows, err)
+	}
+	raw, _ := os.ReadFile(logFile)
+	if got := strings.Count(string(raw), "list -m"); got != 1 {
+		t.Fatalf("expected one module lookup, got %d:\n%s", got, raw)
+	}
+
+	if err := (goManager{}).InstallFzf(); err == nil || !strings.Contains(err.Error(), "not on PATH") {
+		t.Fatalf("expected InstallFzf to refuse a GOBIN outside PATH, got %v", err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+gobin)
+	if err := (goManager{}).InstallFzf(); err != nil {
+		t.Fatalf("InstallFzf: %v", err)
+	}
+	raw, _ = os.ReadFile(logFile)
+	if !strings.Contains(string(raw), "install github.com/junegunn/fzf@latest\n") {
+		t.Fatalf("expected fzf install in go log:\n%s", raw)
+	}
+}
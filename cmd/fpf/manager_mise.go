@@ -0,0 +1,456 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+type miseManager struct {
+	managerSpec
+}
+
+func init() {
+	registerManager(miseManager{managerSpec{
+		name:         "mise",
+		label:        "mise",
+		order:        170,
+		binaries:     []string{"mise", "asdf"},
+		explicitOnly: true,
+	}})
+}
+
+// miseToolDescriptions labels the common runtimes; neither mise nor asdf
+// ships tool descriptions.
+var miseToolDescriptions = map[string]string{
+	"bun":    "JavaScript runtime",
+	"deno":   "JavaScript runtime",
+	"dotnet": ".NET SDK",
+	"elixir": "Elixir language",
+	"erlang": "Erlang/OTP runtime",
+	"go":     "Go toolchain",
+	"golang": "Go toolchain",
+	"java":   "Java runtime",
+	"node":   "JavaScript runtime",
+	"nodejs": "JavaScript runtime",
+	"php":    "PHP runtime",
+	"python": "Python runtime",
+	"ruby":   "Ruby runtime",
+	"rust":   "Rust toolchain",
+	"zig":    "Zig toolchain",
+}
+
+func miseToolDescription(tool string) string {
+	if desc, ok := miseToolDescriptions[tool]; ok {
+		return desc
+	}
+	return "-"
+}
+
+// miseDriver picks the version manager to drive: mise when installed,
+// otherwise asdf. FPF_MISE_DRIVER=mise|asdf forces one.
+func miseDriver() string {
+	candidates := []string{"mise", "asdf"}
+	if forced := strings.ToLower(strings.TrimSpace(os.Getenv("FPF_MISE_DRIVER"))); forced != "" {
+		candidates = []string{forced}
+	}
+	for _, driver := range candidates {
+		if driver != "mise" && driver != "asdf" {
+			continue
+		}
+		if _, err := exec.LookPath(driver); err == nil {
+			return driver
+		}
+	}
+	return ""
+}
+
+func (miseManager) Ready() bool {
+	return miseDriver() != ""
+}
+
+// splitToolVersion splits "node@22.3.0" into its tool and version.
+func splitToolVersion(spec string) (string, string) {
+	tool, version, _ := strings.Cut(strings.TrimSpace(spec), "@")
+	return tool, version
+}
+
+// Search matches the query ("node", "node@22" or "node 22") against the
+// registry. Once it names a registry tool, that tool's latest, the typed
+// spec and its remote versions (newest first, narrowed to the typed version
+// prefix) lead, followed by the other registry tools whose name contains it.
+// Without a query the installed versions are listed.
+func (m miseManager) Search(input searchInput) ([]searchRow, error) {
+	fields := strings.Fields(input.Query)
+	if len(fields) == 0 {
+		installed, err := m.ListInstalled()
+		if err != nil {
+			return nil, err
+		}
+		rows := make([]searchRow, 0, len(installed))
+		for _, spec := range installed {
+			tool, _ := splitToolVersion(spec)
+			rows = append(rows, searchRow{Name: spec, Desc: miseToolDescription(tool)})
+		}
+		return rows, nil
+	}
+
+	tool, version := splitToolVersion(fields[0])
+	if version == "" && len(fields) > 1 {
+		version = fields[1]
+	}
+	if tool == "" {
+		return nil, nil
+	}
+
+	registry, err := loadMiseRegistryRows(input)
+	if err != nil {
+		return nil, err
+	}
+	matches := filterMiseRegistry(registry, tool)
+	latest := -1
+	for i, row := range matches {
+		if row.Name == tool+"@latest" {
+			latest = i
+			break
+		}
+	}
+	if latest < 0 {
+		return matches, nil
+	}
+
+	desc := matches[latest].Desc
+	rows := []searchRow{{Name: tool + "@latest", Desc: desc, Rank: rankFromScore(0)}}
+	seen := map[string]struct{}{rows[0].Name: {}}
+	add := func(spec string) {
+		if _, ok := seen[spec]; ok {
+			return
+		}
+		seen[spec] = struct{}{}
+		rows = append(rows, searchRow{Name: spec, Desc: desc, Rank: rankFromScore(1)})
+	}
+	if version != "" {
+		add(tool + "@" + version)
+	}
+	versions, err := loadMiseVersions(input, tool)
+	if errors.Is(err, context.DeadlineExceeded) {
+		return nil, err
+	}
+	for i := len(versions) - 1; i >= 0; i-- {
+		if strings.HasPrefix(versions[i], version) {
+			add(tool + "@" + versions[i])
+		}
+	}
+	for i, row := range matches {
+		if i != latest {
+			rows = append(rows, row)
+		}
+	}
+	return rows, nil
+}
+
+// miseRegistryFingerprint keys the registry cache on the driver binary,
+// whose built-in registry changes on upgrade, and for asdf on the plugin
+// index it syncs into its data dir.
+func miseRegistryFingerprint(driver string) string {
+	parts := []string{"1", driver}
+	path, err := exec.LookPath(driver)
+	if err != nil {
+		return strings.Join(append(parts, "missing"), "|")
+	}
+	stamps := []string{path}
+	if driver == "asdf" {
+		stamps = append(stamps, filepath.Join(asdfDataDir(), "repository", "plugins"))
+	}
+	for _, stamp := range stamps {
+		info, err := os.Stat(stamp)
+		if err != nil {
+			parts = append(parts, stamp+"=missing")
+			continue
+		}
+		parts = append(parts, fmt.Sprintf("%s=%d:%d", stamp, info.ModTime().UnixNano(), info.Size()))
+	}
+	return strings.Join(parts, "|")
+}
+
+func asdfDataDir() string {
+	if dataDir := strings.TrimSpace(os.Getenv("ASDF_DATA_DIR")); dataDir != "" {
+		return dataDir
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		return filepath.Join(home, ".asdf")
+	}
+	return ""
+}
+
+// loadMiseRegistryRows reads `mise registry` (or `asdf plugin list all`)
+// once per driver version and serves later queries from the catalog cache.
+func loadMiseRegistryRows(input searchInput) ([]searchRow, error) {
+	driver := miseDriver()
+	if driver == "" {
+		return nil, fmt.Errorf("neither mise nor asdf is installed")
+	}
+	key := cacheChecksum(miseRegistryFingerprint(driver))
+	cachePath := filepath.Join(cacheRootPath(), "search-catalog", "mise", key+".tsv")
+
+	if raw, err := os.ReadFile(cachePath); err == nil {
+		if rows := parseCachedRows(raw); len(rows) > 0 {
+			return rows, nil
+		}
+	}
+
+	args := []string{"registry"}
+	if driver == "asdf" {
+		args = []string{"plugin", "list", "all"}
+	}
+	out, err := input.runOutput(driver, args...)
+	if err != nil {
+		return nil, err
+	}
+	rows := parseMiseRegistry(out)
+	if len(rows) == 0 {
+		return nil, nil
+	}
+
+	_ = os.MkdirAll(filepath.Dir(cachePath), 0o755)
+	_ = os.WriteFile(cachePath, []byte(renderAPT(rows)), 0o644)
+	return rows, nil
+}
+
+// miseVersionsFingerprint keys a tool's remote version cache on the
+// registry fingerprint, the asdf plugin that lists the versions, and the
+// day, so new releases show up by the next day without a refresh.
+func miseVersionsFingerprint(driver, tool string) string {
+	parts := []string{miseRegistryFingerprint(driver), tool, time.Now().UTC().Format("2006-01-02")}
+	if driver == "asdf" {
+		plugin := filepath.Join(asdfDataDir(), "plugins", tool)
+		if info, err := os.Stat(plugin); err == nil {
+			parts = append(parts, fmt.Sprintf("%d", info.ModTime().UnixNano()))
+		}
+	}
+	return strings.Join(parts, "|")
+}
+
+// loadMiseVersions lists a registry tool's remote versions, oldest first,
+// with `mise ls-remote <tool>` (or `asdf list all <tool>`), cached per tool
+// like the registry.
+func loadMiseVersions(input searchInput, tool string) ([]string, error) {
+	driver := miseDriver()
+	if driver == "" {
+		return nil, fmt.Errorf("neither mise nor asdf is installed")
+	}
+	key := cacheChecksum(miseVersionsFingerprint(driver, tool))
+	cachePath := filepath.Join(cacheRootPath(), "search-catalog", "mise", "versions", key+".txt")
+
+	if raw, err := os.ReadFile(cachePath); err == nil {
+		if versions := parseMiseVersions(raw); len(versions) > 0 {
+			return versions, nil
+		}
+	}
+
+	args := []string{"ls-remote", tool}
+	if driver == "asdf" {
+		args = []string{"list", "all", tool}
+	}
+	out, err := input.runOutput(driver, args...)
+	if err != nil {
+		return nil, err
+	}
+	versions := parseMiseVersions(out)
+	if len(versions) == 0 {
+		return nil, nil
+	}
+
+	_ = os.MkdirAll(filepath.Dir(cachePath), 0o755)
+	_ = os.WriteFile(cachePath, []byte(strings.Join(versions, "\n")+"\n"), 0o644)
+	return versions, nil
+}
+
+func (miseManager) ListInstalled() ([]string, error) {
+	switch miseDriver() {
+	case "mise":
+		out, err := runOutputQuietErr("mise", "ls", "--json")
+		if err != nil {
+			return nil, err
+		}
+		return parseMiseLsJSON(out)
+	case "asdf":
+		out, err := runOutputQuietErr("asdf", "list")
+		if err != nil {
+			return nil, err
+		}
+		return parseAsdfList(out), nil
+	default:
+		return nil, fmt.Errorf("neither mise nor asdf is installed")
+	}
+}
+
+// Install takes tool@version specs; a bare tool name installs its latest
+// version. asdf needs the tool's plugin added first.
+func (miseManager) Install(pkgs []string) error {
+	if miseDriver() == "asdf" {
+		for _, pkg := range pkgs {
+			tool, version := splitToolVersion(pkg)
+			if version == "" {
+				version = "latest"
+			}
+			_ = runCommandQuietErr("asdf", "plugin", "add", tool)
+			if err := runCommand("asdf", "install", tool, version); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return runCommand("mise", append([]string{"install"}, pkgs...)...)
+}
+
+func (miseManager) Remove(pkgs []string) error {
+	if miseDriver() == "asdf" {
+		for _, pkg := range pkgs {
+			tool, version := splitToolVersion(pkg)
+			if err := runCommand("asdf", "uninstall", tool, version); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return runCommand("mise", append([]string{"uninstall"}, pkgs...)...)
+}
+
+// Update upgrades mise tools to the newest versions their config allows.
+// asdf has no upgrade command, so its plugins are updated to expose new
+// versions instead.
+func (miseManager) Update() error {
+	if miseDriver() == "asdf" {
+		return runCommand("asdf", "plugin", "update", "--all")
+	}
+	return runCommand("mise", "upgrade")
+}
+
+// Refresh drops mise's cached remote version lists, or updates asdf plugins
+// which carry their own version lists, along with fpf's copies.
+func (miseManager) Refresh() error {
+	_ = os.RemoveAll(filepath.Join(cacheRootPath(), "search-catalog", "mise", "versions"))
+	if miseDriver() == "asdf" {
+		return runCommand("asdf", "plugin", "update", "--all")
+	}
+	return runCommand("mise", "cache", "clear")
+}
+
+func (miseManager) ShowInfo(pkg string) error {
+	tool, _ := splitToolVersion(pkg)
+	if miseDriver() == "asdf" {
+		return runCommandQuietErr("asdf", "list", tool)
+	}
+	if err := runCommandQuietErr("mise", "tool", tool); err == nil {
+		return nil
+	}
+	return runCommandQuietErr("mise", "ls", tool)
+}
+
+// parseMiseRegistry reads `mise registry` ("name  backend...") and
+// `asdf plugin list all` ("name  *url") output into tool@latest rows.
+func parseMiseRegistry(out []byte) []searchRow {
+	rows := make([]searchRow, 0)
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		parts := strings.Fields(scanner.Text())
+		if len(parts) == 0 {
+			continue
+		}
+		tool := parts[0]
+		desc := miseToolDescription(tool)
+		if desc == "-" && len(parts) > 1 {
+			desc = strings.TrimPrefix(parts[1], "*")
+		}
+		rows = append(rows, searchRow{Name: tool + "@latest", Desc: desc})
+	}
+	return rows
+}
+
+// parseMiseVersions reads `mise ls-remote <tool>` and `asdf list all <tool>`,
+// one version per line.
+func parseMiseVersions(out []byte) []string {
+	versions := make([]string, 0)
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		if fields := strings.Fields(scanner.Text()); len(fields) > 0 {
+			versions = append(versions, fields[0])
+		}
+	}
+	return versions
+}
+
+// filterMiseRegistry keeps registry rows whose tool name contains query.
+func filterMiseRegistry(rows []searchRow, query string) []searchRow {
+	needle := strings.ToLower(strings.TrimSpace(query))
+	filtered := make([]searchRow, 0)
+	for _, row := range rows {
+		tool, _ := splitToolVersion(row.Name)
+		if strings.Contains(strings.ToLower(tool), needle) {
+			filtered = append(filtered, row)
+		}
+	}
+	return filtered
+}
+
+// parseMiseLsJSON reads `mise ls --json`, a map of tool to version entries.
+// Versions requested by config but not installed are skipped.
+func parseMiseLsJSON(out []byte) ([]string, error) {
+	var doc map[string][]struct {
+		Version   string `json:"version"`
+		Installed *bool  `json:"installed"`
+	}
+	if err := json.Unmarshal(bytes.TrimSpace(out), &doc); err != nil {
+		return nil, err
+	}
+	tools := make([]string, 0, len(doc))
+	for tool := range doc {
+		tools = append(tools, tool)
+	}
+	sort.Strings(tools)
+
+	specs := make([]string, 0)
+	for _, tool := range tools {
+		for _, entry := range doc[tool] {
+			if entry.Version == "" || (entry.Installed != nil && !*entry.Installed) {
+				continue
+			}
+			specs = append(specs, tool+"@"+entry.Version)
+		}
+	}
+	return specs, nil
+}
+
+// parseAsdfList reads `asdf list`: a tool name per line followed by
+// indented versions, the current one marked with "*".
+func parseAsdfList(out []byte) []string {
+	specs := make([]string, 0)
+	tool := ""
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		if !strings.HasPrefix(line, " ") && !strings.HasPrefix(line, "\t") {
+			tool = strings.TrimSpace(line)
+			continue
+		}
+		version := strings.TrimPrefix(strings.TrimSpace(line), "*")
+		if tool == "" || version == "" || strings.ContainsAny(version, " \t") {
+			continue
+		}
+		specs = append(specs, tool+"@"+version)
+	}
+	return specs
+}
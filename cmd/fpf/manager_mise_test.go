@@ -0,0 +1,99 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseMiseRegistry(t *testing.T) {
+	rows := filterMiseRegistry(parseMiseRegistry(readFixture(t, "mise-registry.txt")), "node")
+	want := []searchRow{
+		{Name: "node@latest", Desc: "JavaScript runtime"},
+		{Name: "nodenv@latest", Desc: "asdf:nodenv/asdf-nodenv"},
+	}
+	if !reflect.DeepEqual(rows, want) {
+		t.Fatalf("parseMiseRegistry=%+v want %+v", rows, want)
+	}
+}
+
+func TestMiseSearchCachesRegistryAndVersions(t *testing.T) {
+	bin := t.TempDir()
+	logFile := filepath.Join(t.TempDir(), "mise.log")
+	fixtures := filepath.Join("..", "..", "tests", "fixtures")
+	script := "#!/bin/sh\nprintf '%s\\n' \"$*\" >>\"" + logFile + "\"\ncase \"$1\" in\n" +
+		"registry) cat \"" + filepath.Join(fixtures, "mise-registry.txt") + "\" ;;\n" +
+		"ls-remote) [ \"$2\" = node ] && cat \"" + filepath.Join(fixtures, "mise-ls-remote.txt") + "\" ;;\n" +
+		"esac\n"
+	writeMockExecutable(t, bin, "mise", script)
+	t.Setenv("PATH", bin+":/usr/bin:/bin")
+	t.Setenv("FPF_CACHE_DIR", t.TempDir())
+	t.Setenv("FPF_MISE_DRIVER", "")
+
+	want := []searchRow{
+		{Name: "node@latest", Desc: "JavaScript runtime", Rank: rankFromScore(0)},
+		{Name: "node@22", Desc: "JavaScript runtime", Rank: rankFromScore(1)},
+		{Name: "node@22.3.0", Desc: "JavaScript runtime", Rank: rankFromScore(1)},
+		{Name: "node@22.1.0", Desc: "JavaScript runtime", Rank: rankFromScore(1)},
+		{Name: "node@22.0.0", Desc: "JavaScript runtime", Rank: rankFromScore(1)},
+		{Name: "nodenv@latest", Desc: "asdf:nodenv/asdf-nodenv"},
+	}
+	for _, query := range []string{"node@22", "node 22"} {
+		rows, err := executeSearchEntries(searchInput{Manager: "mise", Query: query})
+		if err != nil || !reflect.DeepEqual(rows, want) {
+			t.Fatalf("search %q=%+v, %v want %+v", query, rows, err, want)
+		}
+	}
+	rows, err := executeSearchEntries(searchInput{Manager: "mise", Query: "node"})
+	if err != nil || len(rows) != 6 || rows[1].Name != "node@22.3.0" || rows[4].Name != "node@20.14.0" {
+		t.Fatalf("expected every node version newest first, got %+v, %v", rows, err)
+	}
+	rows, err = executeSearchEntries(searchInput{Manager: "mise", Query: "nod"})
+	if err != nil || len(rows) != 2 || rows[0].Name != "node@latest" || rows[0].Rank != 0 {
+		t.Fatalf("expected a partial tool name to list registry rows only, got %+v, %v", rows, err)
+	}
+	rows, err = executeSearchEntries(searchInput{Manager: "mise", Query: "zzz"})
+	if err != nil || len(rows) != 0 {
+		t.Fatalf("expected no invented row for an unknown tool, got %+v, %v", rows, err)
+	}
+
+	raw, _ := os.ReadFile(logFile)
+	if got := string(raw); got != "registry\nls-remote node\n" {
+		t.Fatalf("expected one registry and one ls-remote call, got %q", got)
+	}
+}
+
+func TestMiseFingerprintFollowsDriver(t *testing.T) {
+	t.Setenv("PATH", createMockPath(t, "mise", "asdf"))
+	t.Setenv("FPF_MISE_DRIVER", "asdf")
+	if got := managerCommandForFingerprint("mise"); got != "asdf" {
+		t.Fatalf("expected the asdf driver in the fingerprint, got %q", got)
+	}
+	if miseRegistryFingerprint("asdf") == miseRegistryFingerprint("mise") {
+		t.Fatal("expected distinct registry cache keys per driver")
+	}
+}
+
+func TestParseMiseInstalled(t *testing.T) {
+	specs, err := parseMiseLsJSON(readFixture(t, "mise-ls.json"))
+	if err != nil {
+		t.Fatalf("parseMiseLsJSON: %v", err)
+	}
+	if got := strings.Join(specs, ","); got != "go@1.22.4,node@20.14.0,node@22.3.0" {
+		t.Fatalf("parseMiseLsJSON=%q", got)
+	}
+	if got := strings.Join(parseAsdfList(readFixture(t, "asdf-list.txt")), ","); got != "nodejs@20.14.0,nodejs@22.3.0,python@3.12.4" {
+		t.Fatalf("parseAsdfList=%q", got)
+	}
+}
+
+func TestSplitToolVersion(t *testing.T) {
+	if tool, version := splitToolVersion("node@22.3.0"); tool != "node" || version != "22.3.0" {
+		t.Fatalf("splitToolVersion=%q,%q", tool, version)
+	}
+	if tool, version := splitToolVersion("python"); tool != "python" || version != "" {
+		t.Fatalf("splitToolVersion bare=%q,%q", tool, version)
+	}
+}
@@ -0,0 +1,653 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type nixManager struct {
+	managerSpec
+}
+
+func init() {
+	registerManager(nixManager{managerSpec{
+		name:     "nix",
+		label:    "Nix",
+		order:    115,
+		binaries: []string{"nix"},
+	}})
+}
+
+const nixFlakeRef = "nixpkgs"
+
+func nixArgs(args ...string) []string {
+	return append([]string{"--extra-experimental-features", "nix-command flakes"}, args...)
+}
+
+func nixInstallable(pkg string) string {
+	if strings.Contains(pkg, "#") {
+		return pkg
+	}
+	return nixFlakeRef + "#" + pkg
+}
+
+// nixDriver picks how packages are managed: "profile" drives `nix profile`
+// and flakes, "env" drives nix-env for hosts without nix-command or flakes.
+// FPF_NIX_DRIVER=profile|env forces one. Otherwise the user profile decides,
+// since `nix profile` refuses a profile that nix-env manages.
+func nixDriver() string {
+	switch forced := strings.ToLower(strings.TrimSpace(os.Getenv("FPF_NIX_DRIVER"))); forced {
+	case "profile", "env":
+		return forced
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		profile := filepath.Join(home, ".nix-profile")
+		if _, err := os.Stat(filepath.Join(profile, "manifest.json")); err == nil {
+			return "profile"
+		}
+		if _, err := os.Stat(filepath.Join(profile, "manifest.nix")); err == nil {
+			return "env"
+		}
+	}
+	if _, err := exec.LookPath("nix"); err != nil {
+		return "env"
+	}
+	return "profile"
+}
+
+// nixEnvChannel is the channel nix-env attribute paths start with: "nixos"
+// on NixOS, "nixpkgs" elsewhere. FPF_NIX_CHANNEL overrides it.
+func nixEnvChannel() string {
+	if channel := strings.TrimSpace(os.Getenv("FPF_NIX_CHANNEL")); channel != "" {
+		return channel
+	}
+	if _, err := os.Stat("/etc/NIXOS"); err == nil {
+		return "nixos"
+	}
+	return "nixpkgs"
+}
+
+func nixEnvAttr(pkg string) string {
+	return nixEnvChannel() + "." + pkg
+}
+
+func (nixManager) Ready() bool {
+	for _, binary := range []string{"nix", "nix-env"} {
+		if _, err := exec.LookPath(binary); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+func (nixManager) Search(input searchInput) ([]searchRow, error) {
+	query := strings.TrimSpace(input.Query)
+	if nixDriver() == "env" {
+		return nixEnvSearch(input)
+	}
+	args := []string{"search", "--json", nixFlakeRef}
+	if query != "" {
+		args = append(args, strings.Fields(query)...)
+	} else {
+		args = append(args, "^")
+	}
+	out, err := input.runOutput("nix", nixArgs(args...)...)
+	if err != nil {
+		return nil, err
+	}
+	return parseNixSearch(out)
+}
+
+// nixEnvSearch matches the first query word against package names with
+// `nix-env -qaP`, then keeps rows whose name or description contains every
+// other word.
+func nixEnvSearch(input searchInput) ([]searchRow, error) {
+	fields := strings.Fields(strings.ToLower(input.Query))
+	args := []string{"-qaP", "--json", "--meta"}
+	if len(fields) > 0 {
+		args = append(args, ".*"+regexp.QuoteMeta(fields[0])+".*")
+	}
+	out, err := input.runOutput("nix-env", args...)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := parseNixEnvQuery(out)
+	if err != nil || len(fields) < 2 {
+		return rows, err
+	}
+	filtered := make([]searchRow, 0, len(rows))
+	for _, row := range rows {
+		text := strings.ToLower(row.Name + " " + row.Desc)
+		matched := true
+		for _, field := range fields[1:] {
+			if !strings.Contains(text, field) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			filtered = append(filtered, row)
+		}
+	}
+	return filtered, nil
+}
+
+func (nixManager) ListInstalled() ([]string, error) {
+	if nixDriver() == "env" {
+		installed, err := nixEnvInstalled()
+		if err != nil {
+			return nil, err
+		}
+		names := make([]string, 0, len(installed))
+		for _, pkg := range installed {
+			names = append(names, pkg.Attr)
+		}
+		return names, nil
+	}
+	if out, err := runOutputQuietErr("nix", nixArgs("profile", "list", "--json")...); err == nil {
+		if names, ok := parseNixProfileJSON(out); ok {
+			return names, nil
+		}
+	}
+	out, err := runOutputQuietErr("nix", nixArgs("profile", "list")...)
+	if err != nil {
+		return nil, err
+	}
+	return parseNixProfileList(out), nil
+}
+
+func (nixManager) Install(pkgs []string) error {
+	if nixDriver() == "env" {
+		attrs := make([]string, 0, len(pkgs))
+		for _, pkg := range pkgs {
+			attrs = append(attrs, nixEnvAttr(pkg))
+		}
+		return runCommand("nix-env", append([]string{"-iA"}, attrs...)...)
+	}
+	installables := make([]string, 0, len(pkgs))
+	for _, pkg := range pkgs {
+		installables = append(installables, nixInstallable(pkg))
+	}
+	return runCommand("nix", nixArgs(append([]string{"profile", "install"}, installables...)...)...)
+}
+
+// Remove maps rows back to what each driver removes by: nix-env takes the
+// installed derivation name, and `nix profile remove` only takes element
+// names from Nix 2.20 on, so older profiles are given the element index.
+func (nixManager) Remove(pkgs []string) error {
+	if nixDriver() == "env" {
+		names := make(map[string]string)
+		if installed, err := nixEnvInstalled(); err == nil {
+			for _, pkg := range installed {
+				names[pkg.Attr] = pkg.Name
+			}
+		}
+		return runCommand("nix-env", append([]string{"-e"}, nixSelectors(pkgs, names)...)...)
+	}
+	selectors := make(map[string]string)
+	if out, err := runOutputQuietErr("nix", nixArgs("profile", "list", "--json")...); err == nil {
+		if elements, ok := parseNixProfileElements(out); ok {
+			for _, element := range elements {
+				selectors[element.Name] = element.Selector
+			}
+		}
+	}
+	return runCommand("nix", nixArgs(append([]string{"profile", "remove"}, nixSelectors(pkgs, selectors)...)...)...)
+}
+
+func nixSelectors(pkgs []string, selectors map[string]string) []string {
+	out := make([]string, 0, len(pkgs))
+	for _, pkg := range pkgs {
+		if selector, ok := selectors[pkg]; ok && selector != "" {
+			pkg = selector
+		}
+		out = append(out, pkg)
+	}
+	return out
+}
+
+func (nixManager) Update() error {
+	if nixDriver() == "env" {
+		return runCommand("nix-env", "-u")
+	}
+	return runCommand("nix", nixArgs("profile", "upgrade", "--all")...)
+}
+
+func (nixManager) Refresh() error {
+	if nixDriver() == "env" {
+		return runCommand("nix-channel", "--update")
+	}
+	return runCommand("nix", nixArgs("flake", "metadata", "--refresh", nixFlakeRef)...)
+}
+
+func (nixManager) ShowInfo(pkg string) error {
+	if nixDriver() == "env" {
+		out, err := runOutputQuietErr("nix-env", "-qaA", nixEnvAttr(pkg), "--json", "--meta")
+		if err != nil {
+			return err
+		}
+		info, err := formatNixEnvInfo(out)
+		if err != nil {
+			return err
+		}
+		fmt.Print(info)
+		return nil
+	}
+	out, err := runOutputQuietErr("nix", nixArgs("eval", "--json", nixInstallable(pkg)+".meta")...)
+	if err != nil {
+		return err
+	}
+	var meta nixMeta
+	if err := json.Unmarshal(bytes.TrimSpace(out), &meta); err != nil {
+		return err
+	}
+	fmt.Print(formatNixInfo(pkg, "", meta))
+	return nil
+}
+
+func (nixManager) InstallFzf() error {
+	if nixDriver() == "env" {
+		return runCommand("nix-env", "-iA", nixEnvAttr("fzf"))
+	}
+	return runCommand("nix", nixArgs("profile", "install", nixInstallable("fzf"))...)
+}
+
+// nixAttrName strips the flake output prefix (legacyPackages.<system>. or
+// packages.<system>.) from an attribute path, leaving the name nixpkgs#<name>
+// resolves to.
+func nixAttrName(attrPath string) string {
+	attrPath = strings.TrimSpace(attrPath)
+	if i := strings.Index(attrPath, "#"); i >= 0 {
+		attrPath = attrPath[i+1:]
+	}
+	for _, prefix := range []string{"legacyPackages.", "packages."} {
+		if !strings.HasPrefix(attrPath, prefix) {
+			continue
+		}
+		rest := strings.TrimPrefix(attrPath, prefix)
+		if i := strings.Index(rest, "."); i >= 0 {
+			return rest[i+1:]
+		}
+		return ""
+	}
+	return attrPath
+}
+
+func parseNixSearch(out []byte) ([]searchRow, error) {
+	out = bytes.TrimSpace(out)
+	if len(out) == 0 {
+		return nil, nil
+	}
+	entries := map[string]struct {
+		Pname       string `json:"pname"`
+		Version     string `json:"version"`
+		Description string `json:"description"`
+	}{}
+	if err := json.Unmarshal(out, &entries); err != nil {
+		return nil, fmt.Errorf("nix search: %w", err)
+	}
+
+	rows := make([]searchRow, 0, len(entries))
+	for attrPath, entry := range entries {
+		name := nixAttrName(attrPath)
+		if name == "" {
+			continue
+		}
+		rows = append(rows, searchRow{Name: name, Desc: nixDescText(entry.Description)})
+	}
+	sortNixRows(rows)
+	return rows, nil
+}
+
+// nixEnvPackage is one entry of `nix-env -q --json`, with meta filled in
+// when --meta is passed and outputs when --out-path is.
+type nixEnvPackage struct {
+	Name    string            `json:"name"`
+	Pname   string            `json:"pname"`
+	Version string            `json:"version"`
+	Outputs map[string]string `json:"outputs"`
+	Meta    nixMeta           `json:"meta"`
+}
+
+// parseNixEnvQuery reads `nix-env -qaP --json --meta`, keyed by attribute
+// path. The channel prefix is dropped so rows match the flake driver's.
+func parseNixEnvQuery(out []byte) ([]searchRow, error) {
+	out = bytes.TrimSpace(out)
+	if len(out) == 0 {
+		return nil, nil
+	}
+	entries := map[string]nixEnvPackage{}
+	if err := json.Unmarshal(out, &entries); err != nil {
+		return nil, fmt.Errorf("nix-env query: %w", err)
+	}
+	rows := make([]searchRow, 0, len(entries))
+	for attrPath, entry := range entries {
+		_, name, ok := strings.Cut(attrPath, ".")
+		if !ok || name == "" {
+			continue
+		}
+		rows = append(rows, searchRow{Name: name, Desc: nixDescText(entry.Meta.Description)})
+	}
+	sortNixRows(rows)
+	return rows, nil
+}
+
+// nixEnvInstall is an installed nix-env package: Attr is the channel-less
+// attribute path search rows use, Name the derivation name `nix-env -e`
+// takes.
+type nixEnvInstall struct {
+	Attr string
+	Name string
+}
+
+// nixEnvInstalled lists the user environment keyed like search rows. The
+// environment only records derivation names, so the installed pnames are
+// looked up in the channel and matched back by output path.
+func nixEnvInstalled() ([]nixEnvInstall, error) {
+	out, err := runOutputQuietErr("nix-env", "-q", "--json", "--out-path")
+	if err != nil {
+		return nil, err
+	}
+	installed := map[string]nixEnvPackage{}
+	if err := json.Unmarshal(bytes.TrimSpace(out), &installed); err != nil {
+		return nil, fmt.Errorf("nix-env query: %w", err)
+	}
+	if len(installed) == 0 {
+		return nil, nil
+	}
+
+	pnames := make([]string, 0, len(installed))
+	seen := make(map[string]struct{}, len(installed))
+	for _, entry := range installed {
+		if _, ok := seen[entry.Pname]; entry.Pname == "" || ok {
+			continue
+		}
+		seen[entry.Pname] = struct{}{}
+		pnames = append(pnames, entry.Pname)
+	}
+	sort.Strings(pnames)
+	available := map[string]nixEnvPackage{}
+	if len(pnames) > 0 {
+		if out, err := runOutputQuietErr("nix-env", append([]string{"-qaP", "--json", "--out-path"}, pnames...)...); err == nil {
+			_ = json.Unmarshal(bytes.TrimSpace(out), &available)
+		}
+	}
+	return matchNixEnvInstalled(installed, available), nil
+}
+
+// matchNixEnvInstalled pairs each installed package with the channel
+// attribute that builds the same output, else one with the same pname,
+// preferring the attribute named after it. Packages the channel no longer
+// has keep their pname.
+func matchNixEnvInstalled(installed, available map[string]nixEnvPackage) []nixEnvInstall {
+	attrs := make([]string, 0, len(available))
+	for attrPath := range available {
+		attrs = append(attrs, attrPath)
+	}
+	sort.Strings(attrs)
+
+	result := make([]nixEnvInstall, 0, len(installed))
+	for key, entry := range installed {
+		name := entry.Name
+		if name == "" {
+			name = key
+		}
+		byOutput, byPname := "", ""
+		for _, attrPath := range attrs {
+			candidate := available[attrPath]
+			_, attr, ok := strings.Cut(attrPath, ".")
+			if !ok || attr == "" {
+				continue
+			}
+			if out := entry.Outputs["out"]; out != "" && candidate.Outputs["out"] == out {
+				byOutput = attr
+				break
+			}
+			if entry.Pname != "" && candidate.Pname == entry.Pname && (byPname == "" || attr == entry.Pname) {
+				byPname = attr
+			}
+		}
+		attr := byOutput
+		if attr == "" {
+			attr = byPname
+		}
+		if attr == "" {
+			attr = entry.Pname
+		}
+		if attr == "" {
+			attr = name
+		}
+		result = append(result, nixEnvInstall{Attr: attr, Name: name})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Attr < result[j].Attr
+	})
+	return result
+}
+
+func nixDescText(desc string) string {
+	if desc = strings.Join(strings.Fields(desc), " "); desc != "" {
+		return desc
+	}
+	return "-"
+}
+
+func sortNixRows(rows []searchRow) {
+	sort.Slice(rows, func(i, j int) bool {
+		return rows[i].Name < rows[j].Name
+	})
+}
+
+// nixMeta is the part of a derivation's meta attribute the preview shows.
+// homepage and license may be a single value or a list.
+type nixMeta struct {
+	Description     string          `json:"description"`
+	LongDescription string          `json:"longDescription"`
+	Homepage        json.RawMessage `json:"homepage"`
+	License         json.RawMessage `json:"license"`
+	Maintainers     []struct {
+		Name   string `json:"name"`
+		Github string `json:"github"`
+	} `json:"maintainers"`
+	MainProgram string `json:"mainProgram"`
+	Position    string `json:"position"`
+	Unfree      bool   `json:"unfree"`
+	Broken      bool   `json:"broken"`
+}
+
+// formatNixEnvInfo renders the single package `nix-env -qaA --json --meta`
+// returns.
+func formatNixEnvInfo(out []byte) (string, error) {
+	entries := map[string]nixEnvPackage{}
+	if err := json.Unmarshal(bytes.TrimSpace(out), &entries); err != nil {
+		return "", fmt.Errorf("nix-env query: %w", err)
+	}
+	for attrPath, entry := range entries {
+		name := entry.Pname
+		if name == "" {
+			name = attrPath
+		}
+		return formatNixInfo(name, entry.Version, entry.Meta), nil
+	}
+	return "", fmt.Errorf("nix-env query: no package found")
+}
+
+func formatNixInfo(name, version string, meta nixMeta) string {
+	var b strings.Builder
+	if version != "" {
+		fmt.Fprintf(&b, "%s (%s)\n", name, version)
+	} else {
+		fmt.Fprintf(&b, "%s\n", name)
+	}
+
+	maintainers := make([]string, 0, len(meta.Maintainers))
+	for _, maintainer := range meta.Maintainers {
+		if maintainer.Name != "" {
+			maintainers = append(maintainers, maintainer.Name)
+		} else if maintainer.Github != "" {
+			maintainers = append(maintainers, maintainer.Github)
+		}
+	}
+	flags := make([]string, 0, 2)
+	if meta.Unfree {
+		flags = append(flags, "unfree")
+	}
+	if meta.Broken {
+		flags = append(flags, "broken")
+	}
+
+	for _, field := range [][2]string{
+		{"Description", meta.Description},
+		{"Long description", strings.TrimSpace(meta.LongDescription)},
+		{"Homepage", strings.Join(nixMetaStrings(meta.Homepage, nil), ", ")},
+		{"License", strings.Join(nixMetaStrings(meta.License, nixLicenseName), ", ")},
+		{"Maintainers", strings.Join(maintainers, ", ")},
+		{"Main program", meta.MainProgram},
+		{"Flags", strings.Join(flags, ", ")},
+		{"Position", meta.Position},
+	} {
+		if field[1] != "" {
+			fmt.Fprintf(&b, "%s: %s\n", field[0], field[1])
+		}
+	}
+	return b.String()
+}
+
+// nixMetaStrings flattens a meta value that is a string, an object or a
+// list of either. Objects are named by nameOf.
+func nixMetaStrings(raw json.RawMessage, nameOf func(json.RawMessage) string) []string {
+	if len(raw) == 0 {
+		return nil
+	}
+	var list []json.RawMessage
+	if err := json.Unmarshal(raw, &list); err != nil {
+		list = []json.RawMessage{raw}
+	}
+	values := make([]string, 0, len(list))
+	for _, item := range list {
+		var text string
+		if err := json.Unmarshal(item, &text); err != nil && nameOf != nil {
+			text = nameOf(item)
+		}
+		if text = strings.TrimSpace(text); text != "" {
+			values = append(values, text)
+		}
+	}
+	return values
+}
+
+// nixLicenseName prefers a license's SPDX id, then its short and full names.
+func nixLicenseName(raw json.RawMessage) string {
+	var license struct {
+		SpdxID    string `json:"spdxId"`
+		ShortName string `json:"shortName"`
+		FullName  string `json:"fullName"`
+	}
+	if err := json.Unmarshal(raw, &license); err != nil {
+		return ""
+	}
+	for _, name := range []string{license.SpdxID, license.ShortName, license.FullName} {
+		if name != "" {
+			return name
+		}
+	}
+	return ""
+}
+
+// nixProfileElement is one profile element: Name is the row name and
+// Selector what `nix profile remove` accepts for it.
+type nixProfileElement struct {
+	Name     string
+	Selector string
+}
+
+func parseNixProfileJSON(out []byte) ([]string, bool) {
+	elements, ok := parseNixProfileElements(out)
+	if !ok {
+		return nil, false
+	}
+	names := make([]string, 0, len(elements))
+	for _, element := range elements {
+		names = append(names, element.Name)
+	}
+	return names, true
+}
+
+// parseNixProfileElements handles both `nix profile list --json` layouts:
+// the element array used up to Nix 2.19, whose elements are removed by
+// index, and the name-keyed map used since, removed by name.
+func parseNixProfileElements(out []byte) ([]nixProfileElement, bool) {
+	type profileElement struct {
+		AttrPath string `json:"attrPath"`
+	}
+	var doc struct {
+		Elements json.RawMessage `json:"elements"`
+	}
+	if err := json.Unmarshal(bytes.TrimSpace(out), &doc); err != nil || len(doc.Elements) == 0 {
+		return nil, false
+	}
+
+	elements := make([]nixProfileElement, 0)
+	var list []profileElement
+	if err := json.Unmarshal(doc.Elements, &list); err == nil {
+		for i, element := range list {
+			if name := nixAttrName(element.AttrPath); name != "" {
+				elements = append(elements, nixProfileElement{Name: name, Selector: strconv.Itoa(i)})
+			}
+		}
+		return elements, true
+	}
+
+	byName := map[string]profileElement{}
+	if err := json.Unmarshal(doc.Elements, &byName); err != nil {
+		return nil, false
+	}
+	for key, element := range byName {
+		name := nixAttrName(element.AttrPath)
+		if name == "" {
+			name = key
+		}
+		elements = append(elements, nixProfileElement{Name: name, Selector: key})
+	}
+	sort.Slice(elements, func(i, j int) bool {
+		return elements[i].Name < elements[j].Name
+	})
+	return elements, true
+}
+
+// parseNixProfileList reads the human-readable `nix profile list` output,
+// either the "Flake attribute:" blocks printed by newer Nix or the older
+// one-line "<index> <flake>#<attr> <locked> <store path>" form.
+func parseNixProfileList(out []byte) []string {
+	names := make([]string, 0)
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "Flake attribute:") {
+			if name := nixAttrName(strings.TrimPrefix(line, "Flake attribute:")); name != "" {
+				names = append(names, name)
+			}
+			continue
+		}
+		parts := strings.Fields(line)
+		if len(parts) < 2 || !strings.Contains(parts[1], "#") {
+			continue
+		}
+		if name := nixAttrName(parts[1]); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
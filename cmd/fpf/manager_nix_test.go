@@ -0,0 +1,235 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func readFixture(t *testing.T, name string) []byte {
+	t.Helper()
+	raw, err := os.ReadFile(filepath.Join("..", "..", "tests", "fixtures", name))
+	if err != nil {
+		t.Fatalf("read fixture %s: %v", name, err)
+	}
+	return raw
+}
+
+func TestParseNixSearch(t *testing.T) {
+	rows, err := parseNixSearch(readFixture(t, "nix-search.json"))
+	if err != nil {
+		t.Fatalf("parseNixSearch: %v", err)
+	}
+	want := []searchRow{
+		{Name: "ripgrep", Desc: "Utility that combines the usability of The Silver Searcher with the raw speed of grep"},
+		{Name: "ripgrep-all", Desc: "Ripgrep, but also search in PDFs, E-Books, Office documents, zip, tar.gz, and more"},
+		{Name: "vimPlugins.vim-ripgrep", Desc: "-"},
+	}
+	if len(rows) != len(want) {
+		t.Fatalf("parseNixSearch returned %d rows want %d: %+v", len(rows), len(want), rows)
+	}
+	for i := range want {
+		if rows[i] != want[i] {
+			t.Fatalf("row %d=%+v want %+v", i, rows[i], want[i])
+		}
+	}
+
+	if rows, err := parseNixSearch([]byte("error: flake 'nixpkgs' does not exist")); err == nil || len(rows) != 0 {
+		t.Fatalf("expected an error for non-JSON output, got %+v, %v", rows, err)
+	}
+	if rows, err := parseNixSearch(nil); err != nil || len(rows) != 0 {
+		t.Fatalf("expected no rows and no error for empty output, got %+v, %v", rows, err)
+	}
+}
+
+func TestParseNixEnv(t *testing.T) {
+	rows, err := parseNixEnvQuery(readFixture(t, "nix-env-query.json"))
+	if err != nil {
+		t.Fatalf("parseNixEnvQuery: %v", err)
+	}
+	if len(rows) != 2 || rows[0].Name != "ripgrep" || rows[1].Name != "ripgrep-all" ||
+		rows[0].Desc != "Utility that combines the usability of The Silver Searcher with the raw speed of grep" {
+		t.Fatalf("unexpected nix-env rows: %+v", rows)
+	}
+
+	installed := map[string]nixEnvPackage{}
+	if err := json.Unmarshal(readFixture(t, "nix-env-installed.json"), &installed); err != nil {
+		t.Fatal(err)
+	}
+	available := map[string]nixEnvPackage{}
+	if err := json.Unmarshal([]byte(`{
+		"nixpkgs.fzf":{"name":"fzf-0.56.3","pname":"fzf","outputs":{"out":"/nix/store/0x6dxbxkyi2kjbs6p4x6n5mn9xnkb2zi-fzf-0.56.3"}},
+		"nixpkgs.ripgrep":{"name":"ripgrep-14.1.0","pname":"ripgrep","outputs":{"out":"/nix/store/9m3mz0a4xw6b4qf8sxl3m7cg3lz0ljqj-ripgrep-14.1.0"}},
+		"nixpkgs.ripgrep_14":{"name":"ripgrep-14.1.1","pname":"ripgrep","outputs":{"out":"/nix/store/bq0x9hzvzvqsgcq1hw2lcdzqxkm3rbbm-ripgrep-14.1.1"}}
+	}`), &available); err != nil {
+		t.Fatal(err)
+	}
+	want := []nixEnvInstall{{Attr: "fzf", Name: "fzf-0.54.3"}, {Attr: "ripgrep_14", Name: "ripgrep-14.1.1"}}
+	if got := matchNixEnvInstalled(installed, available); !reflect.DeepEqual(got, want) {
+		t.Fatalf("matchNixEnvInstalled=%+v want %+v", got, want)
+	}
+	want = []nixEnvInstall{{Attr: "fzf", Name: "fzf-0.54.3"}, {Attr: "ripgrep", Name: "ripgrep-14.1.1"}}
+	if got := matchNixEnvInstalled(installed, nil); !reflect.DeepEqual(got, want) {
+		t.Fatalf("matchNixEnvInstalled without a channel=%+v want %+v", got, want)
+	}
+}
+
+func TestNixEnvDriver(t *testing.T) {
+	dir := t.TempDir()
+	log := filepath.Join(dir, "log")
+	writeMockExecutable(t, dir, "nix-env", `#!/usr/bin/env bash
+printf '%s\n' "$*" >> "`+log+`"
+case "$1 $3" in
+    "-qaP --meta") cat "`+filepath.Join("..", "..", "tests", "fixtures", "nix-env-query.json")+`" ;;
+    "-qaP --out-path") printf '{"nixpkgs.ripgrep":{"name":"ripgrep-14.1.1","pname":"ripgrep","outputs":{"out":"/nix/store/bq0x9hzvzvqsgcq1hw2lcdzqxkm3rbbm-ripgrep-14.1.1"}}}' ;;
+    "-q --out-path") cat "`+filepath.Join("..", "..", "tests", "fixtures", "nix-env-installed.json")+`" ;;
+esac
+`)
+	t.Setenv("PATH", dir+":/usr/bin:/bin")
+	t.Setenv("HOME", dir)
+	t.Setenv("FPF_NIX_CHANNEL", "nixpkgs")
+	if err := os.MkdirAll(filepath.Join(dir, ".nix-profile"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, ".nix-profile", "manifest.nix"), []byte("[ ]"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if got := nixDriver(); got != "env" {
+		t.Fatalf("nixDriver()=%q want env for a nix-env profile", got)
+	}
+
+	rows, err := executeSearchEntries(searchInput{Manager: "nix", Query: "ripgrep pdfs"})
+	if err != nil || len(rows) != 1 || rows[0].Name != "ripgrep-all" {
+		t.Fatalf("expected the second word to filter nix-env rows, got %+v, %v", rows, err)
+	}
+	if err := executeManagerAction(managerActionInput{Action: "install", Manager: "nix", Packages: []string{"ripgrep"}}); err != nil {
+		t.Fatalf("install: %v", err)
+	}
+	raw, _ := os.ReadFile(log)
+	if want := "-qaP --json --meta .*ripgrep.*\n-iA nixpkgs.ripgrep\n"; string(raw) != want {
+		t.Fatalf("nix-env calls=%q want %q", raw, want)
+	}
+
+	names, err := nixManager{}.ListInstalled()
+	if err != nil || strings.Join(names, ",") != "fzf,ripgrep" {
+		t.Fatalf("expected nix-env installs keyed like search rows, got %v, %v", names, err)
+	}
+	if err := os.Truncate(log, 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := executeManagerAction(managerActionInput{Action: "remove", Manager: "nix", Packages: []string{"ripgrep"}}); err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+	raw, _ = os.ReadFile(log)
+	want := "-q --json --out-path\n-qaP --json --out-path fzf ripgrep\n-e ripgrep-14.1.1\n"
+	if string(raw) != want {
+		t.Fatalf("nix-env calls=%q want %q", raw, want)
+	}
+}
+
+func TestFormatNixInfo(t *testing.T) {
+	single := []byte(`{"nixpkgs.ripgrep":{"pname":"ripgrep","version":"14.1.1","meta":{
+		"description":"Fast grep","homepage":"https://github.com/BurntSushi/ripgrep",
+		"license":[{"spdxId":"Unlicense","fullName":"The Unlicense"},{"shortName":"mit","fullName":"MIT License"}],
+		"maintainers":[{"name":"Iliana","github":"ilianaw"},{"github":"zowoq"}],"mainProgram":"rg","unfree":false}}}`)
+	info, err := formatNixEnvInfo(single)
+	if err != nil {
+		t.Fatalf("formatNixEnvInfo: %v", err)
+	}
+	for _, want := range []string{
+		"ripgrep (14.1.1)\n",
+		"Homepage: https://github.com/BurntSushi/ripgrep\n",
+		"License: Unlicense, mit\n",
+		"Maintainers: Iliana, zowoq\n",
+		"Main program: rg\n",
+	} {
+		if !strings.Contains(info, want) {
+			t.Fatalf("info missing %q:\n%s", want, info)
+		}
+	}
+	if strings.Contains(info, "Flags:") {
+		t.Fatalf("expected no flags for a free, working package:\n%s", info)
+	}
+
+	if _, err := formatNixEnvInfo([]byte("{}")); err == nil {
+		t.Fatal("expected an error when nix-env finds no package")
+	}
+	meta := nixMeta{Description: "Hello", License: []byte(`"GPL-3.0"`), Unfree: true}
+	if got := formatNixInfo("hello", "", meta); got != "hello\nDescription: Hello\nLicense: GPL-3.0\nFlags: unfree\n" {
+		t.Fatalf("formatNixInfo=%q", got)
+	}
+}
+
+func TestParseNixProfile(t *testing.T) {
+	names, ok := parseNixProfileJSON(readFixture(t, "nix-profile-list.json"))
+	if !ok || strings.Join(names, ",") != "fzf,ripgrep" {
+		t.Fatalf("parseNixProfileJSON=(%v,%v) want [fzf ripgrep]", names, ok)
+	}
+
+	legacy := []byte(`{"elements":[{"active":true,"attrPath":"legacyPackages.x86_64-linux.hello","originalUrl":"flake:nixpkgs"}],"version":2}`)
+	names, ok = parseNixProfileJSON(legacy)
+	if !ok || strings.Join(names, ",") != "hello" {
+		t.Fatalf("parseNixProfileJSON(v2)=(%v,%v) want [hello]", names, ok)
+	}
+
+	if got := strings.Join(parseNixProfileList(readFixture(t, "nix-profile-list.txt")), ","); got != "fzf,ripgrep" {
+		t.Fatalf("parseNixProfileList=%q want fzf,ripgrep", got)
+	}
+	oneLine := []byte("0 flake:nixpkgs#legacyPackages.x86_64-linux.jq github:NixOS/nixpkgs/abc#legacyPackages.x86_64-linux.jq /nix/store/abc-jq-1.7\n")
+	if got := strings.Join(parseNixProfileList(oneLine), ","); got != "jq" {
+		t.Fatalf("parseNixProfileList(legacy)=%q want jq", got)
+	}
+}
+
+func TestNixProfileRemoveUsesElementSelectors(t *testing.T) {
+	dir := t.TempDir()
+	log := filepath.Join(dir, "log")
+	listing := filepath.Join(dir, "profile.json")
+	writeMockExecutable(t, dir, "nix", `#!/usr/bin/env bash
+shift 2
+case "$1 $2" in
+    "profile list") cat "`+listing+`" ;;
+    *) printf '%s\n' "$*" >> "`+log+`" ;;
+esac
+`)
+	t.Setenv("PATH", dir+":/usr/bin:/bin")
+	t.Setenv("FPF_NIX_DRIVER", "profile")
+
+	for _, tc := range []struct {
+		listing string
+		want    string
+	}{
+		{`{"elements":[{"attrPath":"legacyPackages.x86_64-linux.fzf"},{"attrPath":"legacyPackages.x86_64-linux.ripgrep"}],"version":2}`, "profile remove 1 jq\n"},
+		{string(readFixture(t, "nix-profile-list.json")), "profile remove ripgrep jq\n"},
+	} {
+		if err := os.WriteFile(listing, []byte(tc.listing), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		_ = os.Remove(log)
+		if err := (nixManager{}).Remove([]string{"ripgrep", "jq"}); err != nil {
+			t.Fatalf("remove: %v", err)
+		}
+		if raw, _ := os.ReadFile(log); string(raw) != tc.want {
+			t.Fatalf("nix calls=%q want %q", raw, tc.want)
+		}
+	}
+}
+
+func TestNixAttrName(t *testing.T) {
+	tests := map[string]string{
+		"legacyPackages.x86_64-linux.ripgrep":                "ripgrep",
+		"legacyPackages.aarch64-darwin.python3Packages.rich": "python3Packages.rich",
+		"packages.x86_64-linux.default":                      "default",
+		"flake:nixpkgs#legacyPackages.x86_64-linux.jq":       "jq",
+		"hello":          "hello",
+		"legacyPackages": "legacyPackages",
+	}
+	for in, want := range tests {
+		if got := nixAttrName(in); got != want {
+			t.Fatalf("nixAttrName(%q)=%q want %q", in, got, want)
+		}
+	}
+}
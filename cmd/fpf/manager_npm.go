@@ -0,0 +1,143 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+type npmManager struct {
+	managerSpec
+}
+
+func init() {
+	registerManager(npmManager{managerSpec{
+		name:     "npm",
+		label:    "npm",
+		order:    130,
+		binaries: []string{"npm"},
+	}})
+}
+
+func (npmManager) Search(input searchInput) ([]searchRow, error) {
+	return npmRegistrySearch(input)
+}
+
+func (npmManager) ListInstalled() ([]string, error) {
+	out, err := runOutputQuietErr("npm", "ls", "-g", "--depth=0", "--parseable")
+	if err != nil {
+		return nil, err
+	}
+	return parseNpmInstalled(out), nil
+}
+
+func (npmManager) Install(pkgs []string) error {
+	return runCommand("npm", append([]string{"install", "-g"}, pkgs...)...)
+}
+
+func (npmManager) Remove(pkgs []string) error {
+	return runCommand("npm", append([]string{"uninstall", "-g"}, pkgs...)...)
+}
+
+func (npmManager) Update() error {
+	return runCommand("npm", "update", "-g")
+}
+
+func (npmManager) Refresh() error {
+	return runCommand("npm", "cache", "verify")
+}
+
+func (npmManager) ShowInfo(pkg string) error {
+	return runCommandQuietErr("npm", "view", pkg)
+}
+
+func npmRegistrySearch(input searchInput) ([]searchRow, error) {
+	out, err := input.runOutput("npm", "search", input.Query, fmt.Sprintf("--searchlimit=%d", input.NPMSearchLimit), "--parseable")
+	if err != nil {
+		return nil, err
+	}
+	return parseNpmSearch(out), nil
+}
+
+// jsRegistrySearch backs the JS managers without a search command of their
+// own (pnpm, yarn). It runs npm's registry search unless npm is already in the
+// multi-manager set, so the same query is not sent to the registry twice.
+func jsRegistrySearch(input searchInput) ([]searchRow, error) {
+	if !input.AllowNPMRegistryFallback {
+		return nil, nil
+	}
+	if _, err := exec.LookPath("npm"); err != nil {
+		return nil, nil
+	}
+	return npmRegistrySearch(input)
+}
+
+func parseNpmSearch(out []byte) []searchRow {
+	rows := make([]searchRow, 0)
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		line := scanner.Text()
+		parts := strings.Split(line, "\t")
+		if len(parts) < 2 {
+			continue
+		}
+		name := strings.TrimSpace(parts[0])
+		desc := strings.TrimSpace(parts[1])
+		if name == "" {
+			continue
+		}
+		if desc == "" {
+			desc = "-"
+		}
+		rows = append(rows, searchRow{Name: name, Desc: desc})
+	}
+	return rows
+}
+
+func parseNpmInstalled(out []byte) []string {
+	names := make([]string, 0)
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		if lineNumber == 1 {
+			continue
+		}
+
+		line := scanner.Text()
+		line = strings.ReplaceAll(line, "\r", "")
+		line = strings.ReplaceAll(line, "\\", "/")
+
+		parts := strings.Split(line, "/")
+		if len(parts) == 0 {
+			continue
+		}
+
+		pkg := ""
+		for i := len(parts) - 1; i >= 0; i-- {
+			if strings.TrimSpace(parts[i]) != "" {
+				pkg = strings.TrimSpace(parts[i])
+				break
+			}
+		}
+		if pkg == "" {
+			continue
+		}
+
+		for i := len(parts) - 2; i >= 0; i-- {
+			prev := strings.TrimSpace(parts[i])
+			if prev == "" {
+				continue
+			}
+			if strings.HasPrefix(prev, "@") {
+				pkg = prev + "/" + pkg
+			}
+			break
+		}
+
+		names = append(names, pkg)
+	}
+	return names
+}
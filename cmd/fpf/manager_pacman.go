@@ -0,0 +1,467 @@
+package main
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type pacmanManager struct {
+	managerSpec
+}
+
+func init() {
+	registerManager(pacmanManager{managerSpec{
+		name:     "pacman",
+		label:    "Pacman",
+		order:    30,
+		binaries: []string{"pacman"},
+		root:     true,
+	}})
+}
+
+// Search filters the cached sync database catalog. `pacman -Ss` only runs
+// when there are no sync databases to build it from.
+func (pacmanManager) Search(input searchInput) ([]searchRow, error) {
+	if catalogRows, err := loadPacmanCatalogRows(input.Query); err == nil {
+		return catalogRows, nil
+	}
+	out, err := input.runOutput("pacman", "-Ss", "--", input.Query)
+	if err != nil {
+		return nil, err
+	}
+	return parsePacmanSearch(out), nil
+}
+
+func (pacmanManager) ListInstalled() ([]string, error) {
+	packages, err := pacmanInstalledPackages()
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(packages))
+	for _, pkg := range packages {
+		names = append(names, pkg.Name)
+	}
+	return names, nil
+}
+
+// InstalledDetails describes each installed package as
+// "version (architecture, explicit|dependency)".
+func (pacmanManager) InstalledDetails() ([]searchRow, error) {
+	packages, err := pacmanInstalledPackages()
+	if err != nil {
+		return nil, err
+	}
+	rows := make([]searchRow, 0, len(packages))
+	for _, pkg := range packages {
+		rows = append(rows, searchRow{Name: pkg.Name, Desc: pkg.describeInstalled()})
+	}
+	return rows, nil
+}
+
+func (pacmanManager) Install(pkgs []string) error {
+	return runRootCommand("pacman", append([]string{"-S", "--needed"}, pkgs...)...)
+}
+
+func (pacmanManager) Remove(pkgs []string) error {
+	return runRootCommand("pacman", append([]string{"-Rsn"}, pkgs...)...)
+}
+
+func (pacmanManager) Update() error {
+	return runRootCommand("pacman", "-Syu")
+}
+
+func (pacmanManager) Refresh() error {
+	return runRootCommand("pacman", "-Sy")
+}
+
+func (pacmanManager) ShowInfo(pkg string) error {
+	if err := runCommandQuietErr("pacman", "-Qi", pkg); err != nil {
+		runCommandQuietErr("pacman", "-Si", pkg)
+	}
+	fmt.Println()
+	runCommandQuietErr("pacman", "-Ql", pkg)
+	return nil
+}
+
+func (pacmanManager) InstallFzf() error {
+	return runRootCommand("pacman", "-S", "--needed", "fzf")
+}
+
+func parsePacmanSearch(out []byte) []searchRow {
+	rows := make([]searchRow, 0)
+	lines := splitLines(out)
+	for i := 0; i+1 < len(lines); i += 2 {
+		head := strings.TrimSpace(lines[i])
+		if head == "" {
+			continue
+		}
+		parts := strings.Fields(head)
+		if len(parts) == 0 {
+			continue
+		}
+		pkg := parts[0]
+		if strings.Contains(pkg, "/") {
+			seg := strings.SplitN(pkg, "/", 2)
+			if len(seg) == 2 {
+				pkg = seg[1]
+			}
+		}
+		desc := strings.TrimSpace(lines[i+1])
+		if desc == "" {
+			desc = "-"
+		}
+		rows = append(rows, searchRow{Name: pkg, Desc: desc})
+	}
+	return rows
+}
+
+func parsePacmanInstalled(out []byte) []string {
+	names := make([]string, 0)
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		parts := strings.Fields(line)
+		if len(parts) > 0 {
+			names = append(names, parts[0])
+		}
+	}
+	return names
+}
+
+// pacmanRoot is the filesystem root pacman's config and databases are read
+// from. FPF_PACMAN_ROOT points it at a chroot or a test tree.
+func pacmanRoot() string {
+	if root := strings.TrimSpace(os.Getenv("FPF_PACMAN_ROOT")); root != "" {
+		return root
+	}
+	return "/"
+}
+
+func pacmanDBPath() string {
+	return filepath.Join(pacmanRoot(), "var", "lib", "pacman")
+}
+
+func pacmanConfPath() string {
+	return filepath.Join(pacmanRoot(), "etc", "pacman.conf")
+}
+
+// pacmanPackage is one desc entry from a sync or local database.
+type pacmanPackage struct {
+	Name    string
+	Version string
+	Desc    string
+	Arch    string
+	Size    int64
+	Repo    string
+	Reason  string
+}
+
+func (p pacmanPackage) describeInstalled() string {
+	version := p.Version
+	if version == "" {
+		version = "installed"
+	}
+	extra := make([]string, 0, 2)
+	for _, field := range []string{p.Arch, p.Reason} {
+		if field != "" {
+			extra = append(extra, field)
+		}
+	}
+	if len(extra) == 0 {
+		return version
+	}
+	return version + " (" + strings.Join(extra, ", ") + ")"
+}
+
+// parsePacmanDesc reads a database desc file: "%FIELD%" headers followed by
+// value lines, separated by blank lines.
+func parsePacmanDesc(raw []byte) pacmanPackage {
+	var pkg pacmanPackage
+	field := ""
+	for _, line := range strings.Split(strings.ReplaceAll(string(raw), "\r\n", "\n"), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			field = ""
+			continue
+		}
+		if strings.HasPrefix(line, "%") && strings.HasSuffix(line, "%") && len(line) > 2 {
+			field = line
+			continue
+		}
+		switch field {
+		case "%NAME%":
+			pkg.Name = line
+		case "%VERSION%":
+			pkg.Version = line
+		case "%DESC%":
+			pkg.Desc = line
+		case "%ARCH%":
+			pkg.Arch = line
+		case "%ISIZE%", "%SIZE%":
+			if pkg.Size == 0 {
+				pkg.Size, _ = strconv.ParseInt(line, 10, 64)
+			}
+		case "%REASON%":
+			pkg.Reason = line
+		}
+		field = ""
+	}
+	return pkg
+}
+
+// pacmanSyncDBs returns the sync databases in pacman.conf repository order;
+// databases not listed there follow alphabetically.
+func pacmanSyncDBs() []string {
+	matches, _ := filepath.Glob(filepath.Join(pacmanDBPath(), "sync", "*.db"))
+	order := make(map[string]int)
+	for i, repo := range pacmanConfRepos() {
+		order[repo] = i + 1
+	}
+	rank := func(path string) int {
+		if n, ok := order[strings.TrimSuffix(filepath.Base(path), ".db")]; ok {
+			return n
+		}
+		return len(order) + 1
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		ri, rj := rank(matches[i]), rank(matches[j])
+		if ri != rj {
+			return ri < rj
+		}
+		return matches[i] < matches[j]
+	})
+	return matches
+}
+
+// pacmanConfRepos lists the [repository] sections of pacman.conf in order.
+func pacmanConfRepos() []string {
+	raw, err := os.ReadFile(pacmanConfPath())
+	if err != nil {
+		return nil
+	}
+	repos := make([]string, 0)
+	for _, line := range splitLines(raw) {
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, "[") || !strings.HasSuffix(line, "]") {
+			continue
+		}
+		if name := strings.TrimSpace(line[1 : len(line)-1]); name != "" && name != "options" {
+			repos = append(repos, name)
+		}
+	}
+	return repos
+}
+
+func pacmanFileStamp(path string) string {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "missing"
+	}
+	return fmt.Sprintf("%d:%d", info.ModTime().UnixNano(), info.Size())
+}
+
+// pacmanCatalogFingerprint keys the catalog on every sync database and on
+// pacman.conf, whose repository order decides which repo a package is
+// listed from.
+func pacmanCatalogFingerprint() string {
+	parts := []string{"1", "pacman", "sync", pacmanDBPath(), "conf=" + pacmanFileStamp(pacmanConfPath())}
+	for _, path := range pacmanSyncDBs() {
+		parts = append(parts, filepath.Base(path)+"="+pacmanFileStamp(path))
+	}
+	return strings.Join(parts, "|")
+}
+
+func loadPacmanCatalogRows(q string) ([]searchRow, error) {
+	dbs := pacmanSyncDBs()
+	if len(dbs) == 0 {
+		return nil, fmt.Errorf("pacman catalog: no sync databases under %s", filepath.Join(pacmanDBPath(), "sync"))
+	}
+	key := cacheChecksum(pacmanCatalogFingerprint())
+	cachePath := filepath.Join(cacheRootPath(), "search-catalog", "pacman", key+".tsv")
+
+	if raw, err := os.ReadFile(cachePath); err == nil {
+		rows := parseCachedRows(raw)
+		if len(rows) > 0 {
+			return filterCatalogRows(rows, q), nil
+		}
+	}
+
+	rows, err := buildPacmanCatalogRows(dbs)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("pacman catalog: no packages in the sync databases")
+	}
+
+	_ = os.MkdirAll(filepath.Dir(cachePath), 0o755)
+	_ = os.WriteFile(cachePath, []byte(renderAPT(rows)), 0o644)
+
+	return filterCatalogRows(rows, q), nil
+}
+
+// buildPacmanCatalogRows reads each sync database in order; like pacman, the
+// first repository that carries a package wins.
+func buildPacmanCatalogRows(dbs []string) ([]searchRow, error) {
+	seen := make(map[string]bool)
+	rows := make([]searchRow, 0)
+	var firstErr error
+	for _, path := range dbs {
+		repo := strings.TrimSuffix(filepath.Base(path), ".db")
+		packages, err := readPacmanSyncDB(path)
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+		for _, pkg := range packages {
+			if seen[pkg.Name] {
+				continue
+			}
+			seen[pkg.Name] = true
+			pkg.Repo = repo
+			rows = append(rows, searchRow{Name: pkg.Name, Desc: pacmanRowDesc(pkg)})
+		}
+	}
+	if len(rows) == 0 && firstErr != nil {
+		return nil, firstErr
+	}
+	return rows, nil
+}
+
+// pacmanRowDesc renders "[repo] description (version, size)".
+func pacmanRowDesc(pkg pacmanPackage) string {
+	parts := make([]string, 0, 3)
+	if pkg.Repo != "" {
+		parts = append(parts, "["+pkg.Repo+"]")
+	}
+	if pkg.Desc != "" {
+		parts = append(parts, pkg.Desc)
+	}
+	extra := make([]string, 0, 2)
+	if pkg.Version != "" {
+		extra = append(extra, pkg.Version)
+	}
+	if pkg.Size > 0 {
+		extra = append(extra, formatAptInstalledSize(pkg.Size/1024))
+	}
+	if len(extra) > 0 {
+		parts = append(parts, "("+strings.Join(extra, ", ")+")")
+	}
+	if len(parts) == 0 {
+		return "-"
+	}
+	return strings.Join(parts, " ")
+}
+
+// readPacmanSyncDB reads the desc entries of a sync database, a tar archive
+// that is usually gzip or zstd compressed.
+func readPacmanSyncDB(path string) ([]pacmanPackage, error) {
+	reader, err := openDecompressed(path)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	packages := make([]pacmanPackage, 0)
+	archive := tar.NewReader(reader)
+	for {
+		header, err := archive.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return packages, err
+		}
+		if header.Typeflag != tar.TypeReg || filepath.Base(header.Name) != "desc" {
+			continue
+		}
+		raw, err := io.ReadAll(archive)
+		if err != nil {
+			return packages, err
+		}
+		if pkg := parsePacmanDesc(raw); pkg.Name != "" {
+			packages = append(packages, pkg)
+		}
+	}
+	return packages, nil
+}
+
+func pacmanLocalDir() string {
+	return filepath.Join(pacmanDBPath(), "local")
+}
+
+// pacmanLocalStamp changes whenever pacman adds or removes a local entry,
+// since each package is a directory in the local database.
+func pacmanLocalStamp() string {
+	info, err := os.Stat(pacmanLocalDir())
+	if err != nil {
+		return "local=missing"
+	}
+	return fmt.Sprintf("local=%d", info.ModTime().UnixNano())
+}
+
+// pacmanInstalledPackages reads the local database, falling back to
+// `pacman -Q` when it is not readable.
+func pacmanInstalledPackages() ([]pacmanPackage, error) {
+	if packages, err := readPacmanLocalDB(); err == nil {
+		return packages, nil
+	}
+	out, err := runOutputQuietErr("pacman", "-Q")
+	if err != nil {
+		return nil, err
+	}
+	packages := make([]pacmanPackage, 0)
+	for _, line := range splitLines(out) {
+		parts := strings.Fields(line)
+		if len(parts) == 0 {
+			continue
+		}
+		pkg := pacmanPackage{Name: parts[0]}
+		if len(parts) > 1 {
+			pkg.Version = parts[1]
+		}
+		packages = append(packages, pkg)
+	}
+	return packages, nil
+}
+
+// readPacmanLocalDB reads local/<name>-<version>/desc for every installed
+// package. %REASON% 1 marks packages installed as dependencies.
+func readPacmanLocalDB() ([]pacmanPackage, error) {
+	entries, err := os.ReadDir(pacmanLocalDir())
+	if err != nil {
+		return nil, err
+	}
+	packages := make([]pacmanPackage, 0, len(entries))
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		raw, err := os.ReadFile(filepath.Join(pacmanLocalDir(), entry.Name(), "desc"))
+		if err != nil {
+			continue
+		}
+		pkg := parsePacmanDesc(raw)
+		if pkg.Name == "" {
+			continue
+		}
+		if pkg.Reason == "1" {
+			pkg.Reason = "dependency"
+		} else {
+			pkg.Reason = "explicit"
+		}
+		packages = append(packages, pkg)
+	}
+	sort.Slice(packages, func(i, j int) bool { return packages[i].Name < packages[j].Name })
+	return packages, nil
+}
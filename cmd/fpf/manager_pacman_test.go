@@ -0,0 +1,200 @@
+package main
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func pacmanDescEntry(name, version, desc string) string {
+	return "%NAME%\n" + name + "\n\n%VERSION%\n" + version + "\n\n%DESC%\n" + desc + "\n\n"
+}
+
+// pacmanTar builds a sync database archive holding one desc file per entry.
+func pacmanTar(t *testing.T, descs map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	archive := tar.NewWriter(&buf)
+	for dir, desc := range descs {
+		if err := archive.WriteHeader(&tar.Header{Name: dir + "/", Typeflag: tar.TypeDir, Mode: 0o755}); err != nil {
+			t.Fatal(err)
+		}
+		if err := archive.WriteHeader(&tar.Header{Name: dir + "/desc", Typeflag: tar.TypeReg, Mode: 0o644, Size: int64(len(desc))}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := archive.Write([]byte(desc)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := archive.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func gzipBytes(t *testing.T, raw []byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	if _, err := gz.Write(raw); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func writePacmanRoot(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+	files := map[string][]byte{
+		"etc/pacman.conf": readFixture(t, "pacman.conf"),
+		"var/lib/pacman/sync/core.db": gzipBytes(t, pacmanTar(t, map[string]string{
+			"ripgrep-14.1.0-1": string(readFixture(t, "pacman-desc.txt")),
+			"pcre2-10.43-1":    pacmanDescEntry("pcre2", "10.43-1", "A library that implements Perl 5-style regular expressions"),
+		})),
+		"var/lib/pacman/sync/extra.db": pacmanTar(t, map[string]string{
+			"ripgrep-99.0.0-1":     pacmanDescEntry("ripgrep", "99.0.0-1", "Shadowed by core"),
+			"ripgrep-all-0.10.6-1": pacmanDescEntry("ripgrep-all", "0.10.6-1", "rga: ripgrep, but also search in PDFs, E-Books, Office documents"),
+		}),
+		"var/lib/pacman/local/ripgrep-14.1.0-1/desc": []byte(string(readFixture(t, "pacman-desc.txt")) + "%INSTALLDATE%\n1704600000\n\n"),
+		"var/lib/pacman/local/pcre2-10.43-1/desc":    []byte(pacmanDescEntry("pcre2", "10.43-1", "regex") + "%ARCH%\nx86_64\n\n%REASON%\n1\n\n"),
+	}
+	for rel, raw := range files {
+		path := filepath.Join(root, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, raw, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+func TestParsePacmanDesc(t *testing.T) {
+	pkg := parsePacmanDesc(readFixture(t, "pacman-desc.txt"))
+	want := pacmanPackage{
+		Name:    "ripgrep",
+		Version: "14.1.0-1",
+		Desc:    "A search tool that combines the usability of ag with the raw speed of grep",
+		Arch:    "x86_64",
+		Size:    4718592,
+	}
+	if pkg != want {
+		t.Fatalf("parsePacmanDesc=%+v want %+v", pkg, want)
+	}
+}
+
+func TestLoadPacmanCatalogRowsReadsSyncDBs(t *testing.T) {
+	t.Setenv("FPF_PACMAN_ROOT", writePacmanRoot(t))
+	t.Setenv("FPF_CACHE_DIR", t.TempDir())
+
+	rows, err := loadPacmanCatalogRows("ripgrep")
+	if err != nil {
+		t.Fatalf("loadPacmanCatalogRows: %v", err)
+	}
+	want := []searchRow{
+		{Name: "ripgrep", Desc: "[core] A search tool that combines the usability of ag with the raw speed of grep (14.1.0-1, 4.5 MB)"},
+		{Name: "ripgrep-all", Desc: "[extra] rga: ripgrep, but also search in PDFs, E-Books, Office documents (0.10.6-1)"},
+	}
+	if !reflect.DeepEqual(rows, want) {
+		t.Fatalf("rows=%v\nwant %v", rows, want)
+	}
+	if rows, _ := loadPacmanCatalogRows("core"); len(rows) != 0 {
+		t.Fatalf("repository tag should not match queries, got %v", rows)
+	}
+}
+
+func TestPacmanSearchSkipsPacmanForUnmatchedQueries(t *testing.T) {
+	t.Setenv("FPF_PACMAN_ROOT", writePacmanRoot(t))
+	t.Setenv("FPF_CACHE_DIR", t.TempDir())
+	bin := t.TempDir()
+	logFile := filepath.Join(t.TempDir(), "pacman.log")
+	writeMockExecutable(t, bin, "pacman", "#!/bin/sh\nprintf '%s\\n' \"$*\" >>\""+logFile+"\"\n")
+	t.Setenv("PATH", bin+":/usr/bin:/bin")
+
+	rows, err := (pacmanManager{}).Search(searchInput{Query: "no-such-package"})
+	if err != nil || len(rows) != 0 {
+		t.Fatalf("expected no rows, got %+v, %v", rows, err)
+	}
+	if raw, _ := os.ReadFile(logFile); len(raw) != 0 {
+		t.Fatalf("expected no pacman -Ss once the catalog loads, got %q", raw)
+	}
+
+	t.Setenv("FPF_PACMAN_ROOT", t.TempDir())
+	if _, err := (pacmanManager{}).Search(searchInput{Query: "ripgrep"}); err != nil {
+		t.Fatalf("search: %v", err)
+	}
+	if raw, _ := os.ReadFile(logFile); !strings.Contains(string(raw), "-Ss -- ripgrep") {
+		t.Fatalf("expected pacman -Ss without sync databases, got %q", raw)
+	}
+}
+
+func TestPacmanCatalogFingerprintTracksSyncDBs(t *testing.T) {
+	root := writePacmanRoot(t)
+	t.Setenv("FPF_PACMAN_ROOT", root)
+
+	before := pacmanCatalogFingerprint()
+	later := time.Now().Add(time.Hour)
+	if err := os.Chtimes(filepath.Join(root, "var", "lib", "pacman", "sync", "extra.db"), later, later); err != nil {
+		t.Fatal(err)
+	}
+	if after := pacmanCatalogFingerprint(); after == before {
+		t.Fatalf("expected fingerprint to change after pacman -Sy, still %q", after)
+	}
+}
+
+func TestOpenPacmanDBUsesZstdTool(t *testing.T) {
+	dir := t.TempDir()
+	raw := pacmanTar(t, map[string]string{"fd-10.1.0-1": pacmanDescEntry("fd", "10.1.0-1", "Simple, fast alternative to find")})
+	path := filepath.Join(dir, "zst.db")
+	if err := os.WriteFile(path, append([]byte{0x28, 0xb5, 0x2f, 0xfd}, raw...), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	bin := t.TempDir()
+	writeMockExecutable(t, bin, "zstd", "#!/bin/sh\nfor last; do :; done\nexec tail -c +5 \"$last\"\n")
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	packages, err := readPacmanSyncDB(path)
+	if err != nil {
+		t.Fatalf("readPacmanSyncDB: %v", err)
+	}
+	if len(packages) != 1 || packages[0].Name != "fd" {
+		t.Fatalf("unexpected packages %+v", packages)
+	}
+}
+
+func TestPacmanInstalledDetailsReadsLocalDB(t *testing.T) {
+	root := writePacmanRoot(t)
+	t.Setenv("FPF_PACMAN_ROOT", root)
+	t.Setenv("PATH", t.TempDir())
+
+	rows, err := pacmanManager{}.InstalledDetails()
+	if err != nil {
+		t.Fatalf("InstalledDetails: %v", err)
+	}
+	want := []searchRow{
+		{Name: "pcre2", Desc: "10.43-1 (x86_64, dependency)"},
+		{Name: "ripgrep", Desc: "14.1.0-1 (x86_64, explicit)"},
+	}
+	if !reflect.DeepEqual(rows, want) {
+		t.Fatalf("rows=%v want %v", rows, want)
+	}
+
+	before := installedFingerprint("pacman")
+	later := time.Now().Add(time.Hour)
+	if err := os.Chtimes(filepath.Join(root, "var", "lib", "pacman", "local"), later, later); err != nil {
+		t.Fatal(err)
+	}
+	if after := installedFingerprint("pacman"); after == before || !strings.Contains(after, "local=") {
+		t.Fatalf("expected installed fingerprint to follow the local db, got %q then %q", before, after)
+	}
+}
@@ -0,0 +1,301 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+)
+
+type pipxManager struct {
+	managerSpec
+}
+
+func init() {
+	registerManager(pipxManager{managerSpec{
+		name:         "pipx",
+		label:        "pipx",
+		order:        150,
+		binaries:     []string{"pipx", "uv"},
+		explicitOnly: true,
+	}})
+}
+
+// pipxDriver picks the tool that manages Python CLI environments: pipx when
+// installed, otherwise `uv tool`. FPF_PIPX_DRIVER=pipx|uv forces one.
+func pipxDriver() string {
+	candidates := []string{"pipx", "uv"}
+	if forced := strings.ToLower(strings.TrimSpace(os.Getenv("FPF_PIPX_DRIVER"))); forced != "" {
+		candidates = []string{forced}
+	}
+	for _, driver := range candidates {
+		if driver != "pipx" && driver != "uv" {
+			continue
+		}
+		if _, err := exec.LookPath(driver); err == nil {
+			return driver
+		}
+	}
+	return ""
+}
+
+func (pipxManager) Ready() bool {
+	return pipxDriver() != ""
+}
+
+// Search does an exact-name lookup against the PyPI JSON API; PyPI has no
+// search endpoint and neither pipx nor uv can search. Without a query the
+// installed tools are listed instead.
+func (m pipxManager) Search(input searchInput) ([]searchRow, error) {
+	name := normalizePythonPackageName(strings.Join(strings.Fields(input.Query), "-"))
+	if name == "" {
+		installed, err := m.ListInstalled()
+		if err != nil {
+			return nil, err
+		}
+		rows := make([]searchRow, 0, len(installed))
+		for _, pkg := range installed {
+			rows = append(rows, searchRow{Name: pkg, Desc: "installed"})
+		}
+		return rows, nil
+	}
+	out, found, err := fetchPypiProject(input.runOutput, name)
+	if err != nil || !found {
+		return nil, err
+	}
+	return parsePypiProject(out)
+}
+
+// fetchPypiProject reads a project's PyPI JSON through curl. found is false
+// when PyPI has no such project, which curl -f reports as exit status 22.
+func fetchPypiProject(runOutput func(string, ...string) ([]byte, error), name string) ([]byte, bool, error) {
+	if _, err := exec.LookPath("curl"); err != nil {
+		return nil, false, fmt.Errorf("curl is required to look up packages on PyPI")
+	}
+	out, err := runOutput("curl", "-fsSL", "--max-time", "5", "https://pypi.org/pypi/"+url.PathEscape(name)+"/json")
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 22 {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("PyPI lookup for %s: %w", name, err)
+	}
+	return out, true, nil
+}
+
+func (pipxManager) ListInstalled() ([]string, error) {
+	switch pipxDriver() {
+	case "pipx":
+		out, err := runOutputQuietErr("pipx", "list", "--json")
+		if err != nil {
+			return nil, err
+		}
+		return parsePipxListJSON(out)
+	case "uv":
+		out, err := runOutputQuietErr("uv", "tool", "list")
+		if err != nil {
+			return nil, err
+		}
+		return parseUvToolList(out), nil
+	default:
+		return nil, fmt.Errorf("neither pipx nor uv is installed")
+	}
+}
+
+func (pipxManager) Install(pkgs []string) error {
+	if pipxDriver() == "uv" {
+		for _, pkg := range pkgs {
+			if err := runCommand("uv", "tool", "install", pkg); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return runCommand("pipx", append([]string{"install"}, pkgs...)...)
+}
+
+func (pipxManager) Remove(pkgs []string) error {
+	driver := pipxDriver()
+	for _, pkg := range pkgs {
+		var err error
+		if driver == "uv" {
+			err = runCommand("uv", "tool", "uninstall", pkg)
+		} else {
+			err = runCommand("pipx", "uninstall", pkg)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (pipxManager) Update() error {
+	if pipxDriver() == "uv" {
+		return runCommand("uv", "tool", "upgrade", "--all")
+	}
+	return runCommand("pipx", "upgrade-all")
+}
+
+// Refresh is a no-op: packages are resolved against PyPI at install time.
+func (pipxManager) Refresh() error {
+	return nil
+}
+
+func (pipxManager) ShowInfo(pkg string) error {
+	if pipxDriver() == "pipx" {
+		if err := runCommandQuietErr("pipx", "runpip", pkg, "show", pkg); err == nil {
+			return nil
+		}
+	}
+	out, found, err := fetchPypiProject(runOutputQuietErr, pkg)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return fmt.Errorf("%s is not on PyPI", pkg)
+	}
+	info, err := formatPypiProjectInfo(out)
+	if err != nil {
+		return err
+	}
+	fmt.Print(info)
+	return nil
+}
+
+// normalizePythonPackageName applies the PEP 503 normalisation so search
+// rows and installed markers agree on names like Foo_Bar and foo-bar.
+func normalizePythonPackageName(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	var b strings.Builder
+	lastDash := false
+	for _, r := range name {
+		if r == '-' || r == '_' || r == '.' {
+			if !lastDash {
+				b.WriteByte('-')
+			}
+			lastDash = true
+			continue
+		}
+		b.WriteRune(r)
+		lastDash = false
+	}
+	return strings.Trim(b.String(), "-")
+}
+
+type pypiProjectInfo struct {
+	Name        string            `json:"name"`
+	Version     string            `json:"version"`
+	Summary     string            `json:"summary"`
+	HomePage    string            `json:"home_page"`
+	License     string            `json:"license"`
+	RequiresPy  string            `json:"requires_python"`
+	ProjectURLs map[string]string `json:"project_urls"`
+}
+
+// decodePypiProject reads the PyPI JSON API response for one project. An
+// empty info name means PyPI answered without a project.
+func decodePypiProject(out []byte) (pypiProjectInfo, error) {
+	var doc struct {
+		Info pypiProjectInfo `json:"info"`
+	}
+	if err := json.Unmarshal(bytes.TrimSpace(out), &doc); err != nil {
+		return pypiProjectInfo{}, fmt.Errorf("PyPI response: %w", err)
+	}
+	return doc.Info, nil
+}
+
+func parsePypiProject(out []byte) ([]searchRow, error) {
+	info, err := decodePypiProject(out)
+	if err != nil || info.Name == "" {
+		return nil, err
+	}
+	desc := strings.Join(strings.Fields(info.Summary), " ")
+	if desc == "" {
+		desc = "-"
+	}
+	return []searchRow{{Name: normalizePythonPackageName(info.Name), Desc: desc}}, nil
+}
+
+func formatPypiProjectInfo(out []byte) (string, error) {
+	info, err := decodePypiProject(out)
+	if err != nil {
+		return "", err
+	}
+	if info.Name == "" {
+		return "", fmt.Errorf("PyPI response has no project")
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "Name: %s\n", info.Name)
+	fmt.Fprintf(&b, "Version: %s\n", info.Version)
+	if info.Summary != "" {
+		fmt.Fprintf(&b, "Summary: %s\n", info.Summary)
+	}
+	if info.License != "" {
+		fmt.Fprintf(&b, "License: %s\n", info.License)
+	}
+	if info.RequiresPy != "" {
+		fmt.Fprintf(&b, "Requires-Python: %s\n", info.RequiresPy)
+	}
+	if info.HomePage != "" {
+		fmt.Fprintf(&b, "Home-page: %s\n", info.HomePage)
+	}
+	labels := make([]string, 0, len(info.ProjectURLs))
+	for label := range info.ProjectURLs {
+		labels = append(labels, label)
+	}
+	sort.Strings(labels)
+	for _, label := range labels {
+		fmt.Fprintf(&b, "Project-URL: %s, %s\n", label, info.ProjectURLs[label])
+	}
+	return b.String(), nil
+}
+
+func parsePipxListJSON(out []byte) ([]string, error) {
+	var doc struct {
+		Venvs map[string]struct {
+			Metadata struct {
+				MainPackage struct {
+					Package string `json:"package"`
+				} `json:"main_package"`
+			} `json:"metadata"`
+		} `json:"venvs"`
+	}
+	if err := json.Unmarshal(bytes.TrimSpace(out), &doc); err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(doc.Venvs))
+	for venv, entry := range doc.Venvs {
+		name := entry.Metadata.MainPackage.Package
+		if name == "" {
+			name = venv
+		}
+		names = append(names, normalizePythonPackageName(name))
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// parseUvToolList reads `uv tool list`, which prints "name vX.Y" headers
+// followed by "- entrypoint" lines.
+func parseUvToolList(out []byte) []string {
+	names := make([]string, 0)
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "-") || strings.HasPrefix(line, "warning:") || line == "No tools installed" {
+			continue
+		}
+		parts := strings.Fields(line)
+		if len(parts) > 0 {
+			names = append(names, normalizePythonPackageName(parts[0]))
+		}
+	}
+	return names
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParsePipxListJSON(t *testing.T) {
+	names, err := parsePipxListJSON(readFixture(t, "pipx-list.json"))
+	if err != nil {
+		t.Fatalf("parsePipxListJSON: %v", err)
+	}
+	if got := strings.Join(names, ","); got != "black,poetry,ruamel-yaml-cli" {
+		t.Fatalf("parsePipxListJSON=%q", got)
+	}
+	if got := strings.Join(parseUvToolList(readFixture(t, "uv-tool-list.txt")), ","); got != "black,ruff,pygments" {
+		t.Fatalf("parseUvToolList=%q", got)
+	}
+}
+
+func TestParsePypiProject(t *testing.T) {
+	rows, err := parsePypiProject(readFixture(t, "pypi-project.json"))
+	want := searchRow{Name: "httpie", Desc: "HTTPie: modern, user-friendly command-line HTTP client for the API era."}
+	if err != nil || len(rows) != 1 || rows[0] != want {
+		t.Fatalf("parsePypiProject=(%+v,%v) want %+v", rows, err, want)
+	}
+	info, err := formatPypiProjectInfo(readFixture(t, "pypi-project.json"))
+	if err != nil {
+		t.Fatalf("formatPypiProjectInfo: %v", err)
+	}
+	for _, line := range []string{"Version: 3.2.4", "License: MIT", "Project-URL: Documentation, https://httpie.io/docs"} {
+		if !strings.Contains(info, line) {
+			t.Fatalf("expected %q in info:\n%s", line, info)
+		}
+	}
+	if rows, err := parsePypiProject([]byte(`{"message":"Not Found"}`)); err != nil || len(rows) != 0 {
+		t.Fatalf("expected no rows for missing project, got %+v, %v", rows, err)
+	}
+	if _, err := parsePypiProject([]byte("<html>Service Unavailable</html>")); err == nil {
+		t.Fatal("expected an error for a non-JSON PyPI response")
+	}
+}
+
+func TestPipxSearchReportsLookupFailures(t *testing.T) {
+	dir := t.TempDir()
+	writeMockExecutable(t, dir, "pipx", "#!/bin/sh\nexit 0\n")
+	t.Setenv("PATH", dir)
+	t.Setenv("FPF_PIPX_DRIVER", "")
+
+	if _, err := executeSearchEntries(searchInput{Manager: "pipx", Query: "httpie"}); err == nil || !strings.Contains(err.Error(), "curl is required") {
+		t.Fatalf("expected a missing curl error, got %v", err)
+	}
+
+	writeMockExecutable(t, dir, "curl", "#!/bin/sh\nexit 22\n")
+	if rows, err := executeSearchEntries(searchInput{Manager: "pipx", Query: "no-such-tool"}); err != nil || len(rows) != 0 {
+		t.Fatalf("expected an unknown project to give no rows, got %+v, %v", rows, err)
+	}
+
+	writeMockExecutable(t, dir, "curl", "#!/bin/sh\nexit 6\n")
+	if _, err := executeSearchEntries(searchInput{Manager: "pipx", Query: "httpie"}); err == nil || !strings.Contains(err.Error(), "PyPI lookup for httpie") {
+		t.Fatalf("expected a network failure to surface, got %v", err)
+	}
+}
+
+func TestNormalizePythonPackageName(t *testing.T) {
+	tests := map[string]string{
+		"Black":           "black",
+		"ruamel.yaml.cli": "ruamel-yaml-cli",
+		"Foo__Bar":        "foo-bar",
+		"  httpie ":       "httpie",
+	}
+	for in, want := range tests {
+		if got := normalizePythonPackageName(in); got != want {
+			t.Fatalf("normalizePythonPackageName(%q)=%q want %q", in, got, want)
+		}
+	}
+}
+
+func TestPipxDriverFallsBackToUv(t *testing.T) {
+	dir := t.TempDir()
+	writeMockExecutable(t, dir, "uv", "#!/bin/sh\nprintf 'httpie v3.2.4\\n- http\\n'\n")
+	t.Setenv("PATH", dir)
+	t.Setenv("FPF_PIPX_DRIVER", "")
+
+	if got := pipxDriver(); got != "uv" {
+		t.Fatalf("pipxDriver()=%q want uv", got)
+	}
+	installed, err := executeInstalledEntries(installedInput{Manager: "pipx"})
+	if err != nil || strings.Join(installed, ",") != "httpie" {
+		t.Fatalf("installed=(%v,%v) want [httpie]", installed, err)
+	}
+
+	writeMockExecutable(t, dir, "pipx", "#!/usr/bin/env bash\nexit 0\n")
+	if got := pipxDriver(); got != "pipx" {
+		t.Fatalf("pipxDriver()=%q want pipx preferred", got)
+	}
+	t.Setenv("FPF_PIPX_DRIVER", "uv")
+	if got := pipxDriver(); got != "uv" {
+		t.Fatalf("pipxDriver()=%q want FPF_PIPX_DRIVER override", got)
+	}
+}
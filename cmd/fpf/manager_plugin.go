@@ -0,0 +1,397 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// External manager plugins are executables named fpf-manager-<name> found on
+// PATH. fpf runs `fpf-manager-<name> <command>` with a JSON request on stdin
+// and reads a single JSON response from stdout. Plugins may log to stderr.
+// For install, remove, update and refresh the response must be the last line
+// of stdout; earlier lines are passed through to the terminal, so installer
+// output does not break the protocol.
+//
+// Commands: describe, search, list-installed, install, remove, info, update,
+// refresh. A plugin that does not implement a command responds with
+// {"unsupported": true}. See README.md for the full protocol.
+const (
+	pluginManagerPrefix   = "fpf-manager-"
+	pluginProtocolVersion = 1
+	pluginManagerOrder    = 1000
+)
+
+type pluginRequest struct {
+	Protocol int      `json:"protocol"`
+	Command  string   `json:"command"`
+	Query    string   `json:"query,omitempty"`
+	Limit    int      `json:"limit,omitempty"`
+	Packages []string `json:"packages,omitempty"`
+}
+
+type pluginPackage struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version,omitempty"`
+}
+
+type pluginResponse struct {
+	Label       string          `json:"label,omitempty"`
+	Packages    []pluginPackage `json:"packages,omitempty"`
+	Info        string          `json:"info,omitempty"`
+	Error       string          `json:"error,omitempty"`
+	Unsupported bool            `json:"unsupported,omitempty"`
+}
+
+type pluginManager struct {
+	managerSpec
+	path string
+
+	labelOnce sync.Once
+	label     string
+}
+
+func newPluginManager(name, path string) *pluginManager {
+	return &pluginManager{
+		managerSpec: managerSpec{
+			name:     name,
+			order:    pluginManagerOrder,
+			binaries: []string{filepath.Base(path)},
+		},
+		path: path,
+	}
+}
+
+// Label asks the plugin to describe itself the first time a label is shown,
+// falling back to the plugin name.
+func (p *pluginManager) Label() string {
+	p.labelOnce.Do(func() {
+		p.label = p.name
+		resp, err := p.call(2*time.Second, pluginRequest{Command: "describe"}, false)
+		if err == nil && strings.TrimSpace(resp.Label) != "" {
+			p.label = strings.TrimSpace(resp.Label)
+		}
+	})
+	return p.label
+}
+
+func (p *pluginManager) Ready() bool {
+	info, err := os.Stat(p.path)
+	return err == nil && !info.IsDir()
+}
+
+func (p *pluginManager) Search(input searchInput) ([]searchRow, error) {
+	resp, err := p.call(input.CommandTimeout, pluginRequest{Command: "search", Query: input.Query, Limit: input.Limit}, false)
+	if err != nil {
+		return nil, err
+	}
+	rows := make([]searchRow, 0, len(resp.Packages))
+	for _, pkg := range resp.Packages {
+		name := strings.TrimSpace(pkg.Name)
+		if name == "" {
+			continue
+		}
+		desc := strings.TrimSpace(pkg.Description)
+		if desc == "" {
+			desc = "-"
+		}
+		rows = append(rows, searchRow{Name: name, Desc: desc})
+	}
+	return rows, nil
+}
+
+func (p *pluginManager) ListInstalled() ([]string, error) {
+	rows, err := p.InstalledDetails()
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(rows))
+	for _, row := range rows {
+		names = append(names, row.Name)
+	}
+	return names, nil
+}
+
+// InstalledDetails lists installed packages with the version the plugin
+// reports, falling back to their description.
+func (p *pluginManager) InstalledDetails() ([]searchRow, error) {
+	resp, err := p.call(0, pluginRequest{Command: "list-installed"}, false)
+	if err != nil {
+		return nil, err
+	}
+	rows := make([]searchRow, 0, len(resp.Packages))
+	for _, pkg := range resp.Packages {
+		name := strings.TrimSpace(pkg.Name)
+		if name == "" {
+			continue
+		}
+		desc := strings.TrimSpace(pkg.Version)
+		if desc == "" {
+			desc = strings.TrimSpace(pkg.Description)
+		}
+		if desc == "" {
+			desc = "installed"
+		}
+		rows = append(rows, searchRow{Name: name, Desc: desc})
+	}
+	return rows, nil
+}
+
+func (p *pluginManager) Install(pkgs []string) error {
+	_, err := p.call(0, pluginRequest{Command: "install", Packages: pkgs}, true)
+	return err
+}
+
+func (p *pluginManager) Remove(pkgs []string) error {
+	_, err := p.call(0, pluginRequest{Command: "remove", Packages: pkgs}, true)
+	return err
+}
+
+func (p *pluginManager) Update() error {
+	_, err := p.call(0, pluginRequest{Command: "update"}, true)
+	return err
+}
+
+func (p *pluginManager) Refresh() error {
+	_, err := p.call(0, pluginRequest{Command: "refresh"}, true)
+	if errors.Is(err, errPluginUnsupported) {
+		return nil
+	}
+	return err
+}
+
+func (p *pluginManager) ShowInfo(pkg string) error {
+	resp, err := p.call(0, pluginRequest{Command: "info", Packages: []string{pkg}}, false)
+	if err != nil {
+		return err
+	}
+	fmt.Print(resp.Info)
+	if resp.Info != "" && !strings.HasSuffix(resp.Info, "\n") {
+		fmt.Println()
+	}
+	return nil
+}
+
+var errPluginUnsupported = errors.New("command not supported by plugin")
+
+// call runs one plugin command. interactive commands show the plugin's
+// stderr and any stdout before the final response line.
+func (p *pluginManager) call(timeout time.Duration, req pluginRequest, interactive bool) (pluginResponse, error) {
+	req.Protocol = pluginProtocolVersion
+	payload, err := json.Marshal(req)
+	if err != nil {
+		return pluginResponse{}, err
+	}
+
+	ctx := context.Background()
+	cancel := func() {}
+	if timeout > 0 {
+		ctx, cancel = context.WithTimeout(context.Background(), timeout)
+	}
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, p.path, req.Command)
+	cmd.Env = append(os.Environ(), fmt.Sprintf("FPF_PLUGIN_PROTOCOL=%d", pluginProtocolVersion))
+	cmd.Stdin = bytes.NewReader(payload)
+	var stdout bytes.Buffer
+	relay := &lastLineWriter{w: os.Stdout}
+	if interactive {
+		cmd.Stdout = relay
+		cmd.Stderr = os.Stderr
+	} else {
+		cmd.Stdout = &stdout
+		cmd.Stderr = io.Discard
+	}
+
+	runErr := cmd.Run()
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return pluginResponse{}, context.DeadlineExceeded
+	}
+
+	resp := pluginResponse{}
+	raw := bytes.TrimSpace(stdout.Bytes())
+	if interactive {
+		raw = bytes.TrimSpace(relay.last())
+	}
+	if len(raw) > 0 {
+		if err := json.Unmarshal(raw, &resp); err != nil {
+			return pluginResponse{}, fmt.Errorf("plugin %s returned invalid JSON for %s: %w", p.name, req.Command, err)
+		}
+	}
+	if resp.Unsupported {
+		return resp, fmt.Errorf("plugin %s %s: %w", p.name, req.Command, errPluginUnsupported)
+	}
+	if resp.Error != "" {
+		return resp, fmt.Errorf("plugin %s %s: %s", p.name, req.Command, resp.Error)
+	}
+	if runErr != nil {
+		return resp, fmt.Errorf("plugin %s %s: %w", p.name, req.Command, runErr)
+	}
+	return resp, nil
+}
+
+// lastLineWriter passes a plugin's stdout through to w a line at a time,
+// holding back the most recent non-blank line, which is the response frame.
+type lastLineWriter struct {
+	w       io.Writer
+	held    []byte
+	partial []byte
+}
+
+func (l *lastLineWriter) Write(p []byte) (int, error) {
+	l.partial = append(l.partial, p...)
+	for {
+		i := bytes.IndexByte(l.partial, '\n')
+		if i < 0 {
+			return len(p), nil
+		}
+		line := append([]byte(nil), l.partial[:i+1]...)
+		l.partial = l.partial[i+1:]
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		if err := l.flush(); err != nil {
+			return len(p), err
+		}
+		l.held = line
+	}
+}
+
+func (l *lastLineWriter) flush() error {
+	if len(l.held) == 0 {
+		return nil
+	}
+	_, err := l.w.Write(l.held)
+	l.held = nil
+	return err
+}
+
+// last returns the response frame: an unterminated final line, else the
+// last complete one.
+func (l *lastLineWriter) last() []byte {
+	if len(bytes.TrimSpace(l.partial)) > 0 {
+		_ = l.flush()
+		l.held, l.partial = l.partial, nil
+	}
+	return l.held
+}
+
+var (
+	pluginDiscoveryOnce sync.Once
+	pluginManagers      []*pluginManager
+)
+
+func managerPluginsEnabled() bool {
+	v := strings.ToLower(strings.TrimSpace(os.Getenv("FPF_DISABLE_MANAGER_PLUGINS")))
+	return !(v == "1" || v == "true" || v == "yes" || v == "on")
+}
+
+// discoverPluginManagers returns the fpf-manager-<name> executables on PATH.
+// PATH is scanned once per process; every registry lookup after that reuses
+// the result.
+func discoverPluginManagers() []*pluginManager {
+	if !managerPluginsEnabled() {
+		return nil
+	}
+	pluginDiscoveryOnce.Do(func() {
+		pluginManagers = scanPluginManagers(os.Getenv("PATH"))
+	})
+	return pluginManagers
+}
+
+// scanPluginManagers lists plugin executables in PATH order. The first
+// executable for a name wins, and built-in manager names always win.
+func scanPluginManagers(pathEnv string) []*pluginManager {
+	seen := map[string]struct{}{}
+	found := make([]*pluginManager, 0)
+	for _, dir := range filepath.SplitList(pathEnv) {
+		if dir == "" {
+			continue
+		}
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			name, ok := pluginManagerName(entry.Name())
+			if !ok {
+				continue
+			}
+			if _, dup := seen[name]; dup {
+				continue
+			}
+			if _, builtin := lookupBuiltinManager(name); builtin {
+				continue
+			}
+			path := filepath.Join(dir, entry.Name())
+			if !isExecutableFile(path) {
+				continue
+			}
+			seen[name] = struct{}{}
+			found = append(found, newPluginManager(name, path))
+		}
+	}
+	sort.Slice(found, func(i, j int) bool {
+		return found[i].name < found[j].name
+	})
+	return found
+}
+
+func lookupPluginManager(name string) (Manager, bool) {
+	for _, plugin := range discoverPluginManagers() {
+		if plugin.name == name {
+			return plugin, true
+		}
+	}
+	return nil, false
+}
+
+func pluginManagerName(fileName string) (string, bool) {
+	if !strings.HasPrefix(fileName, pluginManagerPrefix) {
+		return "", false
+	}
+	name := strings.TrimPrefix(fileName, pluginManagerPrefix)
+	if runtime.GOOS == "windows" {
+		ext := strings.ToLower(filepath.Ext(name))
+		switch ext {
+		case ".exe", ".cmd", ".bat":
+			name = strings.TrimSuffix(name, filepath.Ext(name))
+		default:
+			return "", false
+		}
+	}
+	name = strings.ToLower(name)
+	if name == "" {
+		return "", false
+	}
+	for _, r := range name {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '-' || r == '_' {
+			continue
+		}
+		return "", false
+	}
+	return name, true
+}
+
+func isExecutableFile(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil || info.IsDir() {
+		return false
+	}
+	if runtime.GOOS == "windows" {
+		return true
+	}
+	return info.Mode()&0o111 != 0
+}
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// resetPluginDiscovery lets a test rescan PATH after changing it, and
+// restores a fresh scan for the tests that follow.
+func resetPluginDiscovery(t *testing.T) {
+	t.Helper()
+	pluginDiscoveryOnce = sync.Once{}
+	pluginManagers = nil
+	t.Cleanup(func() {
+		pluginDiscoveryOnce = sync.Once{}
+		pluginManagers = nil
+	})
+}
+
+const mockArtifactsPlugin = `#!/usr/bin/env bash
+set -euo pipefail
+request="$(cat)"
+case "${1:-}" in
+    describe)
+        printf '{"label":"Artifact Store"}\n'
+        ;;
+    search)
+        printf '{"packages":[{"name":"artifact-cli","description":"Internal artifact client"},{"name":"artifact-sync"}]}\n'
+        ;;
+    list-installed)
+        printf '{"packages":[{"name":"artifact-cli","version":"1.2.0"}]}\n'
+        ;;
+    info)
+        printf '{"info":"request=%s"}\n' "$(printf '%s' "${request}" | tr -d '"')"
+        ;;
+    install)
+        printf '{"error":"artifact store offline"}\n'
+        exit 1
+        ;;
+    remove)
+        printf 'Removing artifact-cli\n{"note":"not the response"}\n\n'
+        printf '{}\n'
+        ;;
+    *)
+        printf '{"unsupported":true}\n'
+        ;;
+esac
+`
+
+func TestDiscoverPluginManagers(t *testing.T) {
+	dir := t.TempDir()
+	writeMockExecutable(t, dir, "fpf-manager-artifacts", mockArtifactsPlugin)
+	writeMockExecutable(t, dir, "fpf-manager-apt", mockArtifactsPlugin)
+	writeMockExecutable(t, dir, "fpf-manager-Bad.Name", mockArtifactsPlugin)
+	if err := os.WriteFile(filepath.Join(dir, "fpf-manager-noexec"), []byte("#!/bin/sh\n"), 0o644); err != nil {
+		t.Fatalf("write non-executable plugin: %v", err)
+	}
+	t.Setenv("PATH", dir+":/usr/bin:/bin")
+	resetPluginDiscovery(t)
+
+	plugins := discoverPluginManagers()
+	if len(plugins) != 1 || plugins[0].Name() != "artifacts" {
+		names := make([]string, 0, len(plugins))
+		for _, p := range plugins {
+			names = append(names, p.Name())
+		}
+		t.Fatalf("discoverPluginManagers=%v want [artifacts]", names)
+	}
+
+	if !isManagerSupported("artifacts") || !isManagerCommandReady("artifacts") {
+		t.Fatal("expected plugin manager to be supported and ready")
+	}
+	if got := managerLabelGo("artifacts"); got != "Artifact Store" {
+		t.Fatalf("plugin label=%q want Artifact Store", got)
+	}
+	if needsRoot("fpf-manager-artifacts") {
+		t.Fatal("plugins must not be run through sudo")
+	}
+	names := registeredManagerNames()
+	if names[len(names)-1] != "artifacts" {
+		t.Fatalf("expected plugin after built-in managers, got %v", names)
+	}
+
+	writeMockExecutable(t, dir, "fpf-manager-late", mockArtifactsPlugin)
+	if isManagerSupported("late") {
+		t.Fatal("expected PATH to be scanned once per process")
+	}
+
+	t.Setenv("FPF_DISABLE_MANAGER_PLUGINS", "1")
+	if isManagerSupported("artifacts") {
+		t.Fatal("expected FPF_DISABLE_MANAGER_PLUGINS to hide plugins")
+	}
+}
+
+func TestPluginManagerProtocol(t *testing.T) {
+	dir := t.TempDir()
+	writeMockExecutable(t, dir, "fpf-manager-artifacts", mockArtifactsPlugin)
+	t.Setenv("PATH", dir+":/usr/bin:/bin")
+	resetPluginDiscovery(t)
+
+	rows, err := executeSearchEntries(searchInput{Manager: "artifacts", Query: "artifact"})
+	if err != nil {
+		t.Fatalf("plugin search: %v", err)
+	}
+	if len(rows) != 2 || rows[0].Name != "artifact-cli" || rows[0].Desc != "Internal artifact client" || rows[1].Desc != "-" {
+		t.Fatalf("unexpected plugin search rows: %+v", rows)
+	}
+
+	installed, err := executeInstalledEntries(installedInput{Manager: "artifacts"})
+	if err != nil {
+		t.Fatalf("plugin list-installed: %v", err)
+	}
+	if strings.Join(installed, ",") != "artifact-cli" {
+		t.Fatalf("plugin installed=%v want [artifact-cli]", installed)
+	}
+
+	err = executeManagerAction(managerActionInput{Action: "install", Manager: "artifacts", Packages: []string{"artifact-cli"}})
+	if err == nil || !strings.Contains(err.Error(), "artifact store offline") {
+		t.Fatalf("expected plugin error to surface, got %v", err)
+	}
+	if err := executeManagerAction(managerActionInput{Action: "remove", Manager: "artifacts", Packages: []string{"artifact-cli"}}); err != nil {
+		t.Fatalf("plugin output before the response line should pass through, got %v", err)
+	}
+	details, err := executeInstalledRows(installedInput{Manager: "artifacts"})
+	if err != nil || len(details) != 1 || details[0].Desc != "1.2.0" {
+		t.Fatalf("expected installed rows to carry the plugin version, got %+v err=%v", details, err)
+	}
+	if err := executeManagerAction(managerActionInput{Action: "refresh", Manager: "artifacts"}); err != nil {
+		t.Fatalf("unsupported refresh should be a no-op, got %v", err)
+	}
+	if err := executeManagerAction(managerActionInput{Action: "update", Manager: "artifacts"}); err == nil {
+		t.Fatal("expected unsupported update to report an error")
+	}
+}
+
+func TestLastLineWriterHoldsBackResponse(t *testing.T) {
+	var passed strings.Builder
+	relay := &lastLineWriter{w: &passed}
+	for _, chunk := range []string{"Resolving art", "ifact-cli\n", "\n", "Done\n{\"err", "or\":\"boom\"}"} {
+		if _, err := relay.Write([]byte(chunk)); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+	if got := string(relay.last()); got != `{"error":"boom"}` {
+		t.Fatalf("last()=%q", got)
+	}
+	if got := passed.String(); got != "Resolving artifact-cli\nDone\n" {
+		t.Fatalf("passed through %q", got)
+	}
+}
+
+func TestPluginManagerName(t *testing.T) {
+	tests := []struct {
+		in     string
+		want   string
+		wantOK bool
+	}{
+		{in: "fpf-manager-artifacts", want: "artifacts", wantOK: true},
+		{in: "fpf-manager-tools_repo", want: "tools_repo", wantOK: true},
+		{in: "fpf-manager-", wantOK: false},
+		{in: "fpf-manager-a.b", wantOK: false},
+		{in: "artifacts", wantOK: false},
+	}
+	for _, tc := range tests {
+		got, ok := pluginManagerName(tc.in)
+		if ok != tc.wantOK || got != tc.want {
+			t.Fatalf("pluginManagerName(%q)=(%q,%v) want (%q,%v)", tc.in, got, ok, tc.want, tc.wantOK)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+)
+
+type pnpmManager struct {
+	managerSpec
+}
+
+func init() {
+	registerManager(pnpmManager{managerSpec{
+		name:     "pnpm",
+		label:    "pnpm",
+		order:    132,
+		binaries: []string{"pnpm"},
+	}})
+}
+
+// Search reuses npm's registry search; pnpm has no search command of its own.
+func (pnpmManager) Search(input searchInput) ([]searchRow, error) {
+	return jsRegistrySearch(input)
+}
+
+func (pnpmManager) ListInstalled() ([]string, error) {
+	out, err := runOutputQuietErr("pnpm", "ls", "-g", "--depth=0", "--json")
+	if err != nil {
+		return nil, err
+	}
+	return parsePnpmInstalled(out)
+}
+
+func (pnpmManager) Install(pkgs []string) error {
+	return runCommand("pnpm", append([]string{"add", "-g"}, pkgs...)...)
+}
+
+func (pnpmManager) Remove(pkgs []string) error {
+	return runCommand("pnpm", append([]string{"remove", "-g"}, pkgs...)...)
+}
+
+func (pnpmManager) Update() error {
+	return runCommand("pnpm", "update", "-g")
+}
+
+// Refresh is a no-op: pnpm resolves packages against the registry at install
+// time and keeps no catalog fpf needs to update.
+func (pnpmManager) Refresh() error {
+	return nil
+}
+
+func (pnpmManager) ShowInfo(pkg string) error {
+	return runCommandQuietErr("npm", "view", pkg)
+}
+
+// parsePnpmInstalled reads `pnpm ls -g --json`, an array with one entry per
+// global project whose dependencies are the globally installed packages.
+func parsePnpmInstalled(out []byte) ([]string, error) {
+	var projects []struct {
+		Dependencies map[string]json.RawMessage `json:"dependencies"`
+	}
+	if err := json.Unmarshal(bytes.TrimSpace(out), &projects); err != nil {
+		return nil, err
+	}
+	names := make([]string, 0)
+	for _, project := range projects {
+		for name := range project.Dependencies {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParsePnpmInstalled(t *testing.T) {
+	names, err := parsePnpmInstalled(readFixture(t, "pnpm-ls-global.json"))
+	if err != nil {
+		t.Fatalf("parsePnpmInstalled: %v", err)
+	}
+	if got := strings.Join(names, ","); got != "@biomejs/biome,prettier,typescript" {
+		t.Fatalf("parsePnpmInstalled=%q", got)
+	}
+	if names, err := parsePnpmInstalled([]byte("[]")); err != nil || len(names) != 0 {
+		t.Fatalf("expected no packages for empty global project, got %v (%v)", names, err)
+	}
+}
+
+func TestAllowNpmRegistryFallbackCoversJSFamily(t *testing.T) {
+	for _, manager := range []string{"pnpm", "yarn"} {
+		if allowNpmRegistryFallback(manager, 3, true) {
+			t.Fatalf("expected %s registry search disabled when npm is in the multi-manager set", manager)
+		}
+		if !allowNpmRegistryFallback(manager, 1, true) {
+			t.Fatalf("expected single-manager %s to search the npm registry", manager)
+		}
+	}
+	if !allowNpmRegistryFallback("npm", 3, true) {
+		t.Fatal("expected npm to keep its own registry search")
+	}
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"fmt"
+	"os/exec"
+	"sort"
+	"sync"
+)
+
+// Manager is a package manager backend. Every CLI path (search, list,
+// remove, update, refresh, preview and dynamic reload) resolves managers
+// through the registry, so a backend only has to live in its own
+// manager_<name>.go file and register itself from init.
+type Manager interface {
+	Name() string
+	Label() string
+	Ready() bool
+	NeedsRoot() bool
+	Search(input searchInput) ([]searchRow, error)
+	ListInstalled() ([]string, error)
+	Install(pkgs []string) error
+	Remove(pkgs []string) error
+	Update() error
+	Refresh() error
+	ShowInfo(pkg string) error
+}
+
+// fzfInstaller is implemented by managers that can bootstrap fzf.
+type fzfInstaller interface {
+	InstallFzf() error
+}
+
+// installedDetailer is implemented by managers that can describe their
+// installed packages (version, architecture, install reason) beyond the
+// bare names ListInstalled returns.
+type installedDetailer interface {
+	InstalledDetails() ([]searchRow, error)
+}
+
+// managerSpec carries the static description shared by every backend and
+// provides the default Name/Label/Ready/NeedsRoot implementations.
+// explicitOnly backends are skipped by auto-detection and only run when
+// selected with --manager.
+type managerSpec struct {
+	name         string
+	label        string
+	order        int
+	binaries     []string
+	root         bool
+	explicitOnly bool
+}
+
+func (s managerSpec) Name() string {
+	return s.name
+}
+
+func (s managerSpec) Label() string {
+	if s.label == "" {
+		return s.name
+	}
+	return s.label
+}
+
+func (s managerSpec) Ready() bool {
+	if len(s.binaries) == 0 {
+		return false
+	}
+	for _, binary := range s.binaries {
+		if _, err := exec.LookPath(binary); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+func (s managerSpec) NeedsRoot() bool {
+	return s.root
+}
+
+func (s managerSpec) spec() managerSpec {
+	return s
+}
+
+type specProvider interface {
+	spec() managerSpec
+}
+
+var (
+	managerRegistryMu sync.RWMutex
+	managerRegistry   = map[string]Manager{}
+)
+
+func registerManager(m Manager) {
+	managerRegistryMu.Lock()
+	defer managerRegistryMu.Unlock()
+
+	name := m.Name()
+	if _, exists := managerRegistry[name]; exists {
+		panic(fmt.Sprintf("fpf: manager %q registered twice", name))
+	}
+	managerRegistry[name] = m
+}
+
+func lookupManager(name string) (Manager, bool) {
+	if m, ok := lookupBuiltinManager(name); ok {
+		return m, true
+	}
+	return lookupPluginManager(name)
+}
+
+func lookupBuiltinManager(name string) (Manager, bool) {
+	managerRegistryMu.RLock()
+	defer managerRegistryMu.RUnlock()
+
+	m, ok := managerRegistry[name]
+	return m, ok
+}
+
+// registeredManagers returns every backend, built-in and plugin, in
+// detection order.
+func registeredManagers() []Manager {
+	managerRegistryMu.RLock()
+	out := make([]Manager, 0, len(managerRegistry))
+	for _, m := range managerRegistry {
+		out = append(out, m)
+	}
+	managerRegistryMu.RUnlock()
+	for _, plugin := range discoverPluginManagers() {
+		out = append(out, plugin)
+	}
+
+	sort.SliceStable(out, func(i, j int) bool {
+		oi, oj := managerOrder(out[i]), managerOrder(out[j])
+		if oi != oj {
+			return oi < oj
+		}
+		return out[i].Name() < out[j].Name()
+	})
+	return out
+}
+
+func registeredManagerNames() []string {
+	managers := registeredManagers()
+	names := make([]string, 0, len(managers))
+	for _, m := range managers {
+		names = append(names, m.Name())
+	}
+	return names
+}
+
+func managerOrder(m Manager) int {
+	if p, ok := m.(specProvider); ok {
+		return p.spec().order
+	}
+	return 1 << 20
+}
+
+func managerExplicitOnly(m Manager) bool {
+	if p, ok := m.(specProvider); ok {
+		return p.spec().explicitOnly
+	}
+	return false
+}
+
+func managerBinaries(m Manager) []string {
+	if p, ok := m.(specProvider); ok {
+		return p.spec().binaries
+	}
+	return nil
+}
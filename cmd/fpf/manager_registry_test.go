@@ -0,0 +1,66 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRegisteredManagersDetectionOrder(t *testing.T) {
+	got := strings.Join(registeredManagerNames(), ",")
+	want := "apt,dnf,pacman,aur,zypper,emerge,apk,xbps,brew,brew-tap,winget,choco,scoop,snap,flatpak,nix,bun,npm,pnpm,yarn,cargo,gem,pipx,go,mise"
+	if got != want {
+		t.Fatalf("registeredManagerNames=%q want=%q", got, want)
+	}
+}
+
+func TestLookupManagerLabelsAndSupport(t *testing.T) {
+	if !isManagerSupported("emerge") {
+		t.Fatal("expected emerge to be registered")
+	}
+	if isManagerSupported("unknown") {
+		t.Fatal("expected unknown manager to be unsupported")
+	}
+	if got := managerLabelGo("emerge"); got != "Portage (emerge)" {
+		t.Fatalf("managerLabelGo(emerge)=%q", got)
+	}
+	if got := managerLabelGo("unknown"); got != "unknown" {
+		t.Fatalf("managerLabelGo(unknown)=%q want passthrough", got)
+	}
+}
+
+func TestNeedsRootUsesRegisteredBinaries(t *testing.T) {
+	for _, binary := range []string{"apt-get", "dnf", "pacman", "zypper", "emerge", "apk", "xbps-install", "xbps-remove", "snap"} {
+		if !needsRoot(binary) {
+			t.Fatalf("expected %s to need root", binary)
+		}
+	}
+	for _, binary := range []string{"brew", "flatpak", "nix", "paru", "yay", "cargo", "npm", "pnpm", "yarn", "bun", "winget"} {
+		if needsRoot(binary) {
+			t.Fatalf("expected %s to run without root", binary)
+		}
+	}
+}
+
+func TestManagerCanInstallFzf(t *testing.T) {
+	for _, manager := range []string{"apt", "brew", "winget", "snap", "go"} {
+		if !managerCanInstallFzfGo(manager) {
+			t.Fatalf("expected %s to bootstrap fzf", manager)
+		}
+	}
+	for _, manager := range []string{"flatpak", "npm", "bun", "unknown"} {
+		if managerCanInstallFzfGo(manager) {
+			t.Fatalf("expected %s not to bootstrap fzf", manager)
+		}
+	}
+}
+
+func TestExecuteManagerActionRejectsUnknown(t *testing.T) {
+	err := executeManagerAction(managerActionInput{Action: "install", Manager: "unknown"})
+	if err == nil || !strings.Contains(err.Error(), "unsupported manager action") {
+		t.Fatalf("expected unsupported manager action error, got %v", err)
+	}
+	err = executeManagerAction(managerActionInput{Action: "explode", Manager: "apt"})
+	if err == nil || !strings.Contains(err.Error(), "action=explode") {
+		t.Fatalf("expected unsupported action error, got %v", err)
+	}
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+)
+
+type scoopManager struct {
+	managerSpec
+}
+
+func init() {
+	registerManager(scoopManager{managerSpec{
+		name:     "scoop",
+		label:    "Scoop",
+		order:    90,
+		binaries: []string{"scoop"},
+	}})
+}
+
+func (scoopManager) Search(input searchInput) ([]searchRow, error) {
+	out, err := input.runOutput("scoop", "search", input.Query)
+	if err != nil {
+		return nil, err
+	}
+	return parseScoopSearch(out), nil
+}
+
+func (scoopManager) ListInstalled() ([]string, error) {
+	out, err := runOutputQuietErr("scoop", "list")
+	if err != nil {
+		return nil, err
+	}
+	return parseScoopInstalled(out), nil
+}
+
+func (scoopManager) Install(pkgs []string) error {
+	return runCommand("scoop", append([]string{"install"}, pkgs...)...)
+}
+
+func (scoopManager) Remove(pkgs []string) error {
+	return runCommand("scoop", append([]string{"uninstall"}, pkgs...)...)
+}
+
+func (scoopManager) Update() error {
+	if err := runCommand("scoop", "update"); err != nil {
+		return err
+	}
+	return runCommand("scoop", "update", "*")
+}
+
+func (scoopManager) Refresh() error {
+	return runCommand("scoop", "update")
+}
+
+func (scoopManager) ShowInfo(pkg string) error {
+	return runCommandQuietErr("scoop", "info", pkg)
+}
+
+func (scoopManager) InstallFzf() error {
+	return runCommand("scoop", "install", "fzf")
+}
+
+func parseScoopSearch(out []byte) []searchRow {
+	rows := make([]searchRow, 0)
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "Name") || strings.HasPrefix(line, "-") {
+			continue
+		}
+		parts := strings.Fields(line)
+		if len(parts) == 0 {
+			continue
+		}
+		name := parts[0]
+		desc := "-"
+		if len(parts) > 1 {
+			desc = strings.Join(parts[1:], " ")
+		}
+		rows = append(rows, searchRow{Name: name, Desc: desc})
+	}
+	return rows
+}
+
+func parseScoopInstalled(out []byte) []string {
+	names := make([]string, 0)
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	inPackages := false
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "Name") || strings.HasPrefix(line, "---") {
+			inPackages = true
+			continue
+		}
+		if !inPackages {
+			continue
+		}
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		parts := strings.Fields(line)
+		if len(parts) > 0 {
+			names = append(names, parts[0])
+		}
+	}
+	return names
+}
@@ -0,0 +1,346 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+type snapManager struct {
+	managerSpec
+}
+
+func init() {
+	registerManager(snapManager{managerSpec{
+		name:     "snap",
+		label:    "Snap",
+		order:    100,
+		binaries: []string{"snap"},
+		root:     true,
+	}})
+}
+
+// Search asks snapd for matching snaps, falling back to `snap find` only
+// when its socket is missing or refuses connections. Other errors, a slow
+// snapd's timeout included, are returned, except snapd's "no matching
+// snaps" 404, which is an empty result.
+func (snapManager) Search(input searchInput) ([]searchRow, error) {
+	snaps, err := snapdFind(input.Query, input.CommandTimeout)
+	var apiErr *snapdError
+	switch {
+	case err == nil:
+		return snapdSearchRows(snaps), nil
+	case errors.As(err, &apiErr) && apiErr.Status == http.StatusNotFound:
+		return nil, nil
+	case !snapdUnreachable(err):
+		return nil, err
+	}
+	out, err := input.runOutput("snap", "find", input.Query)
+	if err != nil {
+		return nil, err
+	}
+	return parseSnapSearch(out), nil
+}
+
+func (snapManager) ListInstalled() ([]string, error) {
+	if snaps, err := snapdInstalled(); err == nil {
+		names := make([]string, 0, len(snaps))
+		for _, snap := range snaps {
+			names = append(names, snap.Name)
+		}
+		return names, nil
+	}
+	out, err := runOutputQuietErr("snap", "list")
+	if err != nil {
+		return nil, err
+	}
+	return parseSnapInstalled(out), nil
+}
+
+// InstalledDetails describes each installed snap as
+// "version (channel, confinement, publisher)".
+func (m snapManager) InstalledDetails() ([]searchRow, error) {
+	snaps, err := snapdInstalled()
+	if err != nil {
+		names, listErr := m.ListInstalled()
+		if listErr != nil {
+			return nil, listErr
+		}
+		rows := make([]searchRow, 0, len(names))
+		for _, name := range names {
+			rows = append(rows, searchRow{Name: name, Desc: "installed"})
+		}
+		return rows, nil
+	}
+	rows := make([]searchRow, 0, len(snaps))
+	for _, snap := range snaps {
+		rows = append(rows, searchRow{Name: snap.Name, Desc: snap.describeInstalled()})
+	}
+	return rows, nil
+}
+
+func (snapManager) Install(pkgs []string) error {
+	for _, pkg := range pkgs {
+		if err := runRootCommandQuietErr("snap", "install", pkg); err != nil {
+			if err2 := runRootCommand("snap", "install", "--classic", pkg); err2 != nil {
+				return err2
+			}
+		}
+	}
+	return nil
+}
+
+func (snapManager) Remove(pkgs []string) error {
+	return runRootCommand("snap", append([]string{"remove"}, pkgs...)...)
+}
+
+func (snapManager) Update() error {
+	return runRootCommand("snap", "refresh")
+}
+
+func (snapManager) Refresh() error {
+	return runRootCommand("snap", "refresh", "--list")
+}
+
+func (snapManager) ShowInfo(pkg string) error {
+	return runCommandQuietErr("snap", "info", pkg)
+}
+
+func (snapManager) InstallFzf() error {
+	return runRootCommand("snap", "install", "fzf")
+}
+
+func parseSnapSearch(out []byte) []searchRow {
+	rows := make([]searchRow, 0)
+	lines := splitLines(out)
+	for i, line := range lines {
+		trim := strings.TrimSpace(line)
+		if i == 0 || trim == "" {
+			continue
+		}
+		parts := strings.Fields(trim)
+		if len(parts) == 0 {
+			continue
+		}
+		name := parts[0]
+		desc := "-"
+		if len(parts) > 1 {
+			desc = strings.Join(parts[1:], " ")
+		}
+		rows = append(rows, searchRow{Name: name, Desc: desc})
+	}
+	return rows
+}
+
+func parseSnapInstalled(out []byte) []string {
+	names := make([]string, 0)
+	lines := splitLines(out)
+	for i, line := range lines {
+		if i == 0 || strings.TrimSpace(line) == "" {
+			continue
+		}
+		parts := strings.Fields(line)
+		if len(parts) > 0 {
+			names = append(names, parts[0])
+		}
+	}
+	return names
+}
+
+// snapdSocketPath is snapd's REST API socket; FPF_SNAPD_SOCKET overrides it.
+func snapdSocketPath() string {
+	if socket := strings.TrimSpace(os.Getenv("FPF_SNAPD_SOCKET")); socket != "" {
+		return socket
+	}
+	return "/run/snapd.socket"
+}
+
+// snapdSnap is the subset of snapd's snap JSON that rows show.
+type snapdSnap struct {
+	Name            string `json:"name"`
+	Summary         string `json:"summary"`
+	Version         string `json:"version"`
+	Channel         string `json:"channel"`
+	TrackingChannel string `json:"tracking-channel"`
+	Confinement     string `json:"confinement"`
+	Publisher       struct {
+		Username   string `json:"username"`
+		Validation string `json:"validation"`
+	} `json:"publisher"`
+}
+
+// publisher renders the publisher the way `snap find` does, with a check
+// mark for verified accounts.
+func (s snapdSnap) publisher() string {
+	name := s.Publisher.Username
+	if name != "" && (s.Publisher.Validation == "verified" || s.Publisher.Validation == "starred") {
+		name += "✓"
+	}
+	return name
+}
+
+func (s snapdSnap) channel() string {
+	if s.TrackingChannel != "" {
+		return s.TrackingChannel
+	}
+	return s.Channel
+}
+
+// describe renders "[publisher] summary (version, channel, confinement)".
+func (s snapdSnap) describe() string {
+	parts := make([]string, 0, 3)
+	if publisher := s.publisher(); publisher != "" {
+		parts = append(parts, "["+publisher+"]")
+	}
+	if summary := strings.Join(strings.Fields(s.Summary), " "); summary != "" {
+		parts = append(parts, summary)
+	}
+	if extra := joinNonEmpty(s.Version, s.channel(), s.Confinement); extra != "" {
+		parts = append(parts, "("+extra+")")
+	}
+	if len(parts) == 0 {
+		return "-"
+	}
+	return strings.Join(parts, " ")
+}
+
+func (s snapdSnap) describeInstalled() string {
+	version := s.Version
+	if version == "" {
+		version = "installed"
+	}
+	if extra := joinNonEmpty(s.channel(), s.Confinement, s.publisher()); extra != "" {
+		return version + " (" + extra + ")"
+	}
+	return version
+}
+
+func joinNonEmpty(fields ...string) string {
+	kept := make([]string, 0, len(fields))
+	for _, field := range fields {
+		if field != "" {
+			kept = append(kept, field)
+		}
+	}
+	return strings.Join(kept, ", ")
+}
+
+func snapdSearchRows(snaps []snapdSnap) []searchRow {
+	rows := make([]searchRow, 0, len(snaps))
+	for _, snap := range snaps {
+		if snap.Name != "" {
+			rows = append(rows, searchRow{Name: snap.Name, Desc: snap.describe()})
+		}
+	}
+	return rows
+}
+
+// snapdFind queries /v2/find. Like `snap find`, an empty query lists the
+// featured snaps.
+func snapdFind(query string, timeout time.Duration) ([]snapdSnap, error) {
+	params := url.Values{}
+	if query = strings.TrimSpace(query); query != "" {
+		params.Set("q", query)
+	} else {
+		params.Set("section", "featured")
+	}
+	var snaps []snapdSnap
+	if err := snapdGet("/v2/find", params, timeout, &snaps); err != nil {
+		return nil, err
+	}
+	return snaps, nil
+}
+
+func snapdInstalled() ([]snapdSnap, error) {
+	var snaps []snapdSnap
+	if err := snapdGet("/v2/snaps", nil, 0, &snaps); err != nil {
+		return nil, err
+	}
+	return snaps, nil
+}
+
+// snapdClient talks to snapd over its unix socket. The socket path is
+// resolved on each dial so FPF_SNAPD_SOCKET is honoured.
+var snapdClient = &http.Client{
+	Transport: &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var dialer net.Dialer
+			return dialer.DialContext(ctx, "unix", snapdSocketPath())
+		},
+	},
+}
+
+// snapdError is an error response from snapd itself, as opposed to a
+// failure to reach it.
+type snapdError struct {
+	Path    string
+	Status  int
+	Message string
+}
+
+func (e *snapdError) Error() string {
+	return fmt.Sprintf("snapd %s: %d %s", e.Path, e.Status, e.Message)
+}
+
+// snapdGet issues a GET against snapd's unix socket and decodes the result
+// field of its response envelope into out.
+// snapdUnreachable reports whether err means there is no snapd to ask: its
+// socket is missing, or dialing it failed before the deadline.
+func snapdUnreachable(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var pathErr *fs.PathError
+	var opErr *net.OpError
+	return errors.As(err, &pathErr) || (errors.As(err, &opErr) && opErr.Op == "dial")
+}
+
+func snapdGet(path string, params url.Values, timeout time.Duration, out any) error {
+	if _, err := os.Stat(snapdSocketPath()); err != nil {
+		return err
+	}
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	target := "http://localhost" + path
+	if len(params) > 0 {
+		target += "?" + params.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := snapdClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var envelope struct {
+		Type       string          `json:"type"`
+		StatusCode int             `json:"status-code"`
+		Result     json.RawMessage `json:"result"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+		return err
+	}
+	if envelope.Type == "error" || resp.StatusCode != http.StatusOK {
+		var failure struct {
+			Message string `json:"message"`
+		}
+		_ = json.Unmarshal(envelope.Result, &failure)
+		return &snapdError{Path: path, Status: resp.StatusCode, Message: failure.Message}
+	}
+	return json.Unmarshal(envelope.Result, out)
+}
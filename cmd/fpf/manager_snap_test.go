@@ -0,0 +1,169 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeSnapd serves handler on a unix socket standing in for snapd and points
+// FPF_SNAPD_SOCKET at it.
+func fakeSnapd(t *testing.T, handler http.HandlerFunc) {
+	t.Helper()
+	socket := filepath.Join(t.TempDir(), "snapd.socket")
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Skipf("unix sockets unavailable: %v", err)
+	}
+	server := &http.Server{Handler: handler}
+	go server.Serve(listener)
+	t.Cleanup(func() { server.Close() })
+	t.Setenv("FPF_SNAPD_SOCKET", socket)
+}
+
+func TestSnapSearchUsesSnapd(t *testing.T) {
+	var gotPath, gotQuery string
+	fakeSnapd(t, func(w http.ResponseWriter, r *http.Request) {
+		gotPath, gotQuery = r.URL.Path, r.URL.Query().Get("q")
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(readFixture(t, "snapd-find.json"))
+	})
+
+	rows, err := snapManager{}.Search(searchInput{Query: " firefox "})
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if gotPath != "/v2/find" || gotQuery != "firefox" {
+		t.Fatalf("request = %s?q=%s", gotPath, gotQuery)
+	}
+	want := []searchRow{
+		{Name: "firefox", Desc: "[mozilla✓] Mozilla Firefox web browser (131.0.3-1, stable, strict)"},
+		{Name: "firefox-dev", Desc: "[fxdev] Firefox Developer Edition (132.0b9, stable, classic)"},
+	}
+	if !reflect.DeepEqual(rows, want) {
+		t.Fatalf("rows = %#v", rows)
+	}
+}
+
+func TestSnapdFindWithoutQueryListsFeatured(t *testing.T) {
+	var section string
+	fakeSnapd(t, func(w http.ResponseWriter, r *http.Request) {
+		section = r.URL.Query().Get("section")
+		w.Write([]byte(`{"type":"sync","status-code":200,"result":[]}`))
+	})
+
+	if _, err := snapdFind("", 0); err != nil {
+		t.Fatalf("snapdFind: %v", err)
+	}
+	if section != "featured" {
+		t.Fatalf("section = %q", section)
+	}
+}
+
+func TestSnapdErrorResponse(t *testing.T) {
+	fakeSnapd(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"type":"error","status-code":400,"result":{"message":"search term too short"}}`))
+	})
+
+	if _, err := snapdFind("x", 0); err == nil {
+		t.Fatal("expected snapd error to surface")
+	}
+}
+
+func TestSnapSearchKeepsSnapdErrorsOffTheCLI(t *testing.T) {
+	dir := t.TempDir()
+	logFile := filepath.Join(dir, "snap.log")
+	writeMockExecutable(t, dir, "snap", "#!/bin/sh\nprintf '%s\\n' \"$*\" >>\""+logFile+"\"\n")
+	t.Setenv("PATH", dir+":/usr/bin:/bin")
+
+	var status atomic.Int32
+	status.Store(http.StatusNotFound)
+	fakeSnapd(t, func(w http.ResponseWriter, r *http.Request) {
+		code := int(status.Load())
+		if code == 0 {
+			time.Sleep(500 * time.Millisecond)
+			code = http.StatusOK
+		}
+		w.WriteHeader(code)
+		fmt.Fprintf(w, `{"type":"error","status-code":%d,"result":{"message":"no snaps found","kind":"snap-not-found"}}`, code)
+	})
+
+	rows, err := snapManager{}.Search(searchInput{Query: "nothing-matches"})
+	if err != nil || len(rows) != 0 {
+		t.Fatalf("expected an empty result for snapd's 404, got %v, %v", rows, err)
+	}
+	status.Store(http.StatusInternalServerError)
+	if _, err := (snapManager{}).Search(searchInput{Query: "firefox"}); err == nil {
+		t.Fatal("expected snapd's error to be returned")
+	}
+	status.Store(0)
+	if _, err := (snapManager{}).Search(searchInput{Query: "firefox", CommandTimeout: 50 * time.Millisecond}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected a slow snapd to time out without a fallback, got %v", err)
+	}
+	if raw, _ := os.ReadFile(logFile); len(raw) != 0 {
+		t.Fatalf("expected no snap find fallback, got %q", raw)
+	}
+}
+
+func TestSnapInstalledDetailsUsesSnapd(t *testing.T) {
+	fakeSnapd(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v2/snaps" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(readFixture(t, "snapd-snaps.json"))
+	})
+
+	names, err := snapManager{}.ListInstalled()
+	if err != nil {
+		t.Fatalf("ListInstalled: %v", err)
+	}
+	if !reflect.DeepEqual(names, []string{"core22", "code"}) {
+		t.Fatalf("names = %v", names)
+	}
+
+	rows, err := snapManager{}.InstalledDetails()
+	if err != nil {
+		t.Fatalf("InstalledDetails: %v", err)
+	}
+	want := []searchRow{
+		{Name: "core22", Desc: "20240904 (latest/stable, strict, canonical✓)"},
+		{Name: "code", Desc: "1.94.2 (latest/stable, classic, vscode✓)"},
+	}
+	if !reflect.DeepEqual(rows, want) {
+		t.Fatalf("rows = %#v", rows)
+	}
+}
+
+func TestSnapSearchFallsBackToCLI(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("FPF_SNAPD_SOCKET", filepath.Join(dir, "missing.socket"))
+	writeMockExecutable(t, dir, "snap", "#!/bin/sh\nprintf 'Name  Version  Publisher  Notes  Summary\\nhello  2.10  canonical✓  -  GNU Hello\\n'\n")
+	t.Setenv("PATH", dir)
+
+	rows, err := snapManager{}.Search(searchInput{Query: "hello"})
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if len(rows) != 1 || rows[0].Name != "hello" {
+		t.Fatalf("rows = %#v", rows)
+	}
+
+	stale := filepath.Join(dir, "stale.socket")
+	if err := os.WriteFile(stale, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("FPF_SNAPD_SOCKET", stale)
+	if rows, err := (snapManager{}).Search(searchInput{Query: "hello"}); err != nil || len(rows) != 1 {
+		t.Fatalf("expected a socket nobody listens on to fall back, got %#v, %v", rows, err)
+	}
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"regexp"
+	"strings"
+)
+
+type wingetManager struct {
+	managerSpec
+}
+
+func init() {
+	registerManager(wingetManager{managerSpec{
+		name:     "winget",
+		label:    "WinGet",
+		order:    70,
+		binaries: []string{"winget"},
+	}})
+}
+
+func (wingetManager) Search(input searchInput) ([]searchRow, error) {
+	out, err := input.runOutput("winget", "search", input.Query, "--source", "winget", "--accept-source-agreements", "--disable-interactivity")
+	if err != nil {
+		return nil, err
+	}
+	return parseWingetSearch(out), nil
+}
+
+func (wingetManager) ListInstalled() ([]string, error) {
+	out, err := runOutputQuietErr("winget", "list", "--source", "winget", "--accept-source-agreements", "--disable-interactivity")
+	if err != nil {
+		return nil, err
+	}
+	return parseWingetInstalled(out), nil
+}
+
+func (wingetManager) Install(pkgs []string) error {
+	for _, pkg := range pkgs {
+		if err := runCommand("winget", "install", "--id", pkg, "--exact", "--source", "winget", "--accept-package-agreements", "--accept-source-agreements", "--disable-interactivity"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (wingetManager) Remove(pkgs []string) error {
+	for _, pkg := range pkgs {
+		if err := runCommand("winget", "uninstall", "--id", pkg, "--exact", "--source", "winget", "--disable-interactivity"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (wingetManager) Update() error {
+	return runCommand("winget", "upgrade", "--all", "--source", "winget", "--accept-package-agreements", "--accept-source-agreements", "--disable-interactivity")
+}
+
+func (wingetManager) Refresh() error {
+	return runCommand("winget", "source", "update", "--name", "winget", "--accept-source-agreements", "--disable-interactivity")
+}
+
+func (wingetManager) ShowInfo(pkg string) error {
+	return runCommandQuietErr("winget", "show", "--id", pkg, "--exact", "--source", "winget", "--accept-source-agreements", "--disable-interactivity")
+}
+
+func (wingetManager) InstallFzf() error {
+	if err := runCommand("winget", "install", "--id", "junegunn.fzf", "--exact", "--source", "winget", "--accept-package-agreements", "--accept-source-agreements", "--disable-interactivity"); err != nil {
+		return runCommand("winget", "install", "--id", "fzf", "--exact", "--source", "winget", "--accept-package-agreements", "--accept-source-agreements", "--disable-interactivity")
+	}
+	return nil
+}
+
+func parseWingetSearch(out []byte) []searchRow {
+	re := regexp.MustCompile(`\s{2,}`)
+	rows := make([]searchRow, 0)
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "Name") || strings.HasPrefix(line, "-") {
+			continue
+		}
+		cols := re.Split(line, -1)
+		if len(cols) < 2 {
+			continue
+		}
+		rows = append(rows, searchRow{Name: cols[1], Desc: "-"})
+	}
+	return rows
+}
+
+func parseWingetInstalled(out []byte) []string {
+	re := regexp.MustCompile(`\s{2,}`)
+	names := make([]string, 0)
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "Name") || strings.HasPrefix(line, "-") {
+			continue
+		}
+		cols := re.Split(line, -1)
+		if len(cols) >= 2 {
+			names = append(names, cols[1])
+		}
+	}
+	return names
+}
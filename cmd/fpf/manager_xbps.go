@@ -0,0 +1,99 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+)
+
+type xbpsManager struct {
+	managerSpec
+}
+
+func init() {
+	registerManager(xbpsManager{managerSpec{
+		name:     "xbps",
+		label:    "Void (xbps)",
+		order:    57,
+		binaries: []string{"xbps-query", "xbps-install", "xbps-remove"},
+		root:     true,
+	}})
+}
+
+func (xbpsManager) Search(input searchInput) ([]searchRow, error) {
+	out, err := input.runOutput("xbps-query", "-Rs", input.Query)
+	if err != nil {
+		return nil, err
+	}
+	return parseXbpsSearch(out), nil
+}
+
+func (xbpsManager) ListInstalled() ([]string, error) {
+	out, err := runOutputQuietErr("xbps-query", "-l")
+	if err != nil {
+		return nil, err
+	}
+	rows := parseXbpsSearch(out)
+	names := make([]string, 0, len(rows))
+	for _, row := range rows {
+		names = append(names, row.Name)
+	}
+	return names, nil
+}
+
+func (xbpsManager) Install(pkgs []string) error {
+	return runRootCommand("xbps-install", append([]string{"-y"}, pkgs...)...)
+}
+
+func (xbpsManager) Remove(pkgs []string) error {
+	return runRootCommand("xbps-remove", append([]string{"-R", "-y"}, pkgs...)...)
+}
+
+func (xbpsManager) Update() error {
+	return runRootCommand("xbps-install", "-Suy")
+}
+
+func (xbpsManager) Refresh() error {
+	return runRootCommand("xbps-install", "-S")
+}
+
+func (xbpsManager) ShowInfo(pkg string) error {
+	return runCommandQuietErr("xbps-query", "-R", pkg)
+}
+
+func (xbpsManager) InstallFzf() error {
+	return runRootCommand("xbps-install", "-y", "fzf")
+}
+
+// xbpsPackageName drops the "-<version>_<revision>" suffix from an xbps
+// pkgver; xbps versions never contain a dash.
+func xbpsPackageName(pkgver string) string {
+	i := strings.LastIndex(pkgver, "-")
+	if i <= 0 || !strings.Contains(pkgver[i+1:], "_") {
+		return pkgver
+	}
+	return pkgver[:i]
+}
+
+// parseXbpsSearch reads both `xbps-query -Rs` ("[-] name-1.0_1  desc") and
+// `xbps-query -l` ("ii name-1.0_1  desc") output.
+func parseXbpsSearch(out []byte) []searchRow {
+	rows := make([]searchRow, 0)
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		parts := strings.Fields(scanner.Text())
+		if len(parts) < 2 {
+			continue
+		}
+		name := xbpsPackageName(parts[1])
+		if name == "" {
+			continue
+		}
+		desc := "-"
+		if len(parts) > 2 {
+			desc = strings.Join(parts[2:], " ")
+		}
+		rows = append(rows, searchRow{Name: name, Desc: desc})
+	}
+	return rows
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseXbpsSearch(t *testing.T) {
+	rows := parseXbpsSearch(readFixture(t, "xbps-search.txt"))
+	if len(rows) != 3 {
+		t.Fatalf("parseXbpsSearch returned %d rows: %+v", len(rows), rows)
+	}
+	want := searchRow{Name: "ripgrep", Desc: "Fast line-oriented search tool, similar to ag and ack"}
+	if rows[0] != want {
+		t.Fatalf("row 0=%+v want %+v", rows[0], want)
+	}
+	if rows[2].Name != "vim-ripgrep" {
+		t.Fatalf("row 2 name=%q want vim-ripgrep", rows[2].Name)
+	}
+
+	installed := parseXbpsSearch(readFixture(t, "xbps-installed.txt"))
+	names := make([]string, 0, len(installed))
+	for _, row := range installed {
+		names = append(names, row.Name)
+	}
+	if strings.Join(names, ",") != "base-system,ripgrep-all,xbps" {
+		t.Fatalf("installed names=%v", names)
+	}
+}
+
+func TestXbpsPackageName(t *testing.T) {
+	tests := map[string]string{
+		"ripgrep-14.1.1_1":      "ripgrep",
+		"python3-yaml-6.0.1_3":  "python3-yaml",
+		"base-system-0.114_2":   "base-system",
+		"ripgrep":               "ripgrep",
+		"some-name-without-rev": "some-name-without-rev",
+	}
+	for in, want := range tests {
+		if got := xbpsPackageName(in); got != want {
+			t.Fatalf("xbpsPackageName(%q)=%q want %q", in, got, want)
+		}
+	}
+}
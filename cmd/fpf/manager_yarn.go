@@ -0,0 +1,140 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+type yarnManager struct {
+	managerSpec
+}
+
+func init() {
+	registerManager(yarnManager{managerSpec{
+		name:     "yarn",
+		label:    "Yarn",
+		order:    134,
+		binaries: []string{"yarn"},
+	}})
+}
+
+var (
+	yarnClassicCheckOnce sync.Once
+	yarnClassicReady     bool
+)
+
+// yarnClassicAvailable reports whether `yarn` is Yarn 1.x. Yarn 2+ dropped
+// `yarn global`, so only classic can manage global packages. The answer is
+// kept in the cache directory, so only the first process after yarn changes
+// pays for `yarn --version`.
+func yarnClassicAvailable() bool {
+	yarnClassicCheckOnce.Do(func() {
+		yarnClassicReady = cachedBinaryProbe("yarn-classic", "yarn", func() bool {
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+
+			cmd := exec.CommandContext(ctx, "yarn", "--version")
+			cmd.Env = os.Environ()
+			out, err := cmd.Output()
+			return err == nil && strings.HasPrefix(strings.TrimSpace(string(out)), "1.")
+		})
+	})
+	return yarnClassicReady
+}
+
+func (yarnManager) Ready() bool {
+	return yarnClassicAvailable()
+}
+
+// Search reuses npm's registry search; Yarn classic has no search command.
+func (yarnManager) Search(input searchInput) ([]searchRow, error) {
+	return jsRegistrySearch(input)
+}
+
+// ListInstalled reads the dependencies of the global project in
+// `yarn global dir`, falling back to `yarn global list`, which only shows
+// packages that ship binaries.
+func (yarnManager) ListInstalled() ([]string, error) {
+	if out, err := runOutputQuietErr("yarn", "global", "dir"); err == nil {
+		if dir := strings.TrimSpace(string(out)); dir != "" {
+			if raw, readErr := os.ReadFile(filepath.Join(dir, "package.json")); readErr == nil {
+				return parseYarnGlobalPackageJSON(raw)
+			}
+		}
+	}
+	out, err := runOutputQuietErr("yarn", "global", "list", "--depth=0")
+	if err != nil {
+		return nil, err
+	}
+	return parseYarnGlobalList(out), nil
+}
+
+func (yarnManager) Install(pkgs []string) error {
+	return runCommand("yarn", append([]string{"global", "add"}, pkgs...)...)
+}
+
+func (yarnManager) Remove(pkgs []string) error {
+	return runCommand("yarn", append([]string{"global", "remove"}, pkgs...)...)
+}
+
+func (yarnManager) Update() error {
+	return runCommand("yarn", "global", "upgrade")
+}
+
+// Refresh is a no-op: Yarn resolves packages against the registry at install
+// time and keeps no catalog fpf needs to update.
+func (yarnManager) Refresh() error {
+	return nil
+}
+
+func (yarnManager) ShowInfo(pkg string) error {
+	return runCommandQuietErr("yarn", "info", pkg)
+}
+
+func parseYarnGlobalPackageJSON(raw []byte) ([]string, error) {
+	var doc struct {
+		Dependencies map[string]string `json:"dependencies"`
+	}
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(doc.Dependencies))
+	for name := range doc.Dependencies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// parseYarnGlobalList reads `info "name@1.0.0" has binaries:` lines from
+// `yarn global list`.
+func parseYarnGlobalList(out []byte) []string {
+	names := make([]string, 0)
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if !strings.HasPrefix(line, "info \"") {
+			continue
+		}
+		spec, _, ok := strings.Cut(strings.TrimPrefix(line, "info \""), "\"")
+		if !ok {
+			continue
+		}
+		if idx := strings.LastIndex(spec, "@"); idx > 0 {
+			spec = spec[:idx]
+		}
+		if spec != "" {
+			names = append(names, spec)
+		}
+	}
+	return names
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestParseYarnGlobalInstalled(t *testing.T) {
+	names, err := parseYarnGlobalPackageJSON(readFixture(t, "yarn-global-package.json"))
+	if err != nil {
+		t.Fatalf("parseYarnGlobalPackageJSON: %v", err)
+	}
+	if got := strings.Join(names, ","); got != "@vue/cli,lodash,typescript" {
+		t.Fatalf("parseYarnGlobalPackageJSON=%q", got)
+	}
+	if got := strings.Join(parseYarnGlobalList(readFixture(t, "yarn-global-list.txt")), ","); got != "@vue/cli,typescript" {
+		t.Fatalf("parseYarnGlobalList=%q", got)
+	}
+}
+
+func TestYarnClassicProbeIsCached(t *testing.T) {
+	bin := t.TempDir()
+	logFile := filepath.Join(t.TempDir(), "yarn.log")
+	script := "#!/bin/sh\nprintf 'version\\n' >>\"" + logFile + "\"\nprintf '1.22.19\\n'\n"
+	writeMockExecutable(t, bin, "yarn", script)
+	t.Setenv("PATH", bin)
+	t.Setenv("FPF_CACHE_DIR", t.TempDir())
+	probe := func() bool {
+		yarnClassicCheckOnce = sync.Once{}
+		return yarnClassicAvailable()
+	}
+	t.Cleanup(func() { yarnClassicCheckOnce = sync.Once{} })
+
+	if !probe() || !probe() {
+		t.Fatal("expected yarn 1.x to be detected as classic")
+	}
+	raw, _ := os.ReadFile(logFile)
+	if got := strings.Count(string(raw), "version"); got != 1 {
+		t.Fatalf("expected one yarn --version across processes, got %d", got)
+	}
+
+	writeMockExecutable(t, bin, "yarn", script+"# upgraded\n")
+	if !probe() {
+		t.Fatal("expected classic yarn after the binary changed")
+	}
+	raw, _ = os.ReadFile(logFile)
+	if got := strings.Count(string(raw), "version"); got != 2 {
+		t.Fatalf("expected a changed yarn binary to be probed again, got %d runs", got)
+	}
+}
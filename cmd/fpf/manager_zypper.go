@@ -0,0 +1,103 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+type zypperManager struct {
+	managerSpec
+}
+
+func init() {
+	registerManager(zypperManager{managerSpec{
+		name:     "zypper",
+		label:    "Zypper",
+		order:    40,
+		binaries: []string{"zypper"},
+		root:     true,
+	}})
+}
+
+func (zypperManager) Search(input searchInput) ([]searchRow, error) {
+	out, err := input.runOutput("zypper", "--non-interactive", "--quiet", "search", "--details", "--type", "package", input.Query)
+	if err != nil {
+		return nil, err
+	}
+	return parseZypperSearch(out), nil
+}
+
+func (zypperManager) ListInstalled() ([]string, error) {
+	out, err := runOutputQuietErr("zypper", "--non-interactive", "--quiet", "search", "--installed-only", "--details", "--type", "package")
+	if err != nil {
+		return nil, err
+	}
+	return parseZypperInstalled(out), nil
+}
+
+func (zypperManager) Install(pkgs []string) error {
+	return runRootCommand("zypper", append([]string{"--non-interactive", "install", "--auto-agree-with-licenses"}, pkgs...)...)
+}
+
+func (zypperManager) Remove(pkgs []string) error {
+	return runRootCommand("zypper", append([]string{"--non-interactive", "remove"}, pkgs...)...)
+}
+
+func (zypperManager) Update() error {
+	if err := runRootCommand("zypper", "--non-interactive", "refresh"); err != nil {
+		return err
+	}
+	return runRootCommand("zypper", "--non-interactive", "update")
+}
+
+func (zypperManager) Refresh() error {
+	return runRootCommand("zypper", "--non-interactive", "refresh")
+}
+
+func (zypperManager) ShowInfo(pkg string) error {
+	return runCommandQuietErr("zypper", "--non-interactive", "info", pkg)
+}
+
+func (zypperManager) InstallFzf() error {
+	return runRootCommand("zypper", "--non-interactive", "install", "--auto-agree-with-licenses", "fzf")
+}
+
+func parseZypperSearch(out []byte) []searchRow {
+	rows := make([]searchRow, 0)
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		line := scanner.Text()
+		parts := strings.Split(line, "|")
+		if len(parts) < 7 {
+			continue
+		}
+		name := strings.TrimSpace(parts[2])
+		ver := strings.TrimSpace(parts[4])
+		repo := strings.TrimSpace(parts[6])
+		if name == "" {
+			continue
+		}
+		desc := fmt.Sprintf("version %s from %s", ver, repo)
+		rows = append(rows, searchRow{Name: name, Desc: desc})
+	}
+	return rows
+}
+
+func parseZypperInstalled(out []byte) []string {
+	names := make([]string, 0)
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		line := scanner.Text()
+		parts := strings.Split(line, "|")
+		if len(parts) < 3 {
+			continue
+		}
+		name := strings.TrimSpace(parts[2])
+		if name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
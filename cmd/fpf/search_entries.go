@@ -2,29 +2,22 @@ package main
 
 import (
 	"bufio"
-	"bytes"
 	"context"
 	"errors"
 	"fmt"
-	"io"
 	"os"
 	"os/exec"
-	"path/filepath"
-	"regexp"
 	"strings"
-	"sync"
 	"time"
-
-	"github.com/Timmy6942025/fpf-cli/internal/flatpak"
 )
 
 type searchInput struct {
-	Manager             string
-	Query               string
-	Limit               int
-	NPMSearchLimit      int
-	CommandTimeout      time.Duration
-	AllowBunNPMFallback bool
+	Manager                  string
+	Query                    string
+	Limit                    int
+	NPMSearchLimit           int
+	CommandTimeout           time.Duration
+	AllowNPMRegistryFallback bool
 }
 
 func maybeRunGoSearchEntries(args []string) (bool, int) {
@@ -62,7 +55,7 @@ func maybeRunGoSearchEntries(args []string) (bool, int) {
 }
 
 func parseSearchInput(args []string) (searchInput, bool, error) {
-	input := searchInput{NPMSearchLimit: 500, AllowBunNPMFallback: true}
+	input := searchInput{NPMSearchLimit: 500, AllowNPMRegistryFallback: true}
 	if len(args) == 0 {
 		return input, false, nil
 	}
@@ -135,182 +128,24 @@ func parseSearchInput(args []string) (searchInput, bool, error) {
 	return input, true, nil
 }
 
+// searchRow is one search result. Managers that rank their own matches set
+// Rank with rankFromScore; zero leaves ranking to scoreRows.
 type searchRow struct {
 	Name string
 	Desc string
+	Rank int
 }
 
 func executeSearchEntries(input searchInput) ([]searchRow, error) {
-	manager := input.Manager
-	query := input.Query
-	runOutput := func(name string, args ...string) ([]byte, error) {
-		return runOutputQuietErrWithTimeout(input.CommandTimeout, name, args...)
+	manager, ok := lookupManager(input.Manager)
+	if !ok {
+		return nil, fmt.Errorf("unsupported manager: %s", input.Manager)
 	}
+	return manager.Search(input)
+}
 
-	switch manager {
-	case "apt":
-		// Use catalog-based search for better performance
-		if catalogRows, err := loadAptCatalogRows(query); err == nil && len(catalogRows) > 0 {
-			return catalogRows, nil
-		}
-		// Fallback to direct search if catalog fails or is empty
-		out, err := runOutput("apt-cache", "search", "--", query)
-		if err != nil {
-			return nil, err
-		}
-		return parseAptSearch(out), nil
-	case "dnf":
-		pattern := "*"
-		if query != "" {
-			pattern = "*" + query + "*"
-		}
-		out, err := runOutput("dnf", "-q", "list", "available", pattern)
-		if err != nil {
-			return nil, err
-		}
-		return parseDNFSearch(out), nil
-	case "pacman":
-		out, err := runOutput("pacman", "-Ss", "--", query)
-		if err != nil {
-			return nil, err
-		}
-		return parsePacmanSearch(out), nil
-	case "zypper":
-		out, err := runOutput("zypper", "--non-interactive", "--quiet", "search", "--details", "--type", "package", query)
-		if err != nil {
-			return nil, err
-		}
-		return parseZypperSearch(out), nil
-	case "emerge":
-		out, err := runOutput("emerge", "--searchdesc", "--color=n", query)
-		if err != nil {
-			return nil, err
-		}
-		return parseEmergeSearch(out), nil
-	case "brew":
-		if catalogRows, err := loadBrewCatalogRows(query); err == nil && len(catalogRows) > 0 {
-			return catalogRows, nil
-		}
-		out, err := runOutput("brew", "search", query)
-		if err != nil {
-			return nil, err
-		}
-		return parseBrewSearch(out), nil
-	case "winget":
-		out, err := runOutput("winget", "search", query, "--source", "winget", "--accept-source-agreements", "--disable-interactivity")
-		if err != nil {
-			return nil, err
-		}
-		return parseWingetSearch(out), nil
-	case "choco":
-		out, err := runOutput("choco", "search", query, "--limit-output")
-		if err != nil {
-			return nil, err
-		}
-		return parseChocoSearch(out), nil
-	case "scoop":
-		out, err := runOutput("scoop", "search", query)
-		if err != nil {
-			return nil, err
-		}
-		return parseScoopSearch(out), nil
-	case "snap":
-		out, err := runOutput("snap", "find", query)
-		if err != nil {
-			return nil, err
-		}
-		return parseSnapSearch(out), nil
-	case "flatpak":
-		if flatpak.ShouldUseDirectCache() {
-			cache, err := flatpak.LoadBest()
-			if err == nil && len(cache.Apps) > 0 {
-				results := cache.Filter(query)
-				rows := make([]searchRow, len(results))
-				for i, r := range results {
-					rows[i] = searchRow{Name: r.Name, Desc: r.Desc}
-				}
-				return rows, nil
-			}
-			if err == flatpak.ErrNoCache {
-				_ = flatpak.UpdateAppStream()
-				cache, refreshErr := flatpak.LoadBest()
-				if refreshErr == nil && len(cache.Apps) > 0 {
-					results := cache.Filter(query)
-					rows := make([]searchRow, len(results))
-					for i, r := range results {
-						rows[i] = searchRow{Name: r.Name, Desc: r.Desc}
-					}
-					return rows, nil
-				}
-			}
-		}
-		if query == "" {
-			out, err := runOutput("flatpak", "remote-ls", "--app", "--columns=application,description", "flathub")
-			if err != nil {
-				if errors.Is(err, context.DeadlineExceeded) {
-					return nil, err
-				}
-				out, err = runOutput("flatpak", "remote-ls", "--app", "--columns=application,description")
-				if err != nil {
-					return nil, err
-				}
-			}
-			return parseFlatpakSearch(out), nil
-		}
-		out, err := runOutput("flatpak", "search", "--columns=application,description", query)
-		if err != nil {
-			if errors.Is(err, context.DeadlineExceeded) {
-				return nil, err
-			}
-			out, err = runOutput("flatpak", "search", query)
-			if err != nil {
-				return nil, err
-			}
-		}
-		return parseFlatpakSearch(out), nil
-	case "npm":
-		out, err := runOutput("npm", "search", query, fmt.Sprintf("--searchlimit=%d", input.NPMSearchLimit), "--parseable")
-		if err != nil {
-			return nil, err
-		}
-		return parseNpmSearch(out), nil
-	case "bun":
-		if !bunSearchAvailable() {
-			if !input.AllowBunNPMFallback {
-				return nil, nil
-			}
-			npmOut, npmErr := runOutput("npm", "search", query, fmt.Sprintf("--searchlimit=%d", input.NPMSearchLimit), "--parseable")
-			if npmErr != nil {
-				return nil, npmErr
-			}
-			return parseNpmSearch(npmOut), nil
-		}
-		out, err := runOutput("bun", "search", query)
-		if err != nil {
-			if _, lookupErr := exec.LookPath("npm"); lookupErr != nil || !input.AllowBunNPMFallback {
-				return nil, err
-			}
-			npmOut, npmErr := runOutput("npm", "search", query, fmt.Sprintf("--searchlimit=%d", input.NPMSearchLimit), "--parseable")
-			if npmErr != nil {
-				return nil, err
-			}
-			return parseNpmSearch(npmOut), nil
-		}
-		rows := parseBunSearch(out)
-		if len(rows) > 0 {
-			return rows, nil
-		}
-		if _, lookupErr := exec.LookPath("npm"); lookupErr != nil || !input.AllowBunNPMFallback {
-			return rows, nil
-		}
-		npmOut, npmErr := runOutput("npm", "search", query, fmt.Sprintf("--searchlimit=%d", input.NPMSearchLimit), "--parseable")
-		if npmErr != nil {
-			return rows, nil
-		}
-		return parseNpmSearch(npmOut), nil
-	default:
-		return nil, fmt.Errorf("unsupported manager: %s", manager)
-	}
+func (input searchInput) runOutput(name string, args ...string) ([]byte, error) {
+	return runOutputQuietErrWithTimeout(input.CommandTimeout, name, args...)
 }
 
 func runOutputQuietErr(name string, args ...string) ([]byte, error) {
@@ -381,330 +216,6 @@ func (ioDiscard) Write(p []byte) (int, error) {
 	return len(p), nil
 }
 
-var (
-	bunSearchCheckOnce sync.Once
-	bunSearchReady     bool
-)
-
-func bunSearchAvailable() bool {
-	bunSearchCheckOnce.Do(func() {
-		if _, err := exec.LookPath("bun"); err != nil {
-			bunSearchReady = false
-			return
-		}
-		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
-		defer cancel()
-
-		cmd := exec.CommandContext(ctx, "bun", "search", "--help")
-		cmd.Env = os.Environ()
-		out, err := cmd.CombinedOutput()
-		if err == nil {
-			bunSearchReady = true
-			return
-		}
-		text := strings.ToLower(string(out))
-		if strings.Contains(text, "script not found \"search\"") || strings.Contains(text, "unknown command") {
-			bunSearchReady = false
-			return
-		}
-		// Keep behavior permissive for non-standard bun outputs.
-		bunSearchReady = true
-	})
-	return bunSearchReady
-}
-
-func parseAptSearch(out []byte) []searchRow {
-	rows := make([]searchRow, 0)
-	scanner := bufio.NewScanner(bytes.NewReader(out))
-	for scanner.Scan() {
-		line := strings.TrimSpace(scanner.Text())
-		if line == "" {
-			continue
-		}
-		parts := strings.SplitN(line, " - ", 2)
-		name := strings.TrimSpace(parts[0])
-		desc := "-"
-		if len(parts) == 2 && strings.TrimSpace(parts[1]) != "" {
-			desc = strings.TrimSpace(parts[1])
-		}
-		rows = append(rows, searchRow{Name: name, Desc: desc})
-	}
-	return rows
-}
-
-func parseDNFSearch(out []byte) []searchRow {
-	rows := make([]searchRow, 0)
-	scanner := bufio.NewScanner(bytes.NewReader(out))
-	for scanner.Scan() {
-		line := strings.TrimSpace(scanner.Text())
-		if line == "" || strings.HasPrefix(line, "Available") || strings.HasPrefix(line, "Last") || strings.HasPrefix(line, "Installed") {
-			continue
-		}
-		parts := strings.Fields(line)
-		if len(parts) < 2 {
-			continue
-		}
-		name := parts[0]
-		if idx := strings.LastIndex(name, "."); idx > 0 {
-			name = name[:idx]
-		}
-		rows = append(rows, searchRow{Name: name, Desc: parts[1]})
-	}
-	return rows
-}
-
-func parsePacmanSearch(out []byte) []searchRow {
-	rows := make([]searchRow, 0)
-	lines := splitLines(out)
-	for i := 0; i+1 < len(lines); i += 2 {
-		head := strings.TrimSpace(lines[i])
-		if head == "" {
-			continue
-		}
-		parts := strings.Fields(head)
-		if len(parts) == 0 {
-			continue
-		}
-		pkg := parts[0]
-		if strings.Contains(pkg, "/") {
-			seg := strings.SplitN(pkg, "/", 2)
-			if len(seg) == 2 {
-				pkg = seg[1]
-			}
-		}
-		desc := strings.TrimSpace(lines[i+1])
-		if desc == "" {
-			desc = "-"
-		}
-		rows = append(rows, searchRow{Name: pkg, Desc: desc})
-	}
-	return rows
-}
-
-func parseZypperSearch(out []byte) []searchRow {
-	rows := make([]searchRow, 0)
-	scanner := bufio.NewScanner(bytes.NewReader(out))
-	for scanner.Scan() {
-		line := scanner.Text()
-		parts := strings.Split(line, "|")
-		if len(parts) < 7 {
-			continue
-		}
-		name := strings.TrimSpace(parts[2])
-		ver := strings.TrimSpace(parts[4])
-		repo := strings.TrimSpace(parts[6])
-		if name == "" {
-			continue
-		}
-		desc := fmt.Sprintf("version %s from %s", ver, repo)
-		rows = append(rows, searchRow{Name: name, Desc: desc})
-	}
-	return rows
-}
-
-func parseEmergeSearch(out []byte) []searchRow {
-	rows := make([]searchRow, 0)
-	var atom string
-	scanner := bufio.NewScanner(bytes.NewReader(out))
-	for scanner.Scan() {
-		line := scanner.Text()
-		if strings.HasPrefix(line, "*  ") {
-			parts := strings.Fields(line)
-			if len(parts) >= 2 {
-				atom = parts[1]
-			}
-			continue
-		}
-		trim := strings.TrimSpace(line)
-		if strings.HasPrefix(trim, "Description:") && atom != "" {
-			desc := strings.TrimSpace(strings.TrimPrefix(trim, "Description:"))
-			if desc == "" {
-				desc = "-"
-			}
-			rows = append(rows, searchRow{Name: atom, Desc: desc})
-			atom = ""
-		}
-	}
-	return rows
-}
-
-func parseBrewSearch(out []byte) []searchRow {
-	rows := make([]searchRow, 0)
-	scanner := bufio.NewScanner(bytes.NewReader(out))
-	for scanner.Scan() {
-		name := strings.TrimSpace(scanner.Text())
-		if name == "" || name == "==>" {
-			continue
-		}
-		rows = append(rows, searchRow{Name: name, Desc: "-"})
-	}
-	return rows
-}
-
-func parseWingetSearch(out []byte) []searchRow {
-	re := regexp.MustCompile(`\s{2,}`)
-	rows := make([]searchRow, 0)
-	scanner := bufio.NewScanner(bytes.NewReader(out))
-	for scanner.Scan() {
-		line := strings.TrimSpace(scanner.Text())
-		if line == "" || strings.HasPrefix(line, "Name") || strings.HasPrefix(line, "-") {
-			continue
-		}
-		cols := re.Split(line, -1)
-		if len(cols) < 2 {
-			continue
-		}
-		rows = append(rows, searchRow{Name: cols[1], Desc: "-"})
-	}
-	return rows
-}
-
-func parseChocoSearch(out []byte) []searchRow {
-	rows := make([]searchRow, 0)
-	scanner := bufio.NewScanner(bytes.NewReader(out))
-	for scanner.Scan() {
-		line := strings.TrimSpace(scanner.Text())
-		if line == "" {
-			continue
-		}
-		parts := strings.SplitN(line, "|", 2)
-		name := strings.TrimSpace(parts[0])
-		if name == "" {
-			continue
-		}
-		ver := "-"
-		if len(parts) == 2 && strings.TrimSpace(parts[1]) != "" {
-			ver = strings.TrimSpace(parts[1])
-		}
-		rows = append(rows, searchRow{Name: name, Desc: "version " + ver})
-	}
-	return rows
-}
-
-func parseScoopSearch(out []byte) []searchRow {
-	rows := make([]searchRow, 0)
-	scanner := bufio.NewScanner(bytes.NewReader(out))
-	for scanner.Scan() {
-		line := strings.TrimSpace(scanner.Text())
-		if line == "" || strings.HasPrefix(line, "Name") || strings.HasPrefix(line, "-") {
-			continue
-		}
-		parts := strings.Fields(line)
-		if len(parts) == 0 {
-			continue
-		}
-		name := parts[0]
-		desc := "-"
-		if len(parts) > 1 {
-			desc = strings.Join(parts[1:], " ")
-		}
-		rows = append(rows, searchRow{Name: name, Desc: desc})
-	}
-	return rows
-}
-
-func parseSnapSearch(out []byte) []searchRow {
-	rows := make([]searchRow, 0)
-	lines := splitLines(out)
-	for i, line := range lines {
-		trim := strings.TrimSpace(line)
-		if i == 0 || trim == "" {
-			continue
-		}
-		parts := strings.Fields(trim)
-		if len(parts) == 0 {
-			continue
-		}
-		name := parts[0]
-		desc := "-"
-		if len(parts) > 1 {
-			desc = strings.Join(parts[1:], " ")
-		}
-		rows = append(rows, searchRow{Name: name, Desc: desc})
-	}
-	return rows
-}
-
-func parseFlatpakSearch(out []byte) []searchRow {
-	rows := make([]searchRow, 0)
-	lines := splitLines(out)
-	for _, line := range lines {
-		trim := strings.TrimSpace(line)
-		if trim == "" || isFlatpakHeaderLine(trim) {
-			continue
-		}
-		parts := strings.Fields(trim)
-		if len(parts) == 0 {
-			continue
-		}
-		name := parts[0]
-		desc := "-"
-		if len(parts) > 1 {
-			desc = strings.TrimSpace(trim[len(name):])
-			if desc == "" {
-				desc = "-"
-			}
-		}
-		rows = append(rows, searchRow{Name: name, Desc: desc})
-	}
-	return rows
-}
-
-func isFlatpakHeaderLine(line string) bool {
-	fields := strings.Fields(strings.ToLower(strings.TrimSpace(line)))
-	if len(fields) < 2 {
-		return false
-	}
-	return fields[0] == "application" && fields[1] == "description"
-}
-
-func parseNpmSearch(out []byte) []searchRow {
-	rows := make([]searchRow, 0)
-	scanner := bufio.NewScanner(bytes.NewReader(out))
-	for scanner.Scan() {
-		line := scanner.Text()
-		parts := strings.Split(line, "\t")
-		if len(parts) < 2 {
-			continue
-		}
-		name := strings.TrimSpace(parts[0])
-		desc := strings.TrimSpace(parts[1])
-		if name == "" {
-			continue
-		}
-		if desc == "" {
-			desc = "-"
-		}
-		rows = append(rows, searchRow{Name: name, Desc: desc})
-	}
-	return rows
-}
-
-func parseBunSearch(out []byte) []searchRow {
-	rows := make([]searchRow, 0)
-	lines := splitLines(out)
-	for i, line := range lines {
-		if i == 0 {
-			continue
-		}
-		trim := strings.TrimSpace(line)
-		if trim == "" {
-			continue
-		}
-		parts := strings.Fields(trim)
-		if len(parts) == 0 {
-			continue
-		}
-		name := parts[0]
-		desc := "-"
-		if len(parts) > 1 {
-			desc = strings.Join(parts[1:], " ")
-		}
-		rows = append(rows, searchRow{Name: name, Desc: desc})
-	}
-	return rows
-}
-
 func dedupeRows(rows []searchRow) []searchRow {
 	seen := make(map[string]struct{}, len(rows))
 	out := make([]searchRow, 0, len(rows))
@@ -733,144 +244,48 @@ func splitLines(out []byte) []string {
 	return strings.Split(raw, "\n")
 }
 
-// APT catalog functions
-func loadAptCatalogRows(q string) ([]searchRow, error) {
-	fingerprint := aptCatalogFingerprint()
-	key := cacheChecksum(fingerprint)
-	cachePath := filepath.Join(cacheRootPath(), "search-catalog", "apt", key+".tsv")
-
-	// Try to load from cache
-	if raw, err := os.ReadFile(cachePath); err == nil {
-		rows := parseCachedRows(raw)
-		if len(rows) > 0 {
-			return filterAPT(rows, q), nil
-		}
-	}
-
-	// Build catalog
-	rows, err := buildAptCatalogRows()
-	if err != nil {
-		return nil, err
-	}
-	if len(rows) == 0 {
-		return nil, nil
-	}
-
-	// Cache the catalog
-	_ = os.MkdirAll(filepath.Dir(cachePath), 0o755)
-	_ = os.WriteFile(cachePath, []byte(renderAPT(rows)), 0o644)
-
-	return filterAPT(rows, q), nil
-}
-
-func aptCatalogFingerprint() string {
-	cmdPath, _ := exec.LookPath("apt-cache")
-	if cmdPath == "" {
-		cmdPath = "missing"
-	}
-	if fixtureRoot := strings.TrimSpace(os.Getenv("FPF_TEST_FIXTURE_DIR")); fixtureRoot != "" {
-		fixturePath := filepath.Join(fixtureRoot, "apt-dumpavail.txt")
-		if info, err := os.Stat(fixturePath); err == nil {
-			return fmt.Sprintf("apt|catalog|%s|fixture=%d|%d", cmdPath, info.ModTime().Unix(), info.Size())
-		}
-	}
-	return fmt.Sprintf("apt|catalog|%s", cmdPath)
-}
-
 func cacheChecksum(input string) string {
 	return stableChecksum(input)
 }
 
-func buildAptCatalogRows() ([]searchRow, error) {
-	cmd := exec.Command("apt-cache", "dumpavail")
-	cmd.Env = os.Environ()
-	cmd.Stderr = ioDiscard{}
-
-	stdout, err := cmd.StdoutPipe()
-	if err != nil {
-		return nil, err
-	}
-	if err := cmd.Start(); err != nil {
-		return nil, err
-	}
-
-	rows := parseAptDumpAvailReader(stdout)
-	if err := cmd.Wait(); err != nil {
-		return nil, err
+func renderAPT(rows []searchRow) string {
+	var b strings.Builder
+	for _, row := range rows {
+		b.WriteString(row.Name)
+		b.WriteString("\t")
+		b.WriteString(row.Desc)
+		b.WriteString("\n")
 	}
-	return rows, nil
-}
-
-func parseAptDumpAvail(out []byte) []searchRow {
-	return parseAptDumpAvailReader(bytes.NewReader(out))
+	return b.String()
 }
 
-func parseAptDumpAvailReader(reader io.Reader) []searchRow {
-	rows := make([]searchRow, 0)
-	scanner := bufio.NewScanner(reader)
-	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
-	var pkg, desc string
-	flush := func() {
-		name := strings.TrimSpace(pkg)
-		if name == "" {
-			return
-		}
-		descOut := strings.TrimSpace(desc)
-		if descOut == "" {
-			descOut = "-"
+// catalogDescText drops the leading "[origin]" tag a catalog row carries,
+// so queries match the description rather than the repository name.
+func catalogDescText(desc string) string {
+	if strings.HasPrefix(desc, "[") {
+		if _, rest, ok := strings.Cut(desc, "]"); ok {
+			return strings.TrimSpace(rest)
 		}
-		rows = append(rows, searchRow{Name: name, Desc: descOut})
-	}
-	for scanner.Scan() {
-		line := scanner.Text()
-		switch {
-		case strings.HasPrefix(line, "Package:"):
-			if pkg != "" {
-				flush()
-			}
-			pkg = strings.TrimSpace(strings.TrimPrefix(line, "Package:"))
-			desc = ""
-		case strings.HasPrefix(line, "Description:"):
-			desc = strings.TrimSpace(strings.TrimPrefix(line, "Description:"))
-		case strings.TrimSpace(line) == "":
-			if pkg != "" {
-				flush()
-				pkg = ""
-				desc = ""
-			}
-		}
-	}
-	if pkg != "" {
-		flush()
 	}
-	return rows
+	return desc
 }
 
-func filterAPT(rows []searchRow, q string) []searchRow {
+// filterCatalogRows keeps rows whose name or untagged description contains
+// the query.
+func filterCatalogRows(rows []searchRow, q string) []searchRow {
+	q = strings.ToLower(strings.TrimSpace(q))
 	if q == "" {
 		return rows
 	}
-	qLower := strings.ToLower(q)
 	filtered := make([]searchRow, 0)
 	for _, row := range rows {
-		if strings.Contains(strings.ToLower(row.Name), qLower) || strings.Contains(strings.ToLower(row.Desc), qLower) {
+		if strings.Contains(strings.ToLower(row.Name), q) || strings.Contains(strings.ToLower(catalogDescText(row.Desc)), q) {
 			filtered = append(filtered, row)
 		}
 	}
 	return filtered
 }
 
-func renderAPT(rows []searchRow) string {
-	var b strings.Builder
-	for _, row := range rows {
-		b.WriteString(row.Name)
-		b.WriteString("\t")
-		b.WriteString(row.Desc)
-		b.WriteString("\n")
-	}
-	return b.String()
-}
-
 func parseCachedRows(data []byte) []searchRow {
 	rows := make([]searchRow, 0)
 	for _, line := range splitLines(data) {
@@ -887,101 +302,3 @@ func parseCachedRows(data []byte) []searchRow {
 	}
 	return rows
 }
-
-func loadBrewCatalogRows(q string) ([]searchRow, error) {
-	cachePath := filepath.Join(cacheRootPath(), "catalog", "brew.tsv")
-	metaPath := filepath.Join(cacheRootPath(), "meta", "catalog", "brew.tsv.meta")
-	fingerprint := brewCatalogFingerprint()
-
-	if rawMeta, err := os.ReadFile(metaPath); err == nil {
-		meta := parseMetaMap(rawMeta)
-		if meta["fingerprint"] == fingerprint {
-			if raw, err := os.ReadFile(cachePath); err == nil {
-				rows := parseCachedRows(raw)
-				if len(rows) > 0 {
-					return filterBrewCatalog(rows, q), nil
-				}
-			}
-		}
-	}
-
-	rows, err := buildBrewCatalogRows()
-	if err != nil {
-		return nil, err
-	}
-	if len(rows) == 0 {
-		return nil, nil
-	}
-
-	_ = os.MkdirAll(filepath.Dir(cachePath), 0o755)
-	_ = os.MkdirAll(filepath.Dir(metaPath), 0o755)
-	_ = os.WriteFile(cachePath, []byte(renderAPT(rows)), 0o644)
-
-	now := time.Now()
-	meta := strings.Builder{}
-	meta.WriteString("format_version=1\n")
-	meta.WriteString("created_at=")
-	meta.WriteString(now.UTC().Format(time.RFC3339))
-	meta.WriteString("\n")
-	meta.WriteString("created_epoch=")
-	meta.WriteString(fmt.Sprintf("%d", now.Unix()))
-	meta.WriteString("\n")
-	meta.WriteString("fingerprint=")
-	meta.WriteString(fingerprint)
-	meta.WriteString("\n")
-	meta.WriteString("item_count=")
-	meta.WriteString(fmt.Sprintf("%d", len(rows)))
-	meta.WriteString("\n")
-	_ = os.WriteFile(metaPath, []byte(meta.String()), 0o644)
-
-	return filterBrewCatalog(rows, q), nil
-}
-
-func brewCatalogFingerprint() string {
-	cmdPath, _ := exec.LookPath("brew")
-	if cmdPath == "" {
-		cmdPath = "missing"
-	}
-	return fmt.Sprintf("1|brew|%s", cmdPath)
-}
-
-func buildBrewCatalogRows() ([]searchRow, error) {
-	rows := make([]searchRow, 0)
-	err := runLineStreamQuietErr("brew", []string{"formulae"}, func(line string) {
-		name := strings.TrimSpace(line)
-		if name == "" {
-			return
-		}
-		rows = append(rows, searchRow{Name: name, Desc: "-"})
-	})
-	if err != nil {
-		return nil, err
-	}
-
-	err = runLineStreamQuietErr("brew", []string{"casks"}, func(line string) {
-		name := strings.TrimSpace(line)
-		if name == "" {
-			return
-		}
-		rows = append(rows, searchRow{Name: name, Desc: "-"})
-	})
-	if err != nil {
-		return nil, err
-	}
-
-	return dedupeRows(rows), nil
-}
-
-func filterBrewCatalog(rows []searchRow, q string) []searchRow {
-	if q == "" {
-		return rows
-	}
-	qLower := strings.ToLower(q)
-	filtered := make([]searchRow, 0, len(rows))
-	for _, row := range rows {
-		if strings.Contains(strings.ToLower(row.Name), qLower) || strings.Contains(strings.ToLower(row.Desc), qLower) {
-			filtered = append(filtered, row)
-		}
-	}
-	return filtered
-}
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -83,28 +85,86 @@ func UpdateAppStream() error {
 }
 
 func loadBestCache() (*Cache, error) {
-	paths := FindCachePaths()
-
-	for _, path := range paths {
-		cache, err := loadFromFile(path)
-		if err == nil && len(cache.Apps) > 0 {
-			return cache, nil
-		}
+	if cache, err := loadSources(FindSources()); err == nil {
+		return cache, nil
 	}
 
 	_ = UpdateAppStream()
 
-	for _, path := range paths {
-		cache, err := loadFromFile(path)
-		if err == nil && len(cache.Apps) > 0 {
-			return cache, nil
+	return loadSources(FindSources())
+}
+
+// loadSources merges every readable source into one cache. Each app is
+// tagged with the remote it came from and kept once per remote: the first
+// architecture listing it wins, but the same app from another remote stays
+// a separate entry so its build can be picked. The first remote listing an
+// app is its primary one.
+func loadSources(sources []Source) (*Cache, error) {
+	cache := &Cache{LoadedAt: time.Now(), primary: make(map[string]string)}
+	seen := make(map[string]bool)
+	for _, source := range sources {
+		apps, err := loadFromFile(source.Path)
+		if err != nil {
+			continue
+		}
+		cache.Sources = append(cache.Sources, source)
+		for _, app := range apps {
+			key := app.ID + "\x00" + source.Remote
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			app.Origin = source.Remote
+			if _, ok := cache.primary[app.ID]; !ok {
+				cache.primary[app.ID] = source.Remote
+			}
+			resolveCachedIcons(&app, filepath.Dir(source.Path))
+			cache.Apps = append(cache.Apps, app)
 		}
 	}
+	if len(cache.Apps) == 0 {
+		return nil, ErrNoCache
+	}
+	return cache, nil
+}
 
-	return nil, ErrNoCache
+// resolveCachedIcons turns cached icon file names into paths under the
+// icons/<width>x<height> directory flatpak keeps next to the appstream file.
+func resolveCachedIcons(app *App, dir string) {
+	for i, icon := range app.Icons {
+		if icon.Type != "cached" || icon.Width <= 0 || filepath.IsAbs(icon.Value) {
+			continue
+		}
+		app.Icons[i].Value = filepath.Join(dir, "icons", fmt.Sprintf("%dx%d", icon.Width, icon.Height), icon.Value)
+	}
+}
+
+// LoadLocal merges the appstream files on disk as they are. Unlike LoadBest
+// it never runs `flatpak update --appstream`.
+func LoadLocal() (*Cache, error) {
+	return loadSources(FindSources())
+}
+
+// OriginOf returns the remote that provides ref, which is an app id or a
+// "remote:id" result name.
+func (c *Cache) OriginOf(ref string) (string, bool) {
+	app, ok := c.Lookup(ref)
+	if !ok || app.Origin == "" {
+		return "", false
+	}
+	return app.Origin, true
+}
+
+// SplitRef splits a result name into its remote and app id. Apps from their
+// primary remote are named by id alone, so remote is empty for them.
+func SplitRef(ref string) (remote, id string) {
+	if remote, id, ok := strings.Cut(ref, ":"); ok && remote != "" && id != "" {
+		return remote, id
+	}
+	return "", ref
 }
 
-func loadFromFile(path string) (*Cache, error) {
+func loadFromFile(path string) ([]App, error) {
 	info, err := os.Stat(path)
 	if err != nil {
 		return nil, err
@@ -115,6 +175,10 @@ func loadFromFile(path string) (*Cache, error) {
 		return nil, ErrCacheStale
 	}
 
+	if apps, ok := loadPreparsed(path, info); ok && len(apps) > 0 {
+		return apps, nil
+	}
+
 	apps, err := ParseAppStreamFile(path)
 	if err != nil {
 		return nil, err
@@ -124,14 +188,8 @@ func loadFromFile(path string) (*Cache, error) {
 		return nil, ErrParseFailed
 	}
 
-	origin := extractOriginFromPath(path)
-
-	return &Cache{
-		Apps:     apps,
-		LoadedAt: time.Now(),
-		Path:     path,
-		Origin:   origin,
-	}, nil
+	storePreparsed(path, info, apps)
+	return apps, nil
 }
 
 func ShouldRefreshStaleCache() bool {
@@ -139,53 +197,173 @@ func ShouldRefreshStaleCache() bool {
 	return val != "0" && val != "false" && val != "no" && val != "off"
 }
 
-func extractOriginFromPath(path string) string {
-	parts := strings.Split(path, "/flatpak/appstream/")
-	if len(parts) < 2 {
-		return "unknown"
-	}
-	remaining := parts[1]
-	if idx := strings.Index(remaining, "/"); idx > 0 {
-		return remaining[:idx]
+// Lookup finds the app named by ref: an id, which resolves to the app from
+// its primary remote, or "remote:id" for the copy another remote offers.
+func (c *Cache) Lookup(ref string) (App, bool) {
+	remote, id := SplitRef(ref)
+	for _, app := range c.Apps {
+		if app.ID == id && (remote == "" || app.Origin == remote) {
+			return app, true
+		}
 	}
-	return remaining
+	return App{}, false
 }
 
+// Match tiers reported in SearchResult.Score, best first.
+const (
+	MatchExact       = iota + 1 // the name, id or last id segment equals the query
+	MatchPrefix                 // the name, id or last id segment starts with the query
+	MatchToken                  // every term occurs in the name or id
+	MatchKeyword                // some term only matched a keyword
+	MatchSummary                // some term only matched the summary
+	MatchDescription            // some term only matched the description
+)
+
+// Filter returns the apps matching query, best matches first. Every plain
+// term has to match the name, id, keywords, summary or description, and an
+// app ranks by the weakest field any term needed. "category:<name>" terms
+// keep only apps in that appstream category, e.g. "category:game chess".
 func (c *Cache) Filter(query string) []SearchResult {
-	if query == "" {
-		rows := make([]SearchResult, 0, len(c.Apps))
-		for _, app := range c.Apps {
-			rows = append(rows, SearchResult{
-				Name: flatpakResultName(app),
-				Desc: app.Summary,
-			})
-		}
-		return rows
-	}
+	text, categories := splitCategoryTerms(query)
+	terms := strings.Fields(strings.ToLower(text))
 
-	query = strings.ToLower(query)
-	rows := make([]SearchResult, 0)
+	type match struct {
+		result SearchResult
+		weight int
+	}
+	matches := make([]match, 0)
 
 	for _, app := range c.Apps {
-		name := strings.ToLower(app.Name)
-		summary := strings.ToLower(app.Summary)
-		desc := strings.ToLower(app.Description)
-		id := strings.ToLower(app.ID)
-
-		if strings.Contains(name, query) ||
-			strings.Contains(id, query) ||
-			strings.Contains(summary, query) ||
-			strings.Contains(desc, query) {
-			rows = append(rows, SearchResult{
-				Name: flatpakResultName(app),
-				Desc: app.Summary,
-			})
+		if !app.inCategories(categories) {
+			continue
 		}
+		score, weight := 0, 0
+		if len(terms) > 0 {
+			var ok bool
+			if score, weight, ok = app.score(terms); !ok {
+				continue
+			}
+		}
+		matches = append(matches, match{
+			result: SearchResult{Name: c.resultName(app), Desc: resultDesc(app), Score: score},
+			weight: weight,
+		})
 	}
 
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].result.Score != matches[j].result.Score {
+			return matches[i].result.Score < matches[j].result.Score
+		}
+		return matches[i].weight < matches[j].weight
+	})
+
+	rows := make([]SearchResult, 0, len(matches))
+	for _, m := range matches {
+		rows = append(rows, m.result)
+	}
 	return rows
 }
 
+// score ranks the app against lowercased query terms. weight sums the tier
+// of each term and breaks ties between apps of the same tier. ok is false
+// when some term matches nothing.
+func (app App) score(terms []string) (score, weight int, ok bool) {
+	name := strings.ToLower(app.Name)
+	id := strings.ToLower(app.ID)
+	short := id[strings.LastIndex(id, ".")+1:]
+
+	for _, term := range terms {
+		tier := app.termTier(term, name, id)
+		if tier == 0 {
+			return 0, 0, false
+		}
+		weight += tier
+		if tier > score {
+			score = tier
+		}
+	}
+
+	phrase := strings.Join(terms, " ")
+	switch {
+	case phrase == name || phrase == id || phrase == short:
+		score = MatchExact
+	case strings.HasPrefix(name, phrase) || strings.HasPrefix(id, phrase) || strings.HasPrefix(short, phrase):
+		score = MatchPrefix
+	}
+	return score, weight, true
+}
+
+// termTier reports the best field a single term matches, or 0.
+func (app App) termTier(term, name, id string) int {
+	if strings.Contains(name, term) || strings.Contains(id, term) {
+		return MatchToken
+	}
+	for _, keyword := range app.Keywords {
+		if strings.Contains(strings.ToLower(keyword), term) {
+			return MatchKeyword
+		}
+	}
+	if strings.Contains(strings.ToLower(app.Summary), term) {
+		return MatchSummary
+	}
+	if strings.Contains(strings.ToLower(app.Description), term) {
+		return MatchDescription
+	}
+	return 0
+}
+
+// splitCategoryTerms separates "category:" terms from the rest of query.
+func splitCategoryTerms(query string) (string, []string) {
+	var text, categories []string
+	for _, term := range strings.Fields(query) {
+		if category, ok := strings.CutPrefix(strings.ToLower(term), "category:"); ok {
+			if category != "" {
+				categories = append(categories, category)
+			}
+			continue
+		}
+		text = append(text, term)
+	}
+	return strings.Join(text, " "), categories
+}
+
+// InCategory reports whether the app lists category, ignoring case.
+func (app App) InCategory(category string) bool {
+	for _, have := range app.Categories {
+		if strings.EqualFold(have, category) {
+			return true
+		}
+	}
+	return false
+}
+
+func (app App) inCategories(categories []string) bool {
+	for _, category := range categories {
+		if !app.InCategory(category) {
+			return false
+		}
+	}
+	return true
+}
+
+// resultName names an app by its id, prefixed with "remote:" when the app
+// comes from a remote other than its primary one.
+func (c *Cache) resultName(app App) string {
+	name := flatpakResultName(app)
+	if primary, ok := c.primary[app.ID]; ok && app.Origin != "" && app.Origin != primary {
+		return app.Origin + ":" + name
+	}
+	return name
+}
+
+// resultDesc tags the summary with the remote Install will use.
+func resultDesc(app App) string {
+	if app.Origin == "" {
+		return app.Summary
+	}
+	return strings.TrimSpace("[" + app.Origin + "] " + app.Summary)
+}
+
 func flatpakResultName(app App) string {
 	id := strings.TrimSpace(app.ID)
 	if id != "" {
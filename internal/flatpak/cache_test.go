@@ -1,6 +1,12 @@
 package flatpak
 
-import "testing"
+import (
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
 
 func TestCacheFilterUsesAppIDAsResultName(t *testing.T) {
 	cache := &Cache{Apps: []App{{
@@ -33,3 +39,205 @@ func TestCacheFilterFallsBackToDisplayNameWithoutID(t *testing.T) {
 		t.Fatalf("row name = %q, want fallback app name", rows[0].Name)
 	}
 }
+
+func appstreamXMLFor(ids ...string) string {
+	doc := `<?xml version="1.0" encoding="UTF-8"?>` + "\n" + `<components version="0.8" origin="flatpak">`
+	for _, id := range ids {
+		doc += `<component type="desktop-application"><id>` + id + `</id><name>` + id + `</name><summary>App ` + id + `</summary></component>`
+	}
+	return doc + "</components>\n"
+}
+
+func writeAppStream(t *testing.T, path, doc string, compress bool) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	if !compress {
+		if _, err := file.WriteString(doc); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	gz := gzip.NewWriter(file)
+	if _, err := gz.Write([]byte(doc)); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFindSourcesCoversEveryRemoteAndArch(t *testing.T) {
+	user := t.TempDir()
+	system := t.TempDir()
+	t.Setenv("FLATPAK_USER_DIR", user)
+	t.Setenv("FLATPAK_SYSTEM_DIR", system)
+
+	native := NativeArch()
+	writeAppStream(t, filepath.Join(user, "appstream", "gnome-nightly", native, "active", "appstream.xml.gz"), appstreamXMLFor("org.gnome.Nightly"), true)
+	writeAppStream(t, filepath.Join(system, "appstream", "flathub", "zz-other", "active", "appstream.xml"), appstreamXMLFor("org.example.Other"), false)
+	writeAppStream(t, filepath.Join(system, "appstream", "flathub", native, "active", "appstream.xml.gz"), appstreamXMLFor("org.example.App"), true)
+	writeAppStream(t, filepath.Join(system, "appstream", "fedora", "appstream.xml.gz"), appstreamXMLFor("org.fedoraproject.App"), true)
+	if err := os.WriteFile(filepath.Join(system, "appstream", "flathub", ".timestamp"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, source := range FindSources() {
+		got = append(got, source.Installation+":"+source.Remote+":"+source.Arch)
+	}
+	want := []string{
+		"user:gnome-nightly:" + native,
+		"system:fedora:",
+		"system:flathub:" + native,
+		"system:flathub:zz-other",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("sources = %v, want %v", got, want)
+	}
+}
+
+func TestLoadSourcesKeepsOneAppPerRemote(t *testing.T) {
+	dir := t.TempDir()
+	flathub := filepath.Join(dir, "flathub.xml")
+	flathubArm := filepath.Join(dir, "flathub-aarch64.xml")
+	fedora := filepath.Join(dir, "fedora.xml.gz")
+	writeAppStream(t, flathub, appstreamXMLFor("org.example.App", "org.example.Shared"), false)
+	writeAppStream(t, flathubArm, appstreamXMLFor("org.example.Shared"), false)
+	writeAppStream(t, fedora, appstreamXMLFor("org.example.Shared", "org.fedoraproject.Only"), true)
+
+	cache, err := loadSources([]Source{
+		{Path: filepath.Join(dir, "missing.xml"), Remote: "broken"},
+		{Path: flathub, Remote: "flathub", Arch: "x86_64"},
+		{Path: flathubArm, Remote: "flathub", Arch: "aarch64"},
+		{Path: fedora, Remote: "fedora", Arch: "x86_64"},
+	})
+	if err != nil {
+		t.Fatalf("loadSources: %v", err)
+	}
+	got := make([]string, 0, len(cache.Apps))
+	for _, app := range cache.Apps {
+		got = append(got, app.Origin+"/"+app.ID)
+	}
+	want := []string{"flathub/org.example.App", "flathub/org.example.Shared", "fedora/org.example.Shared", "fedora/org.fedoraproject.Only"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("apps = %v, want %v", got, want)
+	}
+	if len(cache.Sources) != 3 {
+		t.Fatalf("sources = %+v, want the three readable files", cache.Sources)
+	}
+
+	rows := cache.Filter("shared")
+	wantRows := []SearchResult{
+		{Name: "org.example.Shared", Desc: "[flathub] App org.example.Shared", Score: MatchExact},
+		{Name: "fedora:org.example.Shared", Desc: "[fedora] App org.example.Shared", Score: MatchExact},
+	}
+	if !reflect.DeepEqual(rows, wantRows) {
+		t.Fatalf("Filter = %+v, want %+v", rows, wantRows)
+	}
+
+	for ref, origin := range map[string]string{
+		"org.example.Shared":        "flathub",
+		"fedora:org.example.Shared": "fedora",
+		"org.fedoraproject.Only":    "fedora",
+	} {
+		if app, ok := cache.Lookup(ref); !ok || app.Origin != origin {
+			t.Fatalf("Lookup(%q) = %+v, %v, want origin %s", ref, app, ok, origin)
+		}
+		if remote, ok := cache.OriginOf(ref); !ok || remote != origin {
+			t.Fatalf("OriginOf(%q) = %q, %v, want %s", ref, remote, ok, origin)
+		}
+	}
+	if _, ok := cache.Lookup("flathub:org.fedoraproject.Only"); ok {
+		t.Fatal("expected no flathub copy of a fedora-only app")
+	}
+	if remote, id := SplitRef("fedora:org.example.Shared"); remote != "fedora" || id != "org.example.Shared" {
+		t.Fatalf("SplitRef = %q, %q", remote, id)
+	}
+}
+
+func TestLoadSourcesWithoutAppsFails(t *testing.T) {
+	if _, err := loadSources(nil); err != ErrNoCache {
+		t.Fatalf("err = %v, want ErrNoCache", err)
+	}
+}
+
+func TestResolveCachedIcons(t *testing.T) {
+	app := App{Icons: []Icon{
+		{Type: "cached", Value: "org.example.App.png", Width: 64, Height: 64},
+		{Type: "remote", Value: "https://example.org/icon.png", Width: 128, Height: 128},
+		{Type: "stock", Value: "accessories-text-editor"},
+	}}
+	resolveCachedIcons(&app, "/appstream/flathub/x86_64/active")
+
+	want := []string{
+		"/appstream/flathub/x86_64/active/icons/64x64/org.example.App.png",
+		"https://example.org/icon.png",
+		"accessories-text-editor",
+	}
+	for i, icon := range app.Icons {
+		if icon.Value != want[i] {
+			t.Fatalf("icon %d = %q, want %q", i, icon.Value, want[i])
+		}
+	}
+}
+
+func TestCacheFilterRanksByMatchTier(t *testing.T) {
+	cache := &Cache{Apps: []App{
+		{ID: "org.example.Notes", Name: "Notes", Summary: "Write things down", Description: "A chess-free notebook."},
+		{ID: "org.example.Board", Name: "Board", Summary: "Board games", Keywords: []string{"chess", "checkers"}},
+		{ID: "org.example.Trainer", Name: "Trainer", Summary: "Learn chess openings"},
+		{ID: "org.example.KnightChessTrainer", Name: "Knight Trainer", Summary: "Practice"},
+		{ID: "org.example.ChessClock", Name: "Chess Clock", Summary: "Time games"},
+		{ID: "org.gnome.Chess", Name: "Chess", Summary: "Play chess"},
+	}}
+
+	rows := cache.Filter("Chess")
+	var got []string
+	var scores []int
+	for _, row := range rows {
+		got = append(got, row.Name)
+		scores = append(scores, row.Score)
+	}
+	wantNames := []string{
+		"org.gnome.Chess",
+		"org.example.ChessClock",
+		"org.example.KnightChessTrainer",
+		"org.example.Board",
+		"org.example.Trainer",
+		"org.example.Notes",
+	}
+	wantScores := []int{MatchExact, MatchPrefix, MatchToken, MatchKeyword, MatchSummary, MatchDescription}
+	if !reflect.DeepEqual(got, wantNames) || !reflect.DeepEqual(scores, wantScores) {
+		t.Fatalf("Filter = %v %v, want %v %v", got, scores, wantNames, wantScores)
+	}
+}
+
+func TestCacheFilterRequiresEveryTerm(t *testing.T) {
+	cache := &Cache{Apps: []App{
+		{ID: "org.gnome.Chess", Name: "Chess", Summary: "Play the board game"},
+		{ID: "org.example.Go", Name: "Go", Summary: "Play the board game Go"},
+		{ID: "org.example.Clock", Name: "Chess Clock", Summary: "Time games"},
+	}}
+
+	rows := cache.Filter("chess board")
+	if len(rows) != 1 || rows[0].Name != "org.gnome.Chess" {
+		t.Fatalf("Filter = %+v, want only the app matching both terms", rows)
+	}
+	if rows[0].Score != MatchSummary {
+		t.Fatalf("score = %d, want the weakest term's tier", rows[0].Score)
+	}
+
+	if rows := cache.Filter("chess clock"); len(rows) != 1 || rows[0].Score != MatchExact {
+		t.Fatalf("phrase equal to the name = %+v, want an exact match", rows)
+	}
+	if rows := cache.Filter(""); len(rows) != 3 || rows[0].Score != 0 {
+		t.Fatalf("empty query = %+v, want every app unscored", rows)
+	}
+}
@@ -0,0 +1,180 @@
+package flatpak
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Installation scopes, matching flatpak's --user and --system flags.
+const (
+	ScopeUser   = "user"
+	ScopeSystem = "system"
+)
+
+// InstalledApp is an app deployed in a Flatpak installation.
+type InstalledApp struct {
+	ID      string
+	Branch  string
+	Version string
+	Origin  string
+	Scope   string
+}
+
+// Installation is a Flatpak installation directory and its scope.
+type Installation struct {
+	Scope string
+	Dir   string
+}
+
+// Installations lists the user installation, then the system one.
+func Installations() []Installation {
+	installations := make([]Installation, 0, 2)
+	if dir := UserInstallationDir(); dir != "" {
+		installations = append(installations, Installation{Scope: ScopeUser, Dir: dir})
+	}
+	return append(installations, Installation{Scope: ScopeSystem, Dir: SystemInstallationDir()})
+}
+
+// InUse reports whether the installation has any app or runtime deployed.
+func (inst Installation) InUse() bool {
+	return len(subdirs(filepath.Join(inst.Dir, "app"))) > 0 || len(subdirs(filepath.Join(inst.Dir, "runtime"))) > 0
+}
+
+// ListInstalled reads the apps deployed under <installation>/app in every
+// installation, user apps first. ok is false when no installation has an app
+// directory to read, so callers can fall back to `flatpak list`.
+func ListInstalled() (apps []InstalledApp, ok bool) {
+	for _, inst := range Installations() {
+		appDir := filepath.Join(inst.Dir, "app")
+		if info, err := os.Stat(appDir); err != nil || !info.IsDir() {
+			continue
+		}
+		ok = true
+		for _, id := range subdirs(appDir) {
+			if app, found := readInstalledApp(filepath.Join(appDir, id), id, inst.Scope); found {
+				apps = append(apps, app)
+			}
+		}
+	}
+	return apps, ok
+}
+
+// readInstalledApp reads the active deploy of one app. The "current" link
+// names the arch/branch in use; without it the first deployed branch is
+// taken.
+func readInstalledApp(dir, id, scope string) (InstalledApp, bool) {
+	arch, branch := "", ""
+	if target, err := os.Readlink(filepath.Join(dir, "current")); err == nil {
+		arch, branch, _ = strings.Cut(filepath.ToSlash(target), "/")
+	}
+	if arch == "" || branch == "" || !isDir(filepath.Join(dir, arch, branch, "active")) {
+		arch, branch = "", ""
+		for _, candidateArch := range subdirs(dir) {
+			for _, candidateBranch := range subdirs(filepath.Join(dir, candidateArch)) {
+				if branch == "" && isDir(filepath.Join(dir, candidateArch, candidateBranch, "active")) {
+					arch, branch = candidateArch, candidateBranch
+				}
+			}
+		}
+		if branch == "" {
+			return InstalledApp{}, false
+		}
+	}
+
+	active := filepath.Join(dir, arch, branch, "active")
+	app := InstalledApp{
+		ID:      id,
+		Branch:  branch,
+		Scope:   scope,
+		Origin:  readDeployOrigin(filepath.Join(active, "deploy")),
+		Version: readDeployVersion(active, id),
+	}
+	if group, ok := readKeyFileGroup(filepath.Join(active, "metadata"), "Application"); ok {
+		if name := group["name"]; name != "" {
+			app.ID = name
+		}
+	}
+	return app, true
+}
+
+// readDeployOrigin returns the remote recorded in a deploy file. The file is
+// a GVariant of type (ssasta{sv}) whose first member, the origin, is stored
+// as a NUL-terminated string at offset zero.
+func readDeployOrigin(path string) string {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	origin, _, ok := bytes.Cut(raw, []byte{0})
+	if !ok || len(origin) == 0 || len(origin) > 255 {
+		return ""
+	}
+	for _, c := range origin {
+		if c < 0x20 || c > 0x7e {
+			return ""
+		}
+	}
+	return string(origin)
+}
+
+// readDeployVersion takes the newest release from the metainfo file the app
+// ships in its deploy.
+func readDeployVersion(active, id string) string {
+	for _, rel := range []string{
+		"files/share/metainfo/" + id + ".metainfo.xml",
+		"files/share/metainfo/" + id + ".appdata.xml",
+		"files/share/appdata/" + id + ".appdata.xml",
+	} {
+		raw, err := os.ReadFile(filepath.Join(active, filepath.FromSlash(rel)))
+		if err != nil {
+			continue
+		}
+		var comp componentXML
+		if err := xml.Unmarshal(raw, &comp); err != nil {
+			continue
+		}
+		return comp.toApp("").Version
+	}
+	return ""
+}
+
+// readKeyFileGroup reads one [group] of a GKeyFile such as a deploy's
+// metadata file.
+func readKeyFileGroup(path, group string) (map[string]string, bool) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, false
+	}
+	defer file.Close()
+
+	values := make(map[string]string)
+	found, inGroup := false, false
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			inGroup = line[1:len(line)-1] == group
+			found = found || inGroup
+			continue
+		}
+		if !inGroup {
+			continue
+		}
+		if key, value, ok := strings.Cut(line, "="); ok {
+			values[strings.TrimSpace(key)] = strings.TrimSpace(value)
+		}
+	}
+	return values, found
+}
+
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
@@ -0,0 +1,99 @@
+package flatpak
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// deployApp lays out an app the way flatpak deploys it:
+// app/<id>/<arch>/<branch>/active with deploy, metadata and metainfo files.
+func deployApp(t *testing.T, installation, id, arch, branch, origin, version string, current bool) {
+	t.Helper()
+	appDir := filepath.Join(installation, "app", id)
+	commit := filepath.Join(appDir, arch, branch, "0123abcd")
+	files := map[string]string{
+		"deploy":   origin + "\x00" + "0123abcd\x00\x00\x00",
+		"metadata": "[Application]\nname=" + id + "\nruntime=org.gnome.Platform/" + arch + "/46\ncommand=app\n",
+	}
+	if version != "" {
+		files["files/share/metainfo/"+id+".metainfo.xml"] = `<?xml version="1.0"?>
+<component type="desktop-application"><id>` + id + `</id><releases><release version="` + version + `" date="2024-04-01"/><release version="0.9"/></releases></component>`
+	}
+	for name, content := range files {
+		path := filepath.Join(commit, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Symlink("0123abcd", filepath.Join(appDir, arch, branch, "active")); err != nil {
+		t.Fatal(err)
+	}
+	if current {
+		if err := os.Symlink(arch+"/"+branch, filepath.Join(appDir, "current")); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestListInstalledReadsBothInstallations(t *testing.T) {
+	user := t.TempDir()
+	system := t.TempDir()
+	t.Setenv("FLATPAK_USER_DIR", user)
+	t.Setenv("FLATPAK_SYSTEM_DIR", system)
+
+	deployApp(t, user, "org.example.Editor", "x86_64", "stable", "flathub", "2.1", true)
+	deployApp(t, system, "org.gnome.Chess", "x86_64", "stable", "fedora", "46.0", false)
+	deployApp(t, system, "org.example.Editor", "aarch64", "beta", "flathub-beta", "", true)
+	if err := os.MkdirAll(filepath.Join(system, "app", "org.example.Broken", "x86_64", "stable"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	apps, ok := ListInstalled()
+	if !ok {
+		t.Fatal("expected the installation directories to be readable")
+	}
+	want := []InstalledApp{
+		{ID: "org.example.Editor", Branch: "stable", Version: "2.1", Origin: "flathub", Scope: ScopeUser},
+		{ID: "org.example.Editor", Branch: "beta", Origin: "flathub-beta", Scope: ScopeSystem},
+		{ID: "org.gnome.Chess", Branch: "stable", Version: "46.0", Origin: "fedora", Scope: ScopeSystem},
+	}
+	if !reflect.DeepEqual(apps, want) {
+		t.Fatalf("ListInstalled =\n%+v\nwant\n%+v", apps, want)
+	}
+
+	for _, inst := range Installations() {
+		if !inst.InUse() {
+			t.Fatalf("%s installation should be in use", inst.Scope)
+		}
+	}
+}
+
+func TestListInstalledWithoutInstallations(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("FLATPAK_USER_DIR", filepath.Join(dir, "user"))
+	t.Setenv("FLATPAK_SYSTEM_DIR", filepath.Join(dir, "system"))
+
+	if apps, ok := ListInstalled(); ok || len(apps) != 0 {
+		t.Fatalf("ListInstalled = %v, %v; want a fallback signal", apps, ok)
+	}
+	for _, inst := range Installations() {
+		if inst.InUse() {
+			t.Fatalf("%s installation should not be in use", inst.Scope)
+		}
+	}
+}
+
+func TestReadDeployOriginRejectsGarbage(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "deploy")
+	if err := os.WriteFile(path, []byte("\x01\x02binary"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if origin := readDeployOrigin(path); origin != "" {
+		t.Fatalf("origin = %q, want none", origin)
+	}
+}
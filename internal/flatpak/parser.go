@@ -1,30 +1,94 @@
 package flatpak
 
 import (
+	"bufio"
 	"compress/gzip"
 	"encoding/xml"
+	"fmt"
 	"io"
 	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 )
 
-// appstreamXML represents the root of the Flatpak appstream XML document.
-type appstreamXML struct {
-	XMLName    xml.Name       `xml:"components"`
-	Origin     string         `xml:"origin,attr"`
-	Components []componentXML `xml:"component"`
-}
-
 // componentXML represents a single application in the appstream.
+// Translatable elements repeat once per language; the untranslated one is
+// used.
 type componentXML struct {
-	Type        string      `xml:"type,attr"`
-	ID          string      `xml:"id"`
-	Name        string      `xml:"name"`
-	Summary     string      `xml:"summary"`
-	Description string      `xml:"description"`
-	Metadata    metadataXML `xml:"metadata"`
-	Version     string      `xml:"releases>release>version"`
+	Type           string             `xml:"type,attr"`
+	ID             string             `xml:"id"`
+	Names          []localizedXML     `xml:"name"`
+	Summaries      []localizedXML     `xml:"summary"`
+	Descriptions   []descriptionXML   `xml:"description"`
+	DeveloperNames []localizedXML     `xml:"developer_name"`
+	Developer      developerXML       `xml:"developer"`
+	ProjectLicense string             `xml:"project_license"`
+	Categories     []string           `xml:"categories>category"`
+	Keywords       []keywordsXML      `xml:"keywords"`
+	URLs           []urlXML           `xml:"url"`
+	ContentRating  []contentRatingXML `xml:"content_rating"`
+	Releases       []releaseXML       `xml:"releases>release"`
+	Icons          []iconXML          `xml:"icon"`
+	Metadata       metadataXML        `xml:"metadata"`
+}
+
+// localizedXML is a translatable text element.
+type localizedXML struct {
+	Lang  string `xml:"http://www.w3.org/XML/1998/namespace lang,attr"`
+	Value string `xml:",chardata"`
+}
+
+// descriptionXML keeps the markup of a description so its first paragraph
+// can be extracted.
+type descriptionXML struct {
+	Lang  string `xml:"http://www.w3.org/XML/1998/namespace lang,attr"`
+	Inner string `xml:",innerxml"`
+}
+
+// developerXML is the AppStream 1.0 <developer> element, which replaced
+// <developer_name>.
+type developerXML struct {
+	Names []localizedXML `xml:"name"`
+}
+
+// keywordsXML is a <keywords> block. Older catalogs translate the whole
+// block, newer ones each keyword.
+type keywordsXML struct {
+	Lang     string         `xml:"http://www.w3.org/XML/1998/namespace lang,attr"`
+	Keywords []localizedXML `xml:"keyword"`
+}
+
+// urlXML is a typed link such as homepage or bugtracker.
+type urlXML struct {
+	Type  string `xml:"type,attr"`
+	Value string `xml:",chardata"`
+}
+
+// contentRatingXML is an OARS rating: one level per attribute id.
+type contentRatingXML struct {
+	Attributes []struct {
+		ID    string `xml:"id,attr"`
+		Value string `xml:",chardata"`
+	} `xml:"content_attribute"`
+}
+
+// releaseXML is one release; appstream lists the newest first.
+type releaseXML struct {
+	Version   string `xml:"version,attr"`
+	Timestamp string `xml:"timestamp,attr"`
+	Date      string `xml:"date,attr"`
+}
+
+// iconXML is a cached, remote or stock icon.
+type iconXML struct {
+	Type   string `xml:"type,attr"`
+	Width  int    `xml:"width,attr"`
+	Height int    `xml:"height,attr"`
+	Value  string `xml:",chardata"`
 }
 
 // metadataXML holds additional key-value metadata.
@@ -46,74 +110,216 @@ func ParseAppStreamFile(path string) ([]App, error) {
 	}
 	defer file.Close()
 
-	var reader io.Reader = file
+	// Gzip files start with magic bytes 0x1f 0x8b
+	buffered := bufio.NewReader(file)
+	var reader io.Reader = buffered
+	if header, err := buffered.Peek(2); err == nil && header[0] == 0x1f && header[1] == 0x8b {
+		gzReader, err := gzip.NewReader(buffered)
+		if err != nil {
+			return nil, err
+		}
+		defer gzReader.Close()
+		reader = gzReader
+	}
 
-	// Check if file is gzip compressed by reading the magic bytes
-	info, err := file.Stat()
+	return ParseAppStream(reader)
+}
+
+// ParseAppStream parses Flatpak appstream XML from any reader.
+func ParseAppStream(reader io.Reader) ([]App, error) {
+	apps := make([]App, 0)
+	err := ScanAppStream(reader, func(app App) {
+		apps = append(apps, app)
+	})
 	if err != nil {
 		return nil, err
 	}
+	return apps, nil
+}
 
-	if info.Size() > 2 {
-		header := make([]byte, 2)
-		if _, err := file.Read(header); err != nil {
-			return nil, err
+// ScanAppStream streams the desktop applications of an appstream document
+// to emit, decoding one <component> at a time. Other component types are
+// skipped without being decoded, so memory stays bounded by the largest
+// component rather than the whole catalog.
+func ScanAppStream(reader io.Reader, emit func(App)) error {
+	decoder := xml.NewDecoder(reader)
+	origin := ""
+	sawRoot := false
+
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
 		}
-		// Gzip files start with magic bytes 0x1f 0x8b
-		if header[0] == 0x1f && header[1] == 0x8b {
-			file.Seek(0, os.SEEK_SET)
-			gzReader, err := gzip.NewReader(file)
-			if err != nil {
-				return nil, err
+
+		switch {
+		case !sawRoot:
+			if start.Name.Local != "components" {
+				return fmt.Errorf("appstream: unexpected root element <%s>", start.Name.Local)
+			}
+			sawRoot = true
+			origin = xmlAttr(start, "origin")
+		case start.Name.Local != "component" || xmlAttr(start, "type") != "desktop-application":
+			if err := decoder.Skip(); err != nil {
+				return err
+			}
+		default:
+			var comp componentXML
+			if err := decoder.DecodeElement(&comp, &start); err != nil {
+				return err
 			}
-			defer gzReader.Close()
-			reader = gzReader
+			if comp.ID == "" {
+				continue
+			}
+
+			// Extract origin from metadata if not set on root
+			appOrigin := origin
+			for _, v := range comp.Metadata.Values {
+				if v.Key == "flatpak::origin" {
+					appOrigin = v.Value
+					break
+				}
+			}
+
+			emit(comp.toApp(appOrigin))
 		}
 	}
 
-	return ParseAppStream(reader)
+	if !sawRoot {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
 }
 
-// ParseAppStream parses Flatpak appstream XML from any reader.
-func ParseAppStream(reader io.Reader) ([]App, error) {
-	decoder := xml.NewDecoder(reader)
-	var appstream appstreamXML
-	if err := decoder.Decode(&appstream); err != nil {
-		return nil, err
+func xmlAttr(start xml.StartElement, name string) string {
+	for _, attr := range start.Attr {
+		if attr.Name.Local == name {
+			return attr.Value
+		}
+	}
+	return ""
+}
+
+func (comp componentXML) toApp(origin string) App {
+	app := App{
+		ID:            strings.TrimSpace(comp.ID),
+		Name:          untranslated(comp.Names),
+		Summary:       untranslated(comp.Summaries),
+		Origin:        origin,
+		Developer:     untranslated(comp.Developer.Names),
+		License:       strings.TrimSpace(comp.ProjectLicense),
+		ContentRating: comp.contentRating(),
+	}
+	if app.Developer == "" {
+		app.Developer = untranslated(comp.DeveloperNames)
 	}
 
-	apps := make([]App, 0, len(appstream.Components))
-	for _, comp := range appstream.Components {
-		if comp.Type != "desktop-application" {
-			continue
+	for _, desc := range comp.Descriptions {
+		if desc.Lang == "" {
+			// Clean up description - take first paragraph
+			app.Description = cleanDescription(desc.Inner)
+			break
 		}
-		if comp.ID == "" {
+	}
+
+	for _, category := range comp.Categories {
+		if category = strings.TrimSpace(category); category != "" {
+			app.Categories = append(app.Categories, category)
+		}
+	}
+
+	for _, block := range comp.Keywords {
+		if block.Lang != "" {
 			continue
 		}
+		for _, keyword := range block.Keywords {
+			if value := strings.TrimSpace(keyword.Value); keyword.Lang == "" && value != "" {
+				app.Keywords = append(app.Keywords, value)
+			}
+		}
+	}
 
-		// Extract origin from metadata if not set on root
-		origin := appstream.Origin
-		for _, v := range comp.Metadata.Values {
-			if v.Key == "flatpak::origin" {
-				origin = v.Value
-				break
+	for _, link := range comp.URLs {
+		value := strings.TrimSpace(link.Value)
+		switch link.Type {
+		case "homepage":
+			if app.Homepage == "" {
+				app.Homepage = value
+			}
+		case "bugtracker":
+			if app.BugTracker == "" {
+				app.BugTracker = value
 			}
 		}
+	}
 
-		// Clean up description - take first paragraph
-		desc := cleanDescription(comp.Description)
+	for _, release := range comp.Releases {
+		if release.Version == "" {
+			continue
+		}
+		app.Releases = append(app.Releases, Release{Version: release.Version, Date: release.date()})
+	}
+	if len(app.Releases) > 0 {
+		app.Version = app.Releases[0].Version
+	}
 
-		apps = append(apps, App{
-			ID:          comp.ID,
-			Name:        comp.Name,
-			Summary:     comp.Summary,
-			Description: desc,
-			Version:     comp.Version,
-			Origin:      origin,
-		})
+	for _, icon := range comp.Icons {
+		if value := strings.TrimSpace(icon.Value); value != "" {
+			app.Icons = append(app.Icons, Icon{Type: icon.Type, Value: value, Width: icon.Width, Height: icon.Height})
+		}
 	}
 
-	return apps, nil
+	return app
+}
+
+// contentRating keeps the OARS attributes rated above "none".
+func (comp componentXML) contentRating() map[string]string {
+	var rating map[string]string
+	for _, block := range comp.ContentRating {
+		for _, attr := range block.Attributes {
+			level := strings.TrimSpace(attr.Value)
+			if attr.ID == "" || level == "" || level == "none" {
+				continue
+			}
+			if rating == nil {
+				rating = make(map[string]string)
+			}
+			rating[attr.ID] = level
+		}
+	}
+	return rating
+}
+
+// date renders the release date as YYYY-MM-DD, preferring the timestamp.
+func (r releaseXML) date() string {
+	if secs, err := strconv.ParseInt(r.Timestamp, 10, 64); err == nil && secs > 0 {
+		return time.Unix(secs, 0).UTC().Format("2006-01-02")
+	}
+	if len(r.Date) >= 10 {
+		return r.Date[:10]
+	}
+	return r.Date
+}
+
+// untranslated returns the value without an xml:lang attribute, falling back
+// to the first one.
+func untranslated(values []localizedXML) string {
+	for _, value := range values {
+		if value.Lang == "" {
+			return strings.Join(strings.Fields(value.Value), " ")
+		}
+	}
+	if len(values) > 0 {
+		return strings.Join(strings.Fields(values[0].Value), " ")
+	}
+	return ""
 }
 
 // cleanDescription extracts a clean single-line description from the XML content.
@@ -165,49 +371,132 @@ func stripTags(s string) string {
 	return result.String()
 }
 
-// FindCachePaths returns all possible Flatpak appstream cache locations.
-// Flatpak stores appstream metadata in:
-// - System: /var/lib/flatpak/appstream/<remote>/x86_64/../appstream.xml.gz
-// - User: ~/.local/share/flatpak/appstream/<remote>/../appstream.xml.gz
-func FindCachePaths() []string {
-	home := os.Getenv("HOME")
+// UserInstallationDir is the per-user Flatpak installation. Like flatpak
+// itself, FLATPAK_USER_DIR overrides it.
+func UserInstallationDir() string {
+	if dir := os.Getenv("FLATPAK_USER_DIR"); dir != "" {
+		return dir
+	}
 	xdgData := os.Getenv("XDG_DATA_HOME")
-	if xdgData == "" && home != "" {
-		xdgData = home + "/.local/share"
-	}
-
-	var paths []string
-
-	// User-level cache paths
-	if xdgData != "" {
-		userBase := xdgData + "/flatpak/appstream"
-		paths = append(paths,
-			userBase+"/flathub/x86_64/appstream.xml.gz",
-			userBase+"/flathub/i386/appstream.xml.gz",
-			userBase+"/flathub/aarch64/appstream.xml.gz",
-			userBase+"/flathub/armhf/appstream.xml.gz",
-			userBase+"/flathub/appstream.xml.gz",
-		)
-		// Also check for unpacked directories
-		paths = append(paths,
-			userBase+"/flathub/x86_64/appstream.xml",
-			userBase+"/flathub/appstream.xml",
-		)
-	}
-
-	// System-level cache paths (common on Linux)
-	paths = append(paths,
-		"/var/lib/flatpak/appstream/flathub/x86_64/appstream.xml.gz",
-		"/var/lib/flatpak/appstream/flathub/i386/appstream.xml.gz",
-		"/var/lib/flatpak/appstream/flathub/aarch64/appstream.xml.gz",
-		"/var/lib/flatpak/appstream/flathub/armhf/appstream.xml.gz",
-		"/var/lib/flatpak/appstream/flathub/appstream.xml.gz",
-		// Unpacked versions
-		"/var/lib/flatpak/appstream/flathub/x86_64/appstream.xml",
-		"/var/lib/flatpak/appstream/flathub/appstream.xml",
-	)
-
-	return paths
+	if xdgData == "" {
+		home := os.Getenv("HOME")
+		if home == "" {
+			return ""
+		}
+		xdgData = filepath.Join(home, ".local", "share")
+	}
+	return filepath.Join(xdgData, "flatpak")
+}
+
+// SystemInstallationDir is the system-wide Flatpak installation, overridden
+// by FLATPAK_SYSTEM_DIR.
+func SystemInstallationDir() string {
+	if dir := os.Getenv("FLATPAK_SYSTEM_DIR"); dir != "" {
+		return dir
+	}
+	return "/var/lib/flatpak"
+}
+
+// appstreamFileNames are tried in order inside an arch directory. Flatpak
+// deploys the current commit under active/; older versions wrote the files
+// next to it.
+var appstreamFileNames = []string{
+	"active/appstream.xml.gz",
+	"active/appstream.xml",
+	"appstream.xml.gz",
+	"appstream.xml",
+}
+
+// FindSources lists the appstream file of every remote and architecture
+// under <installation>/appstream/<remote>/<arch>, user installation first.
+// Remotes are sorted by name and the native architecture comes first
+// within each remote.
+func FindSources() []Source {
+	installations := []struct {
+		name string
+		dir  string
+	}{
+		{"user", UserInstallationDir()},
+		{"system", SystemInstallationDir()},
+	}
+
+	var sources []Source
+	for _, inst := range installations {
+		if inst.dir == "" {
+			continue
+		}
+		base := filepath.Join(inst.dir, "appstream")
+		for _, remote := range subdirs(base) {
+			sources = append(sources, remoteSources(inst.name, filepath.Join(base, remote), remote)...)
+		}
+	}
+	return sources
+}
+
+func remoteSources(installation, dir, remote string) []Source {
+	arches := subdirs(dir)
+	native := NativeArch()
+	sort.SliceStable(arches, func(i, j int) bool {
+		return arches[i] == native && arches[j] != native
+	})
+
+	var sources []Source
+	for _, arch := range arches {
+		if path := findAppStreamFile(filepath.Join(dir, arch)); path != "" {
+			sources = append(sources, Source{Path: path, Remote: remote, Arch: arch, Installation: installation})
+		}
+	}
+	if len(sources) == 0 {
+		if path := findAppStreamFile(dir); path != "" {
+			sources = append(sources, Source{Path: path, Remote: remote, Installation: installation})
+		}
+	}
+	return sources
+}
+
+func findAppStreamFile(dir string) string {
+	for _, name := range appstreamFileNames {
+		path := filepath.Join(dir, name)
+		if info, err := os.Stat(path); err == nil && info.Mode().IsRegular() {
+			return path
+		}
+	}
+	return ""
+}
+
+// subdirs returns the sorted names of the directories in dir, following
+// symlinks. Hidden entries such as .timestamp are skipped.
+func subdirs(dir string) []string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		if info, err := os.Stat(filepath.Join(dir, entry.Name())); err == nil && info.IsDir() {
+			names = append(names, entry.Name())
+		}
+	}
+	return names
+}
+
+// NativeArch is the Flatpak name of the running architecture.
+func NativeArch() string {
+	switch runtime.GOARCH {
+	case "amd64":
+		return "x86_64"
+	case "386":
+		return "i386"
+	case "arm64":
+		return "aarch64"
+	case "arm":
+		return "arm"
+	default:
+		return runtime.GOARCH
+	}
 }
 
 // CacheAge returns the age of the cache file at the given path.
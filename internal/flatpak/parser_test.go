@@ -0,0 +1,92 @@
+package flatpak
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func parseFixture(t *testing.T) []App {
+	t.Helper()
+	file, err := os.Open(filepath.Join("..", "..", "tests", "fixtures", "flatpak-appstream.xml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	apps, err := ParseAppStream(file)
+	if err != nil {
+		t.Fatalf("ParseAppStream: %v", err)
+	}
+	return apps
+}
+
+func TestParseAppStreamRichFields(t *testing.T) {
+	apps := parseFixture(t)
+	if len(apps) != 2 {
+		t.Fatalf("parsed %d apps, want the two desktop applications", len(apps))
+	}
+
+	want := App{
+		ID:            "org.gnome.Chess",
+		Name:          "Chess",
+		Summary:       "Play the classic two-player board game of chess",
+		Description:   "GNOME Chess is a simple chess game.",
+		Version:       "46.0",
+		Origin:        "flathub-gnome",
+		Categories:    []string{"Game", "BoardGame"},
+		Keywords:      []string{"board", "strategy"},
+		Developer:     "The GNOME Project",
+		License:       "GPL-3.0+",
+		Homepage:      "https://wiki.gnome.org/Apps/Chess",
+		BugTracker:    "https://gitlab.gnome.org/GNOME/gnome-chess/issues",
+		ContentRating: map[string]string{"social-chat": "mild"},
+		Releases: []Release{
+			{Version: "46.0", Date: "2024-03-21"},
+			{Version: "45.0", Date: "2023-09-15"},
+		},
+		Icons: []Icon{
+			{Type: "cached", Value: "org.gnome.Chess.png", Width: 64, Height: 64},
+			{Type: "remote", Value: "https://dl.flathub.org/media/org/gnome/Chess/icons/128x128/org.gnome.Chess.png", Width: 128, Height: 128},
+		},
+	}
+	if !reflect.DeepEqual(apps[0], want) {
+		t.Fatalf("app = %+v\nwant %+v", apps[0], want)
+	}
+
+	legacy := apps[1]
+	if legacy.Developer != "Example Inc." {
+		t.Fatalf("developer_name = %q", legacy.Developer)
+	}
+	if !reflect.DeepEqual(legacy.Keywords, []string{"old"}) {
+		t.Fatalf("keywords = %v, want only the untranslated block", legacy.Keywords)
+	}
+	if legacy.Version != "" || legacy.Releases != nil {
+		t.Fatalf("release data = %q %v, want none", legacy.Version, legacy.Releases)
+	}
+}
+
+func TestFilterMatchesKeywordsAndCategories(t *testing.T) {
+	cache := &Cache{Apps: parseFixture(t)}
+
+	names := func(rows []SearchResult) []string {
+		out := make([]string, 0, len(rows))
+		for _, row := range rows {
+			out = append(out, row.Name)
+		}
+		return out
+	}
+
+	if got := names(cache.Filter("strategy")); !reflect.DeepEqual(got, []string{"org.gnome.Chess"}) {
+		t.Fatalf("keyword search = %v", got)
+	}
+	if got := names(cache.Filter("category:game")); !reflect.DeepEqual(got, []string{"org.gnome.Chess"}) {
+		t.Fatalf("category filter = %v", got)
+	}
+	if got := names(cache.Filter("category:Game legacy")); len(got) != 0 {
+		t.Fatalf("category filter with text = %v, want none", got)
+	}
+	if got := names(cache.Filter("category:")); len(got) != 2 {
+		t.Fatalf("empty category term = %v, want every app", got)
+	}
+}
@@ -0,0 +1,91 @@
+package flatpak
+
+import (
+	"crypto/sha256"
+	"encoding/gob"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+)
+
+// preparsedVersion is bumped whenever App changes shape, so caches written
+// by an older build are parsed again instead of decoded into the wrong
+// fields.
+const preparsedVersion = 1
+
+// PreparsedCacheDir returns the directory holding pre-parsed appstream
+// files, or "" to always parse the XML. The fpf binary points it at its
+// cache root.
+var PreparsedCacheDir = func() string { return "" }
+
+// preparsed is the serialized form of one parsed appstream file. It is
+// valid while the source keeps the recorded size and modification time.
+type preparsed struct {
+	Version int
+	Source  string
+	ModTime int64
+	Size    int64
+	Apps    []App
+}
+
+func preparsedPath(dir, source string) string {
+	sum := sha256.Sum256([]byte(source))
+	return filepath.Join(dir, hex.EncodeToString(sum[:8])+".gob")
+}
+
+// loadPreparsed returns the apps cached for source when the cache still
+// matches the file described by info.
+func loadPreparsed(source string, info os.FileInfo) ([]App, bool) {
+	dir := PreparsedCacheDir()
+	if dir == "" {
+		return nil, false
+	}
+	file, err := os.Open(preparsedPath(dir, source))
+	if err != nil {
+		return nil, false
+	}
+	defer file.Close()
+
+	var cached preparsed
+	if err := gob.NewDecoder(file).Decode(&cached); err != nil {
+		return nil, false
+	}
+	if cached.Version != preparsedVersion || cached.Source != source ||
+		cached.ModTime != info.ModTime().UnixNano() || cached.Size != info.Size() {
+		return nil, false
+	}
+	return cached.Apps, true
+}
+
+// storePreparsed writes apps for source, replacing any older cache
+// atomically. Failures only cost a reparse next time, so they are ignored.
+func storePreparsed(source string, info os.FileInfo, apps []App) {
+	dir := PreparsedCacheDir()
+	if dir == "" {
+		return
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return
+	}
+	tmp, err := os.CreateTemp(dir, ".appstream-*")
+	if err != nil {
+		return
+	}
+	err = gob.NewEncoder(tmp).Encode(preparsed{
+		Version: preparsedVersion,
+		Source:  source,
+		ModTime: info.ModTime().UnixNano(),
+		Size:    info.Size(),
+		Apps:    apps,
+	})
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return
+	}
+	if err := os.Rename(tmp.Name(), preparsedPath(dir, source)); err != nil {
+		os.Remove(tmp.Name())
+	}
+}
@@ -0,0 +1,81 @@
+package flatpak
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLoadFromFileUsesPreparsedCache(t *testing.T) {
+	cacheDir := t.TempDir()
+	saved := PreparsedCacheDir
+	PreparsedCacheDir = func() string { return cacheDir }
+	t.Cleanup(func() { PreparsedCacheDir = saved })
+
+	source := filepath.Join(t.TempDir(), "appstream.xml")
+	doc := appstreamXMLFor("org.example.App", "org.example.Other")
+	writeAppStream(t, source, doc, false)
+	stamp := time.Now().Add(-time.Hour).Truncate(time.Second)
+	if err := os.Chtimes(source, stamp, stamp); err != nil {
+		t.Fatal(err)
+	}
+
+	apps, err := loadFromFile(source)
+	if err != nil || len(apps) != 2 {
+		t.Fatalf("first load = %v, %v", apps, err)
+	}
+	entries, _ := os.ReadDir(cacheDir)
+	if len(entries) != 1 {
+		t.Fatalf("cache dir holds %d entries, want one pre-parsed file", len(entries))
+	}
+
+	// Same size and mtime: the cached apps are used without reading the XML.
+	if err := os.WriteFile(source, bytes.Repeat([]byte("x"), len(doc)), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(source, stamp, stamp); err != nil {
+		t.Fatal(err)
+	}
+	apps, err = loadFromFile(source)
+	if err != nil || len(apps) != 2 || apps[0].ID != "org.example.App" {
+		t.Fatalf("cached load = %v, %v", apps, err)
+	}
+
+	// A new mtime invalidates the cache, so the garbage is parsed and fails.
+	if err := os.Chtimes(source, stamp.Add(time.Minute), stamp.Add(time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loadFromFile(source); err == nil {
+		t.Fatal("expected a changed source to be parsed again")
+	}
+}
+
+func TestScanAppStreamStreamsDesktopApps(t *testing.T) {
+	doc := `<components origin="flathub">
+  <component type="runtime"><id>org.gnome.Platform</id><name>Platform</name></component>
+  <component type="desktop-application"><id>org.example.One</id><name>One</name></component>
+  <component type="addon"><id>org.example.One.Plugin</id></component>
+  <component type="desktop-application"><name>No id</name></component>
+  <component type="desktop-application"><id>org.example.Two</id><name>Two</name></component>
+</components>`
+
+	var ids []string
+	err := ScanAppStream(bytes.NewReader([]byte(doc)), func(app App) {
+		ids = append(ids, app.ID+"@"+app.Origin)
+	})
+	if err != nil {
+		t.Fatalf("ScanAppStream: %v", err)
+	}
+	if len(ids) != 2 || ids[0] != "org.example.One@flathub" || ids[1] != "org.example.Two@flathub" {
+		t.Fatalf("emitted %v", ids)
+	}
+
+	if _, err := ParseAppStream(bytes.NewReader([]byte("<html></html>"))); err == nil {
+		t.Fatal("expected a non-appstream document to fail")
+	}
+	if _, err := ParseAppStream(bytes.NewReader([]byte(`<components><component type="desktop-application"><id>x`))); err == nil {
+		t.Fatal("expected a truncated document to fail")
+	}
+}
@@ -6,26 +6,65 @@ import (
 
 // App represents a Flatpak application from the appstream metadata.
 type App struct {
-	ID          string `json:"id"`
-	Name        string `json:"name"`
-	Summary     string `json:"summary"`
-	Description string `json:"description,omitempty"`
-	Version     string `json:"version,omitempty"`
-	Origin      string `json:"origin"`
+	ID            string            `json:"id"`
+	Name          string            `json:"name"`
+	Summary       string            `json:"summary"`
+	Description   string            `json:"description,omitempty"`
+	Version       string            `json:"version,omitempty"`
+	Origin        string            `json:"origin"`
+	Categories    []string          `json:"categories,omitempty"`
+	Keywords      []string          `json:"keywords,omitempty"`
+	Developer     string            `json:"developer,omitempty"`
+	License       string            `json:"license,omitempty"`
+	Homepage      string            `json:"homepage,omitempty"`
+	BugTracker    string            `json:"bugtracker,omitempty"`
+	ContentRating map[string]string `json:"content_rating,omitempty"`
+	Releases      []Release         `json:"releases,omitempty"`
+	Icons         []Icon            `json:"icons,omitempty"`
 }
 
-// Cache holds parsed Flatpak appstream data.
+// Release is one entry of an app's release history, newest first.
+type Release struct {
+	Version string `json:"version"`
+	Date    string `json:"date,omitempty"`
+}
+
+// Icon is an app icon. Value is a file name for cached icons (resolved to a
+// path once the source is known), a URL for remote icons, or a theme name
+// for stock icons.
+type Icon struct {
+	Type   string `json:"type"`
+	Value  string `json:"value"`
+	Width  int    `json:"width,omitempty"`
+	Height int    `json:"height,omitempty"`
+}
+
+// Source is one appstream file: the catalog a remote publishes for one
+// architecture, in the user or system installation.
+type Source struct {
+	Path         string
+	Remote       string
+	Arch         string
+	Installation string
+}
+
+// Cache holds parsed Flatpak appstream data merged from every source.
 type Cache struct {
 	Apps     []App
 	LoadedAt time.Time
-	Path     string
-	Origin   string
+	Sources  []Source
+
+	// primary maps each app id to the first remote that lists it.
+	primary map[string]string
 }
 
-// SearchResult represents a single search result entry.
+// SearchResult represents a single search result entry. Score is the
+// match tier for the query (MatchExact is best); it is zero when the query
+// had no text to rank by.
 type SearchResult struct {
-	Name string
-	Desc string
+	Name  string
+	Desc  string
+	Score int
 }
 // This mirrors the searchRow type from the main package.
 type searchRow struct {
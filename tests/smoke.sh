@@ -293,6 +293,9 @@ APT_DUMP
             -Q)
                 printf "pacpkg 1.0\n"
                 ;;
+            -Qm)
+                printf "aurpkg 1.0-1\n"
+                ;;
             -Si|-Qi)
                 printf "Name            : %s\n" "${2:-pacpkg}"
                 ;;
@@ -301,6 +304,22 @@ APT_DUMP
                 ;;
         esac
         ;;
+    paru|yay)
+        case "${1:-}" in
+            -Ssa)
+                if fixture_enabled && ! print_fixture "aur-search.txt"; then
+                    printf "aur/aurpkg 1.0-1 [+1 ~0.00]\n"
+                    printf "    Aur package\n"
+                elif ! fixture_enabled; then
+                    printf "aur/aurpkg 1.0-1 [+1 ~0.00]\n"
+                    printf "    Aur package\n"
+                fi
+                ;;
+            -Sia)
+                printf "Repository      : aur\nName            : %s\n" "${2:-aurpkg}"
+                ;;
+        esac
+        ;;
     zypper)
         args=" $* "
         if [[ "${args}" == *" search "* && "${args}" == *" --installed-only "* ]]; then
@@ -324,6 +343,45 @@ APT_DUMP
     qlist)
         printf "app-editors/emepkg\n"
         ;;
+    apk)
+        case "${1:-}" in
+            search)
+                if fixture_enabled && ! print_fixture "apk-search.txt"; then
+                    printf "apkpkg-1.0-r0 - Apk package\n"
+                elif ! fixture_enabled; then
+                    printf "apkpkg-1.0-r0 - Apk package\n"
+                fi
+                ;;
+            info)
+                if [[ "${2:-}" == "-vv" ]]; then
+                    printf "apkpkg-1.0-r0 - Apk package\n"
+                else
+                    printf "%s-1.0-r0 description:\nApk package\n" "${!#}"
+                fi
+                ;;
+        esac
+        ;;
+    xbps-query)
+        case "${1:-}" in
+            -Rs)
+                if fixture_enabled && ! print_fixture "xbps-search.txt"; then
+                    printf "[-] xbpspkg-1.0_1    Xbps package\n"
+                elif ! fixture_enabled; then
+                    printf "[-] xbpspkg-1.0_1    Xbps package\n"
+                fi
+                ;;
+            -l)
+                if fixture_enabled && ! print_fixture "xbps-installed.txt"; then
+                    printf "ii xbpspkg-1.0_1    Xbps package\n"
+                elif ! fixture_enabled; then
+                    printf "ii xbpspkg-1.0_1    Xbps package\n"
+                fi
+                ;;
+            -R)
+                printf "pkgver: %s-1.0_1\n" "${2:-xbpspkg}"
+                ;;
+        esac
+        ;;
     brew)
         case "${1:-}" in
             --repository)
@@ -388,6 +446,16 @@ APT_DUMP
                     fi
                 fi
                 ;;
+            tap)
+                if [[ -z "${2:-}" ]]; then
+                    printf "homebrew/cask-fonts\n"
+                fi
+                ;;
+            tap-info)
+                if [[ "${2:-}" == "--json" ]]; then
+                    printf '[{"name":"homebrew/cask-fonts","formula_names":[],"cask_tokens":["font-fira-code"],"remote":"https://github.com/Homebrew/homebrew-cask-fonts"}]\n'
+                fi
+                ;;
             list)
                 if [[ "${2:-}" == "--versions" ]]; then
                     if fixture_enabled && ! print_fixture "brew-installed.txt"; then
@@ -398,7 +466,13 @@ APT_DUMP
                 fi
                 ;;
             info)
-                if [[ "${FPF_TEST_MULTI_TOKEN:-0}" == "1" ]]; then
+                if [[ "${2:-}" == "--json=v2" ]]; then
+                    if [[ " $* " == *" ripgrep-beta "* ]]; then
+                        printf '{"formulae":[],"casks":[{"token":"ripgrep-beta","full_token":"ripgrep-beta"}]}\n'
+                    else
+                        printf '{"formulae":[],"casks":[]}\n'
+                    fi
+                elif [[ "${FPF_TEST_MULTI_TOKEN:-0}" == "1" ]]; then
                     pkg="${!#}"
                     if [[ "${pkg}" == "claude-code" ]]; then
                         printf "claude-code: stable 1.0\n"
@@ -575,6 +649,39 @@ APT_DUMP
                 ;;
         esac
         ;;
+    nix)
+        if [[ "${1:-}" == "--extra-experimental-features" ]]; then
+            shift 2
+        fi
+        case "${1:-} ${2:-}" in
+            search\ *)
+                if fixture_enabled && ! print_fixture "nix-search.json"; then
+                    printf '{"legacyPackages.x86_64-linux.nixpkg":{"description":"Nix package","pname":"nixpkg","version":"1.0"}}\n'
+                elif ! fixture_enabled; then
+                    printf '{"legacyPackages.x86_64-linux.nixpkg":{"description":"Nix package","pname":"nixpkg","version":"1.0"}}\n'
+                fi
+                ;;
+            "profile list")
+                if [[ "${3:-}" == "--json" ]]; then
+                    if [[ "${FPF_TEST_NIX_PROFILE_JSON_FAIL:-0}" == "1" ]]; then
+                        exit 1
+                    fi
+                    if fixture_enabled && ! print_fixture "nix-profile-list.json"; then
+                        printf '{"elements":{"nixpkg":{"attrPath":"legacyPackages.x86_64-linux.nixpkg"}},"version":3}\n'
+                    elif ! fixture_enabled; then
+                        printf '{"elements":{"nixpkg":{"attrPath":"legacyPackages.x86_64-linux.nixpkg"}},"version":3}\n'
+                    fi
+                elif fixture_enabled && ! print_fixture "nix-profile-list.txt"; then
+                    printf "Flake attribute:    legacyPackages.x86_64-linux.nixpkg\n"
+                elif ! fixture_enabled; then
+                    printf "Flake attribute:    legacyPackages.x86_64-linux.nixpkg\n"
+                fi
+                ;;
+            eval\ *)
+                printf '{"description":"Nix package"}\n'
+                ;;
+        esac
+        ;;
     npm)
         case "${1:-}" in
             search)
@@ -706,6 +813,128 @@ APT_DUMP
                 ;;
         esac
         ;;
+    pnpm)
+        case "${1:-}" in
+            ls)
+                printf '[{"path":"/home/tester/.local/share/pnpm/global/5","dependencies":{"npmpkg":{"version":"1.0.0"}}}]\n'
+                ;;
+            add|remove|update)
+                ;;
+        esac
+        ;;
+    yarn)
+        case "${1:-}" in
+            --version)
+                printf "1.22.22\n"
+                ;;
+            global)
+                case "${2:-}" in
+                    dir)
+                        exit 1
+                        ;;
+                    list)
+                        printf "yarn global v1.22.22\n"
+                        printf 'info "npmpkg@1.0.0" has binaries:\n'
+                        printf "   - npmpkg\n"
+                        ;;
+                esac
+                ;;
+            info)
+                printf "name: %s\n" "${2:-npmpkg}"
+                ;;
+        esac
+        ;;
+    gem)
+        case "${1:-}" in
+            search)
+                printf "\n*** REMOTE GEMS ***\n\n"
+                printf "gempkg (1.0.0)\n"
+                ;;
+            list)
+                printf "\n*** LOCAL GEMS ***\n\n"
+                printf "gempkg (1.0.0)\n"
+                ;;
+            env)
+                printf "%s\n" "${GEM_HOME:-}"
+                ;;
+        esac
+        ;;
+    mise)
+        case "${1:-}" in
+            ls)
+                printf '{"misepkg":[{"version":"1.0.0","installed":true,"active":true}]}\n'
+                ;;
+            ls-remote)
+                printf "1.0.0\n"
+                ;;
+            registry)
+                printf "misepkg                       aqua:example/misepkg\n"
+                printf "sample-query                  aqua:example/sample-query\n"
+                ;;
+        esac
+        ;;
+    asdf)
+        case "${1:-}" in
+            list)
+                if [[ "${2:-}" == "all" ]]; then
+                    printf "1.0.0\n"
+                else
+                    printf "misepkg\n *1.0.0\n"
+                fi
+                ;;
+            plugin)
+                if [[ "${2:-}" == "list" ]]; then
+                    printf "misepkg                       *https://github.com/example/asdf-misepkg.git\n"
+                    printf "sample-query                  *https://github.com/example/asdf-sample-query.git\n"
+                fi
+                ;;
+        esac
+        ;;
+    cargo)
+        case "${1:-}" in
+            search)
+                if fixture_enabled && ! print_fixture "cargo-search.txt"; then
+                    printf 'cargopkg = "1.1.0"    # Cargo package\n'
+                elif ! fixture_enabled; then
+                    printf 'cargopkg = "1.1.0"    # Cargo package\n'
+                fi
+                ;;
+            info)
+                printf "%s\nversion: 1.1.0\n" "${2:-cargopkg}"
+                ;;
+        esac
+        ;;
+    pipx)
+        case "${1:-}" in
+            list)
+                if fixture_enabled && ! print_fixture "pipx-list.json"; then
+                    printf '{"venvs":{"pipxpkg":{"metadata":{"main_package":{"package":"pipxpkg"}}}}}\n'
+                elif ! fixture_enabled; then
+                    printf '{"venvs":{"pipxpkg":{"metadata":{"main_package":{"package":"pipxpkg"}}}}}\n'
+                fi
+                ;;
+            runpip)
+                printf "Name: %s\nVersion: 1.0\n" "${2:-pipxpkg}"
+                ;;
+        esac
+        ;;
+    go)
+        case "${1:-}" in
+            env)
+                printf "%s\n\n" "${FPF_TEST_GOBIN:-}"
+                ;;
+            version)
+                if [[ "${2:-}" == "-m" ]]; then
+                    printf "%s/gopkg: go1.23.3\n" "${FPF_TEST_GOBIN:-/tmp}"
+                    printf "\tpath\texample.com/gopkg\n"
+                    printf "\tmod\texample.com/gopkg\tv1.0.0\th1:x\n"
+                fi
+                ;;
+            list)
+                printf '{"Path":"%s","Version":"v1.1.0"}\n' "${!#%@latest}"
+                ;;
+        esac
+        ;;
     fpf-refresh-signal)
         if [[ -n "${FPF_TEST_CACHE_REFRESH_SIGNAL_FILE:-}" ]]; then
             printf "refresh-complete\n" >>"${FPF_TEST_CACHE_REFRESH_SIGNAL_FILE}"
@@ -715,6 +944,13 @@ APT_DUMP
         if [[ "${FPF_TEST_CURL_FAIL:-0}" == "1" ]]; then
             exit 7
         fi
+        if [[ "$*" == *"https://pypi.org/pypi/"* ]]; then
+            if fixture_enabled && ! print_fixture "pypi-project.json"; then
+                printf '{"info":{"name":"pipxpkg","summary":"Pipx package","version":"1.0"}}\n'
+            elif ! fixture_enabled; then
+                printf '{"info":{"name":"pipxpkg","summary":"Pipx package","version":"1.0"}}\n'
+            fi
+        fi
         ;;
     nc)
         if [[ "${FPF_TEST_NC_FAIL:-0}" == "1" ]]; then
@@ -727,7 +963,7 @@ EOF
 
 chmod +x "${MOCK_BIN}/mockcmd"
 
-for cmd in uname sudo fzf apt-cache dpkg-query dpkg apt-get dnf rpm pacman zypper emerge qlist brew winget choco scoop snap flatpak npm bun fpf-refresh-signal curl nc; do
+for cmd in uname sudo fzf apt-cache dpkg-query dpkg apt-get dnf rpm pacman paru zypper emerge qlist apk xbps-query xbps-install xbps-remove brew winget choco scoop snap flatpak nix npm bun pnpm yarn cargo gem pipx go mise asdf fpf-refresh-signal curl nc; do
     ln -s "${MOCK_BIN}/mockcmd" "${MOCK_BIN}/${cmd}"
 done
 
@@ -736,6 +972,20 @@ export FPF_TEST_LOG="${LOG_FILE}"
 export FPF_TEST_MOCK_BIN="${MOCK_BIN}"
 export FPF_TEST_MOCKCMD_PATH="${MOCK_BIN}/mockcmd"
 export FPF_TEST_FIXTURE_DIR="${FIXTURE_DIR}"
+export FPF_TEST_GOBIN="${TMP_DIR}/gobin"
+mkdir -p "${FPF_TEST_GOBIN}"
+export CARGO_HOME="${TMP_DIR}/cargo-home"
+mkdir -p "${CARGO_HOME}"
+export GEM_HOME="${TMP_DIR}/gem-home"
+export HOMEBREW_CACHE="${TMP_DIR}/homebrew-cache"
+export FPF_APT_ROOT="${TMP_DIR}/apt-root"
+export FPF_PACMAN_ROOT="${TMP_DIR}/pacman-root"
+export FPF_DNF_ROOT="${TMP_DIR}/dnf-root"
+export FPF_SNAPD_SOCKET="${TMP_DIR}/snapd.socket"
+export FLATPAK_USER_DIR="${TMP_DIR}/flatpak-user"
+export FLATPAK_SYSTEM_DIR="${TMP_DIR}/flatpak-system"
+mkdir -p "${GEM_HOME}"
+printf '{"installs":{"cargopkg 1.0.0 (registry+https://github.com/rust-lang/crates.io-index)":{"bins":["cargopkg"]}}}\n' >"${CARGO_HOME}/.crates2.json"
 rm -rf "${SUITE_CACHE_ROOT}"
 export FPF_CACHE_DIR="${SUITE_CACHE_ROOT}"
 
@@ -916,6 +1166,124 @@ run_refresh_test() {
     assert_contains "${expected}"
 }
 
+run_aur_helper_runs_without_sudo_test() {
+    reset_log
+    printf "y\n" | "${FPF_BIN}" --manager aur sample-query >/dev/null
+    assert_logged_exact "paru -S --aur --needed aurpkg"
+    assert_not_contains "sudo paru"
+
+    reset_log
+    printf "y\n" | "${FPF_BIN}" --manager yay -R sample-query >/dev/null
+    assert_contains "pacman -Qm"
+    assert_logged_exact "paru -Rsn aurpkg"
+    assert_not_contains "sudo paru"
+}
+
+run_pipx_installed_marker_test() {
+    local output=""
+    local cache_root="${TMP_DIR}/cache-root-pipx-marker"
+
+    reset_log
+    rm -rf "${cache_root}"
+    output="$(FPF_CACHE_DIR="${cache_root}" "${FPF_BIN}" --manager pipx --feed-search -- pipxpkg)"
+    assert_output_contains "${output}" $'pipx\tpipxpkg\t* '
+    assert_contains "pipx list --json"
+}
+
+run_go_search_install_test() {
+    reset_log
+    printf "y\n" | "${FPF_BIN}" --manager go gopkg >/dev/null
+    assert_logged_exact "go install example.com/gopkg@latest"
+
+    reset_log
+    printf "y\n" | "${FPF_BIN}" --manager go example.com/newtool/cmd/newtool >/dev/null
+    assert_contains "go list -m -json example.com/newtool/cmd/newtool@latest"
+    assert_logged_exact "go install example.com/newtool/cmd/newtool@latest"
+}
+
+run_go_remove_binary_test() {
+    reset_log
+    : >"${FPF_TEST_GOBIN}/gopkg"
+    printf "y\n" | "${FPF_BIN}" --manager go -R sample-query >/dev/null
+    assert_contains "go version -m ${FPF_TEST_GOBIN}"
+    if [[ -e "${FPF_TEST_GOBIN}/gopkg" ]]; then
+        printf "Expected go -R to delete %s\n" "${FPF_TEST_GOBIN}/gopkg" >&2
+        exit 1
+    fi
+}
+
+run_gem_user_install_test() {
+    reset_log
+    printf "y\n" | GEM_HOME="${TMP_DIR}/gem-home-missing" "${FPF_BIN}" --manager gem sample-query >/dev/null
+    assert_logged_exact "gem install --user-install gempkg"
+
+    reset_log
+    printf "y\n" | "${FPF_BIN}" --manager gem sample-query >/dev/null
+    assert_logged_exact "gem install gempkg"
+}
+
+run_mise_asdf_driver_test() {
+    reset_log
+    printf "y\n" | FPF_MISE_DRIVER=asdf "${FPF_BIN}" --manager mise sample-query >/dev/null
+    assert_logged_exact "asdf plugin list all"
+    assert_logged_exact "asdf list all sample-query"
+    assert_logged_exact "asdf install sample-query latest"
+
+    reset_log
+    printf "y\n" | FPF_MISE_DRIVER=asdf "${FPF_BIN}" --manager mise -R sample-query >/dev/null
+    assert_logged_exact "asdf uninstall misepkg 1.0.0"
+}
+
+run_linuxbrew_preferred_test() {
+    local first_update=""
+
+    reset_log
+    export FPF_TEST_UNAME="Linux"
+    printf "y\n" | FPF_LINUX_PREFER_BREW=1 "${FPF_BIN}" -U >/dev/null
+    unset FPF_TEST_UNAME
+
+    first_update="$(grep -m1 -E '^(brew|apt-get) ' "${LOG_FILE}" || true)"
+    if [[ "${first_update}" != "brew update" ]]; then
+        printf "Expected pinned brew to update first, got: %s\n" "${first_update}" >&2
+        exit 1
+    fi
+    assert_not_contains "sudo brew"
+}
+
+run_brew_cask_install_test() {
+    reset_log
+    printf "y\n" | "${FPF_BIN}" --manager brew ripgrep-beta >/dev/null
+    assert_logged_exact "brew install --cask ripgrep-beta"
+    assert_not_logged_exact "brew install ripgrep-beta"
+}
+
+run_brew_api_descriptions_test() {
+    local output=""
+    local brew_cache="${TMP_DIR}/homebrew-cache-api"
+
+    mkdir -p "${brew_cache}/api"
+    cp "${ROOT_DIR}/tests/fixtures/brew-api-formula.jws.json" "${brew_cache}/api/formula.jws.json"
+    cp "${ROOT_DIR}/tests/fixtures/brew-api-cask.jws.json" "${brew_cache}/api/cask.jws.json"
+
+    output="$(FPF_TEST_FIXTURES=1 HOMEBREW_CACHE="${brew_cache}" FPF_CACHE_DIR="${TMP_DIR}/cache-root-brew-api" "${FPF_BIN}" --manager brew --feed-search -- ripgrep)"
+    assert_output_contains "${output}" "[formula homebrew/core] Search tool like grep and The Silver Searcher (14.1.0, Unlicense)"
+}
+
+run_brew_tap_test() {
+    local output=""
+
+    output="$(${FPF_BIN} --manager brew-tap --feed-search -- fonts)"
+    assert_output_contains "${output}" $'brew-tap\thomebrew/cask-fonts\t'
+
+    reset_log
+    printf "y\n" | "${FPF_BIN}" --manager brew-tap hashicorp/tap >/dev/null
+    assert_logged_exact "brew tap hashicorp/tap"
+
+    reset_log
+    printf "y\n" | "${FPF_BIN}" --manager tap -R sample-query >/dev/null
+    assert_logged_exact "brew untap homebrew/cask-fonts"
+}
+
 run_manager_flag_parsing_robustness_test() {
     local output=""
 
@@ -1114,6 +1482,8 @@ run_macos_auto_update_test() {
     assert_contains "brew update"
     assert_contains "bun update"
     assert_not_contains "npm update -g"
+    assert_not_contains "pnpm update -g"
+    assert_not_contains "yarn global upgrade"
 }
 
 run_macos_auto_refresh_test() {
@@ -1479,8 +1849,12 @@ run_feed_search_manager_mix_test() {
 
 run_fixture_catalog_feed_search_test() {
     local output=""
+    local suite_cache_dir="${FPF_CACHE_DIR}"
 
     export FPF_TEST_FIXTURES="1"
+    # Catalog caches built from the non-fixture mocks would answer here.
+    export FPF_CACHE_DIR="${TMP_DIR}/cache-root-fixture-catalog"
+    rm -rf "${FPF_CACHE_DIR}"
 
     output="$(${FPF_BIN} --manager apt --feed-search -- ripgrep)"
     assert_output_contains "${output}" $'apt\tripgrep\t'
@@ -1493,7 +1867,28 @@ run_fixture_catalog_feed_search_test() {
     assert_output_contains "${output}" $'bun\tripgrep\t'
     assert_output_contains "${output}" $'bun\tripgrep-runner\t'
 
+    output="$(${FPF_BIN} --manager apk --feed-search -- ripgrep)"
+    assert_output_contains "${output}" $'apk\tripgrep\t'
+    assert_output_contains "${output}" $'apk\tripgrep-all\t'
+
+    output="$(${FPF_BIN} --manager xbps --feed-search -- ripgrep)"
+    assert_output_contains "${output}" $'xbps\tripgrep\t'
+    assert_output_contains "${output}" $'xbps\tvim-ripgrep\t'
+
+    output="$(${FPF_BIN} --manager aur --feed-search -- ripgrep)"
+    assert_output_contains "${output}" $'aur\tripgrep-git\t'
+    assert_output_contains "${output}" $'aur\tripgrep-all-bin\t'
+
+    output="$(${FPF_BIN} --manager cargo --feed-search -- ripgrep)"
+    assert_output_contains "${output}" $'cargo\tripgrep\t'
+    assert_output_contains "${output}" $'cargo\tripgrep_all\t'
+
+    output="$(${FPF_BIN} --manager nix --feed-search -- ripgrep)"
+    assert_output_contains "${output}" $'nix\tripgrep\t'
+    assert_output_contains "${output}" $'nix\tvimPlugins.vim-ripgrep\t'
+
     unset FPF_TEST_FIXTURES
+    export FPF_CACHE_DIR="${suite_cache_dir}"
 }
 
 run_feed_search_merged_tsv_contract_test() {
@@ -1648,7 +2043,7 @@ run_installed_cache_test() {
     FPF_CACHE_DIR="${cache_root}" "${FPF_BIN}" --manager brew --feed-search -- other-query >/dev/null
 
     assert_file_contains "${cache_root}/go-installed/brew.txt" "brewpkg"
-    assert_file_contains "${cache_root}/go-installed/brew.meta" "format_version=1"
+    assert_file_contains "${cache_root}/go-installed/brew.meta" "format_version=2"
     assert_file_contains "${cache_root}/go-installed/brew.meta" "created_at="
     assert_file_contains "${cache_root}/go-installed/brew.meta" "fingerprint=brew|"
     assert_file_contains "${cache_root}/go-installed/brew.meta" "item_count=1"
@@ -1793,6 +2188,115 @@ EOF
     fi
 }
 
+run_apt_native_lists_catalog_test() {
+    local cache_root="${TMP_DIR}/cache-root-apt-lists"
+    local apt_root="${TMP_DIR}/apt-root-lists"
+    local lists="${apt_root}/var/lib/apt/lists"
+    local output=""
+
+    reset_log
+    rm -rf "${cache_root}" "${apt_root}"
+    mkdir -p "${lists}"
+    cp "${FIXTURE_DIR}/apt-lists-InRelease.txt" "${lists}/deb.example.org_debian_dists_bookworm_InRelease"
+    cp "${FIXTURE_DIR}/apt-lists-Packages.txt" "${lists}/deb.example.org_debian_dists_bookworm_main_binary-amd64_Packages"
+    printf 'Origin: Debian\nCodename: bookworm-updates\n' >"${lists}/deb.example.org_debian_dists_bookworm-updates_Release"
+    gzip -c "${FIXTURE_DIR}/apt-lists-updates-Packages.txt" >"${lists}/deb.example.org_debian_dists_bookworm-updates_main_binary-amd64_Packages.gz"
+
+    output="$(FPF_APT_ROOT="${apt_root}" FPF_CACHE_DIR="${cache_root}" "${FPF_BIN}" --manager apt --feed-search -- ripgrep)"
+    assert_output_contains "${output}" $'apt\tripgrep\t  [bookworm-updates/utils] Recursively searches directories for a regex pattern (13.0.0-4+deb12u1, 4.5 MB)'
+    assert_not_logged_exact "apt-cache dumpavail"
+
+    cat >>"${lists}/deb.example.org_debian_dists_bookworm_main_binary-amd64_Packages" <<'EOF'
+Package: ripgrep-extra
+Version: 0.1-1
+Description: Package added by apt update
+
+EOF
+    touch -d "+1 hour" "${lists}/deb.example.org_debian_dists_bookworm_main_binary-amd64_Packages"
+
+    output="$(FPF_APT_ROOT="${apt_root}" FPF_CACHE_DIR="${cache_root}" "${FPF_BIN}" --manager apt --feed-search -- ripgrep)"
+    assert_output_contains "${output}" $'apt\tripgrep-extra\t'
+}
+
+run_apt_dpkg_status_list_test() {
+    local cache_root="${TMP_DIR}/cache-root-dpkg-status"
+    local apt_root="${TMP_DIR}/apt-root-dpkg"
+
+    reset_log
+    rm -rf "${cache_root}" "${apt_root}"
+    mkdir -p "${apt_root}/var/lib/dpkg" "${apt_root}/var/lib/apt"
+    cp "${FIXTURE_DIR}/dpkg-status.txt" "${apt_root}/var/lib/dpkg/status"
+    cp "${FIXTURE_DIR}/apt-extended-states.txt" "${apt_root}/var/lib/apt/extended_states"
+
+    FPF_APT_ROOT="${apt_root}" FPF_CACHE_DIR="${cache_root}" "${FPF_BIN}" --manager apt -l >/dev/null
+    assert_contains "apt-cache show adduser"
+    assert_not_contains "dpkg-query"
+    assert_file_contains "${cache_root}/go-installed/apt.txt" $'adduser\t3.134 (all, auto)'
+    assert_file_contains "${cache_root}/go-installed/apt.txt" $'libc6:armel\t2.36-9+deb12u10 (armel, manual)'
+}
+
+run_pacman_sync_db_catalog_test() {
+    local cache_root="${TMP_DIR}/cache-root-pacman-sync"
+    local pacman_root="${TMP_DIR}/pacman-root-sync"
+    local staging="${TMP_DIR}/pacman-sync-staging"
+    local output=""
+
+    reset_log
+    rm -rf "${cache_root}" "${pacman_root}" "${staging}"
+    mkdir -p "${pacman_root}/etc" "${pacman_root}/var/lib/pacman/sync" "${pacman_root}/var/lib/pacman/local/ripgrep-14.1.0-1" "${staging}/ripgrep-14.1.0-1"
+    cp "${FIXTURE_DIR}/pacman.conf" "${pacman_root}/etc/pacman.conf"
+    cp "${FIXTURE_DIR}/pacman-desc.txt" "${staging}/ripgrep-14.1.0-1/desc"
+    cp "${FIXTURE_DIR}/pacman-desc.txt" "${pacman_root}/var/lib/pacman/local/ripgrep-14.1.0-1/desc"
+    tar -czf "${pacman_root}/var/lib/pacman/sync/core.db" -C "${staging}" ripgrep-14.1.0-1
+
+    output="$(FPF_PACMAN_ROOT="${pacman_root}" FPF_CACHE_DIR="${cache_root}" "${FPF_BIN}" --manager pacman --feed-search -- ripgrep)"
+    assert_output_contains "${output}" $'pacman\tripgrep\t* [core] A search tool that combines the usability of ag with the raw speed of grep (14.1.0-1, 4.5 MB)'
+    assert_not_contains "pacman -Ss"
+    assert_not_contains "pacman -Q"
+}
+
+run_dnf_primary_catalog_test() {
+    local cache_root="${TMP_DIR}/cache-root-dnf-primary"
+    local dnf_root="${TMP_DIR}/dnf-root-primary"
+    local repodata="${dnf_root}/var/cache/dnf/fedora-2d95c80a1fa0a67d/repodata"
+    local output=""
+
+    reset_log
+    rm -rf "${cache_root}" "${dnf_root}"
+    mkdir -p "${repodata}"
+    gzip -c "${FIXTURE_DIR}/dnf-primary.xml" >"${repodata}/0a1b2c-primary.xml.gz"
+
+    output="$(FPF_DNF_ROOT="${dnf_root}" FPF_CACHE_DIR="${cache_root}" "${FPF_BIN}" --manager dnf --feed-search -- ripgrep)"
+    assert_output_contains "${output}" $'dnf\tripgrep\t  [fedora] Line-oriented search tool (14.1.0-1.fc40'
+    assert_output_contains "${output}" $'dnf\tripgrep-doc\t  [fedora] Documentation for ripgrep (1:2.0-1.fc40, noarch)'
+    assert_not_contains "dnf -q list available"
+}
+
+run_flatpak_all_remotes_appstream_test() {
+    local cache_root="${TMP_DIR}/cache-root-flatpak-remotes"
+    local system_dir="${TMP_DIR}/flatpak-system-remotes"
+    local output=""
+
+    reset_log
+    rm -rf "${cache_root}" "${system_dir}"
+    mkdir -p "${system_dir}/appstream/flathub/x86_64/active" "${system_dir}/appstream/fedora/x86_64/active"
+    printf '<components origin="flatpak"><component type="desktop-application"><id>org.example.Writer</id><name>Writer</name><summary>Flathub writer</summary></component></components>\n' | gzip -c >"${system_dir}/appstream/flathub/x86_64/active/appstream.xml.gz"
+    printf '<components origin="fedora"><component type="desktop-application"><id>org.fedoraproject.MediaWriter</id><name>Media Writer</name><summary>Fedora media writer</summary></component></components>\n' >"${system_dir}/appstream/fedora/x86_64/active/appstream.xml"
+
+    output="$(FLATPAK_SYSTEM_DIR="${system_dir}" FPF_CACHE_DIR="${cache_root}" "${FPF_BIN}" --manager flatpak --feed-search -- writer)"
+    assert_output_contains "${output}" $'flatpak\torg.example.Writer\t'
+    assert_output_contains "${output}" $'flatpak\torg.fedoraproject.MediaWriter\t'
+    assert_not_contains "flatpak search"
+    if ! compgen -G "${cache_root}/flatpak-appstream/*.gob" >/dev/null; then
+        printf "Expected parsed appstream cache under %s/flatpak-appstream\n" "${cache_root}" >&2
+        exit 1
+    fi
+
+    reset_log
+    printf "y\n" | FLATPAK_SYSTEM_DIR="${system_dir}" FPF_CACHE_DIR="${cache_root}" "${FPF_BIN}" --manager flatpak mediawriter >/dev/null
+    assert_logged_exact "flatpak install -y --user fedora org.fedoraproject.MediaWriter"
+}
+
 run_search_catalog_async_prewarm_path_test() {
     local cache_root="${TMP_DIR}/cache-root-search-catalog-async"
     local apt_search_count=0
@@ -1868,7 +2372,7 @@ run_query_cache_layout_test() {
     fi
 
     assert_file_contains "${cache_file}" "sample-query"
-    assert_file_contains "${meta_file}" "format_version=1"
+    assert_file_contains "${meta_file}" "format_version=2"
     assert_file_contains "${meta_file}" "created_at="
     assert_file_contains "${meta_file}" "fingerprint=2|brew|"
     assert_file_contains "${meta_file}" "item_count="
@@ -2440,6 +2944,28 @@ run_flatpak_scope_fallback_test() {
     unset FPF_TEST_FLATPAK_USER_FAIL
 }
 
+run_flatpak_installation_scope_test() {
+    local system_dir="${TMP_DIR}/flatpak-system-installed"
+    local active="${system_dir}/app/org.example.Flat/x86_64/stable/active"
+
+    rm -rf "${system_dir}"
+    mkdir -p "${active}"
+    printf 'flathub\0' >"${active}/deploy"
+    printf '[Application]\nname=org.example.Flat\n' >"${active}/metadata"
+
+    reset_log
+    printf "y\n" | FLATPAK_SYSTEM_DIR="${system_dir}" "${FPF_BIN}" --manager flatpak -R sample-query >/dev/null
+    assert_logged_exact "flatpak uninstall -y --system org.example.Flat"
+    assert_not_logged_exact "flatpak uninstall -y --user org.example.Flat"
+
+    reset_log
+    printf "y\n" | FLATPAK_SYSTEM_DIR="${system_dir}" "${FPF_BIN}" --manager flatpak -U >/dev/null
+    assert_logged_exact "flatpak update -y --system"
+    assert_not_logged_exact "flatpak update -y --user"
+
+    rm -rf "${system_dir}"
+}
+
 run_flatpak_no_query_catalog_test() {
     reset_log
     printf "n\n" | "${FPF_BIN}" --manager flatpak >/dev/null
@@ -2563,6 +3089,8 @@ run_windows_auto_update_test() {
     assert_contains "scoop update"
     assert_contains "bun update"
     assert_not_contains "npm update -g"
+    assert_not_contains "pnpm update -g"
+    assert_not_contains "yarn global upgrade"
 }
 
 run_windows_auto_refresh_test() {
@@ -2700,6 +3228,8 @@ run_auto_detect_update_test() {
     assert_contains "brew update"
     assert_contains "bun update"
     assert_not_contains "npm update -g"
+    assert_not_contains "pnpm update -g"
+    assert_not_contains "yarn global upgrade"
 }
 
 run_auto_detect_refresh_test() {
@@ -2796,6 +3326,14 @@ run_list_test pacman "pacman -Qi"
 run_update_test pacman "pacman -Syu"
 run_refresh_test pacman "pacman -Sy"
 
+run_search_install_test aur "paru -S --aur --needed aurpkg"
+run_remove_test aur "paru -Rsn aurpkg"
+run_list_test aur "paru -Sia aurpkg"
+run_update_test aur "paru -Sua"
+run_refresh_test aur "paru -Sy"
+run_aur_helper_runs_without_sudo_test
+run_linuxbrew_preferred_test
+
 run_search_install_test zypper "zypper --non-interactive install --auto-agree-with-licenses"
 run_remove_test zypper "zypper --non-interactive remove"
 run_list_test zypper "zypper --non-interactive info"
@@ -2808,11 +3346,26 @@ run_list_test emerge "emerge --search --color=n"
 run_update_test emerge "emerge --sync"
 run_refresh_test emerge "emerge --sync"
 
+run_search_install_test apk "apk add apkpkg"
+run_remove_test apk "apk del apkpkg"
+run_list_test apk "apk info -a apkpkg"
+run_update_test apk "apk upgrade"
+run_refresh_test apk "apk update"
+
+run_search_install_test xbps "xbps-install -y xbpspkg"
+run_remove_test xbps "xbps-remove -R -y xbpspkg"
+run_list_test xbps "xbps-query -R xbpspkg"
+run_update_test xbps "xbps-install -Suy"
+run_refresh_test xbps "xbps-install -S"
+
 run_search_install_test brew "brew install"
 run_remove_test brew "brew uninstall"
 run_list_test brew "brew info"
 run_update_test brew "brew update"
 run_refresh_test brew "brew update"
+run_brew_cask_install_test
+run_brew_tap_test
+run_brew_api_descriptions_test
 
 run_search_install_test winget "winget install --id"
 run_remove_test winget "winget uninstall --id"
@@ -2844,6 +3397,12 @@ run_list_test flatpak "flatpak info"
 run_update_test flatpak "flatpak update -y --user"
 run_refresh_test flatpak "flatpak update -y --appstream --user"
 
+run_search_install_test nix "nix --extra-experimental-features nix-command flakes profile install nixpkgs#nixpkg"
+run_remove_test nix "nix --extra-experimental-features nix-command flakes profile remove nixpkg"
+run_list_test nix "nix --extra-experimental-features nix-command flakes eval --json nixpkgs#nixpkg.meta"
+run_update_test nix "nix --extra-experimental-features nix-command flakes profile upgrade --all"
+run_refresh_test nix "nix --extra-experimental-features nix-command flakes flake metadata --refresh nixpkgs"
+
 run_search_install_test npm "npm install -g"
 run_remove_test npm "npm uninstall -g"
 run_list_test npm "npm view"
@@ -2855,6 +3414,40 @@ run_remove_test bun "bun remove --global"
 run_list_test bun "bun info"
 run_update_test bun "bun update"
 run_refresh_test bun "bun pm cache"
+run_search_install_test cargo "cargo install cargopkg"
+run_remove_test cargo "cargo uninstall cargopkg"
+run_list_test cargo "cargo info cargopkg"
+run_update_test cargo "cargo install --force cargopkg"
+
+run_search_install_test pipx "pipx install pipxpkg"
+run_remove_test pipx "pipx uninstall pipxpkg"
+run_list_test pipx "pipx runpip pipxpkg show pipxpkg"
+run_update_test pipx "pipx upgrade-all"
+run_search_install_test pnpm "pnpm add -g npmpkg"
+run_remove_test pnpm "pnpm remove -g npmpkg"
+run_list_test pnpm "npm view npmpkg"
+run_update_test pnpm "pnpm update -g"
+run_search_install_test yarn "yarn global add npmpkg"
+run_remove_test yarn "yarn global remove npmpkg"
+run_list_test yarn "yarn info npmpkg"
+run_update_test yarn "yarn global upgrade"
+run_search_install_test gem "gem install gempkg"
+run_remove_test gem "gem uninstall --all --executables gempkg"
+run_list_test gem "gem search --remote --exact --details gempkg"
+run_update_test gem "gem update"
+run_search_install_test mise "mise install sample-query@latest"
+run_remove_test mise "mise uninstall misepkg@1.0.0"
+run_list_test mise "mise tool misepkg"
+run_update_test mise "mise upgrade"
+run_refresh_test mise "mise cache clear"
+run_pipx_installed_marker_test
+
+run_go_search_install_test
+run_list_test go "go version -m ${FPF_TEST_GOBIN}/gopkg"
+run_update_test go "go install example.com/gopkg@latest"
+run_go_remove_binary_test
+run_gem_user_install_test
+run_mise_asdf_driver_test
 run_assume_yes_bypasses_prompt_test
 run_confirm_mixed_case_yes_test
 run_manager_flag_parsing_robustness_test
@@ -2870,11 +3463,15 @@ run_auto_detect_update_test fedora "rhel fedora" "dnf upgrade -y"
 run_auto_detect_update_test arch arch "pacman -Syu"
 run_auto_detect_update_test opensuse-tumbleweed "suse opensuse" "zypper --non-interactive refresh"
 run_auto_detect_update_test gentoo gentoo "emerge --sync"
+run_auto_detect_update_test alpine "" "apk upgrade"
+run_auto_detect_update_test void "" "xbps-install -Suy"
 run_auto_detect_refresh_test ubuntu debian "apt-get update"
 run_auto_detect_refresh_test fedora "rhel fedora" "dnf makecache"
 run_auto_detect_refresh_test arch arch "pacman -Sy"
 run_auto_detect_refresh_test opensuse-tumbleweed "suse opensuse" "zypper --non-interactive refresh"
 run_auto_detect_refresh_test gentoo gentoo "emerge --sync"
+run_auto_detect_refresh_test alpine "" "apk update"
+run_auto_detect_refresh_test void "" "xbps-install -S"
 run_linux_auto_scope_test ubuntu debian "apt-cache dumpavail"
 
 run_macos_auto_scope_test
@@ -2900,16 +3497,27 @@ run_dynamic_reload_force_never_test "MINGW64_NT-10.0"
 run_dynamic_reload_override_test "apt"
 run_dynamic_reload_override_test "dnf"
 run_dynamic_reload_override_test "pacman"
+run_dynamic_reload_override_test "aur"
 run_dynamic_reload_override_test "zypper"
 run_dynamic_reload_override_test "emerge"
+run_dynamic_reload_override_test "apk"
+run_dynamic_reload_override_test "xbps"
 run_dynamic_reload_override_test "brew"
 run_dynamic_reload_override_test "bun"
 run_dynamic_reload_override_test "npm"
+run_dynamic_reload_override_test "cargo"
+run_dynamic_reload_override_test "pipx"
+run_dynamic_reload_override_test "pnpm"
+run_dynamic_reload_override_test "yarn"
+run_dynamic_reload_override_test "gem"
+run_dynamic_reload_override_test "mise"
+run_dynamic_reload_override_test "go"
 run_dynamic_reload_override_test "winget"
 run_dynamic_reload_override_test "choco"
 run_dynamic_reload_override_test "scoop"
 run_dynamic_reload_override_test "snap"
 run_dynamic_reload_override_test "flatpak"
+run_dynamic_reload_override_test "nix"
 run_dynamic_reload_query_cache_bypass_opt_out_test
 run_dynamic_reload_manager_parity_no_nested_exec_test
 run_fzf_ui_regression_guard_test
@@ -2918,6 +3526,12 @@ run_installed_cache_ttl_expiration_test
 run_apt_catalog_cache_rebuild_test
 run_brew_catalog_cache_rebuild_test
 run_apt_catalog_cache_invalidation_on_fixture_change_test
+run_apt_native_lists_catalog_test
+run_apt_dpkg_status_list_test
+run_pacman_sync_db_catalog_test
+run_dnf_primary_catalog_test
+run_flatpak_all_remotes_appstream_test
+run_flatpak_installation_scope_test
 run_search_catalog_async_prewarm_path_test
 run_search_catalog_async_prewarm_no_query_guard_test
 run_query_cache_layout_test